@@ -165,4 +165,10 @@ export interface FeatureToggles {
   logRowsPopoverMenu?: boolean;
   pluginsSkipHostEnvVars?: boolean;
   regressionTransformation?: boolean;
+  sqlExpressions?: boolean;
+  jitterAlertRules?: boolean;
+  alertingRuleSharding?: boolean;
+  livePipeline?: boolean;
+  dashboardRestore?: boolean;
+  forecastExpressions?: boolean;
 }
@@ -9,6 +9,7 @@ import {
   DataSourceJsonData,
   DataSourceRef,
   createDataFrame,
+  dateTime,
   AdHocVariableFilter,
   ScopedVars,
   getDefaultTimeRange,
@@ -407,6 +408,28 @@ describe('DataSourceWithBackend', () => {
     `);
   });
 
+  test('check that relative time ranges are flagged for the query cache', () => {
+    const { mock, ds } = createMockDatasource();
+    const request = {
+      maxDataPoints: 10,
+      intervalMs: 5000,
+      targets: [{ refId: 'A' }],
+      range: getDefaultTimeRange(),
+      requestId: 'request-123',
+      interval: '5s',
+      scopedVars: {},
+      timezone: '',
+      app: '',
+      startTime: 0,
+    };
+    ds.query({ ...request, rangeRaw: { from: 'now-6h', to: 'now' } });
+    ds.query({ ...request, rangeRaw: { from: dateTime(1697133600000), to: dateTime(1697155200000) } });
+
+    expect(mock.calls.length).toBe(2);
+    expect(mock.calls[0][0].headers['X-Relative-Time-Range']).toBe('true');
+    expect(mock.calls[1][0].headers['X-Relative-Time-Range']).toBeUndefined();
+  });
+
   describe('isExpressionReference', () => {
     test('check all possible expression references', () => {
       expect(isExpressionReference('__expr__')).toBeTruthy(); // New UID
@@ -5,6 +5,7 @@ import {
   DataFrame,
   dataFrameToJSON,
   DataQuery,
+  dateMath,
   DataQueryRequest,
   DataQueryResponse,
   TestDataSourceResponse,
@@ -83,6 +84,7 @@ enum PluginRequestHeaders {
   QueryGroupID = 'X-Query-Group-Id', // mainly useful to find related queries with query splitting
   FromExpression = 'X-Grafana-From-Expr', // used by datasources to identify expression queries
   SkipQueryCache = 'X-Cache-Skip', // used by datasources to skip the query cache
+  RelativeTimeRange = 'X-Relative-Time-Range', // used by the query cache to align relative time ranges
 }
 
 /**
@@ -117,7 +119,8 @@ export interface HealthCheckResult {
 class DataSourceWithBackend<
   TQuery extends DataQuery = DataQuery,
   TOptions extends DataSourceJsonData = DataSourceJsonData,
-> extends DataSourceApi<TQuery, TOptions> {
+  TQueryImportConfiguration extends Record<string, object> = {},
+> extends DataSourceApi<TQuery, TOptions, TQueryImportConfiguration> {
   constructor(instanceSettings: DataSourceInstanceSettings<TOptions>) {
     super(instanceSettings);
   }
@@ -232,6 +235,9 @@ class DataSourceWithBackend<
     if (request.skipQueryCache) {
       headers[PluginRequestHeaders.SkipQueryCache] = 'true';
     }
+    if (request.rangeRaw && (dateMath.isMathString(request.rangeRaw.from) || dateMath.isMathString(request.rangeRaw.to))) {
+      headers[PluginRequestHeaders.RelativeTimeRange] = 'true';
+    }
     return getBackendSrv()
       .fetch<BackendDataSourceResponse>({
         url,
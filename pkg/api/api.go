@@ -404,6 +404,7 @@ func (hs *HTTPServer) registerRoutes() {
 			pluginRoute.Get("/:pluginId/dashboards/", reqOrgAdmin, routing.Wrap(hs.GetPluginDashboards))
 			pluginRoute.Post("/:pluginId/settings", authorize(ac.EvalPermission(pluginaccesscontrol.ActionWrite, pluginIDScope)), routing.Wrap(hs.UpdatePluginSetting))
 			pluginRoute.Get("/:pluginId/metrics", reqOrgAdmin, routing.Wrap(hs.CollectPluginMetrics))
+			pluginRoute.Post("/:pluginId/restart", authorizeInOrg(ac.UseGlobalOrSingleOrg(hs.Cfg), ac.EvalPermission(pluginaccesscontrol.ActionInstall)), routing.Wrap(hs.RestartPlugin))
 		})
 
 		if hs.Features.IsEnabledGlobally(featuremgmt.FlagFeatureToggleAdminPage) {
@@ -468,6 +469,11 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Post("/calculate-diff", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.CalculateDashboardDiff))
 			dashboardRoute.Post("/validate", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.ValidateDashboard))
 
+			if hs.Features.IsEnabledGlobally(featuremgmt.FlagDashboardRestore) {
+				dashboardRoute.Get("/deleted", reqOrgAdmin, routing.Wrap(hs.GetDeletedDashboards))
+				dashboardRoute.Post("/deleted/:uid/restore", reqOrgAdmin, routing.Wrap(hs.RestoreDeletedDashboard))
+			}
+
 			dashboardRoute.Post("/db", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), routing.Wrap(hs.PostDashboard))
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
 			dashboardRoute.Get("/tags", hs.GetDashboardTags)
@@ -567,6 +573,21 @@ func (hs *HTTPServer) registerRoutes() {
 
 			// Some channels may have info
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
+
+			if hs.Features.IsEnabledGlobally(featuremgmt.FlagLivePipeline) {
+				// POST Live data to be processed according to channel rules.
+				liveRoute.Post("/pipeline/push/*", hs.LivePushGateway.HandlePipelinePush)
+				liveRoute.Post("/pipeline-convert-test", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineConvertTestHTTP))
+				liveRoute.Get("/pipeline-entities", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineEntitiesListHTTP))
+				liveRoute.Get("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesListHTTP))
+				liveRoute.Post("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPostHTTP))
+				liveRoute.Put("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPutHTTP))
+				liveRoute.Delete("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesDeleteHTTP))
+				liveRoute.Get("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsListHTTP))
+				liveRoute.Post("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPostHTTP))
+				liveRoute.Put("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPutHTTP))
+				liveRoute.Delete("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsDeleteHTTP))
+			}
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
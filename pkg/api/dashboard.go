@@ -305,15 +305,18 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 
 	namespaceID, userIDStr := c.SignedInUser.GetNamespacedID()
 
-	// disconnect all library elements for this dashboard
-	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
-	if err != nil {
-		hs.log.Error(
-			"Failed to disconnect library elements",
-			"dashboard", dash.ID,
-			"namespaceID", namespaceID,
-			"user", userIDStr,
-			"error", err)
+	// disconnect all library elements for this dashboard, unless the connections are kept
+	// in the recently deleted bin to be restored with the dashboard
+	if !hs.Features.IsEnabled(c.Req.Context(), featuremgmt.FlagDashboardRestore) {
+		err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
+		if err != nil {
+			hs.log.Error(
+				"Failed to disconnect library elements",
+				"dashboard", dash.ID,
+				"namespaceID", namespaceID,
+				"user", userIDStr,
+				"error", err)
+		}
 	}
 
 	// deletes all related public dashboard entities
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/deleted dashboards getDeletedDashboards
+//
+// Get recently deleted dashboards and folders.
+//
+// Lists the dashboards and folders of the organization that were deleted within the
+// recently deleted retention period, most recently deleted first.
+//
+// Responses:
+// 200: getDeletedDashboardsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDeletedDashboards(c *contextmodel.ReqContext) response.Response {
+	deleted, err := hs.DashboardService.GetDeletedDashboards(c.Req.Context(), &dashboards.GetDeletedDashboardsQuery{
+		OrgID: c.SignedInUser.GetOrgID(),
+		Limit: c.QueryInt64("limit"),
+		Page:  c.QueryInt64("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get recently deleted dashboards", err)
+	}
+	return response.JSON(http.StatusOK, deleted)
+}
+
+// swagger:route POST /dashboards/deleted/{uid}/restore dashboards restoreDeletedDashboardByUID
+//
+// Restore a deleted dashboard or folder.
+//
+// Restores the dashboard or folder with its versions, permissions and library panel connections.
+// Restoring a folder also restores the dashboards and subfolders deleted with it.
+//
+// Responses:
+// 200: restoreDeletedDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreDeletedDashboard(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.RestoreDeletedDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, err := hs.DashboardService.RestoreDashboard(c.Req.Context(), &dashboards.RestoreDeletedDashboardCommand{
+		UID:          web.Params(c.Req)[":uid"],
+		OrgID:        c.SignedInUser.GetOrgID(),
+		FolderUID:    cmd.FolderUID,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"uid":     dash.UID,
+		"title":   dash.Title,
+		"url":     dashboards.GetDashboardFolderURL(dash.IsFolder, dash.UID, dash.Slug),
+		"message": fmt.Sprintf("%s restored", dash.Title),
+	})
+}
+
+// swagger:parameters getDeletedDashboards
+type GetDeletedDashboardsParams struct {
+	// Limit the maximum number of dashboards and folders to return, all are returned when not set
+	// in:query
+	// required:false
+	Limit int64 `json:"limit"`
+	// Page index for starting fetching dashboards and folders
+	// in:query
+	// required:false
+	// default:1
+	Page int64 `json:"page"`
+}
+
+// swagger:parameters restoreDeletedDashboardByUID
+type RestoreDeletedDashboardByUIDParams struct {
+	// in:body
+	// required:true
+	Body dtos.RestoreDeletedDashboardCommand
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getDeletedDashboardsResponse
+type GetDeletedDashboardsResponse struct {
+	// in: body
+	Body []*dashboards.DeletedDashboard `json:"body"`
+}
+
+// swagger:response restoreDeletedDashboardResponse
+type RestoreDeletedDashboardResponse struct {
+	// in: body
+	Body struct {
+		// UID Identifier of the restored dashboard or folder.
+		// required: true
+		// example: cIBgcSjkk
+		UID string `json:"uid"`
+
+		// Title Title of the restored dashboard or folder.
+		// required: true
+		// example: My Dashboard
+		Title string `json:"title"`
+
+		// URL Path of the restored dashboard or folder.
+		// required: true
+		// example: /d/cIBgcSjkk/my-dashboard
+		URL string `json:"url"`
+
+		// Message Message of the restore.
+		// required: true
+		// example: My Dashboard restored
+		Message string `json:"message"`
+	} `json:"body"`
+}
@@ -71,3 +71,8 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type RestoreDeletedDashboardCommand struct {
+	// FolderUID is the folder to restore to, defaults to the folder the dashboard was deleted from.
+	FolderUID *string `json:"folderUid"`
+}
@@ -2,6 +2,7 @@ package dtos
 
 import (
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 )
 
@@ -27,6 +28,8 @@ type PluginSetting struct {
 	SignatureType   plugins.SignatureType   `json:"signatureType"`
 	SignatureOrg    string                  `json:"signatureOrg"`
 	AngularDetected bool                    `json:"angularDetected"`
+	// Process is the status of the backend plugin process, if Grafana keeps it alive.
+	Process *process.Status `json:"process,omitempty"`
 }
 
 type PluginListItem struct {
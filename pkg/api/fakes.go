@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 )
 
 type fakePluginInstaller struct {
@@ -34,6 +35,26 @@ func (pm *fakePluginInstaller) Remove(_ context.Context, pluginID string) error
 	return nil
 }
 
+type fakePluginProcessSupervisor struct {
+	process.Supervisor
+
+	statuses map[string]process.Status
+	restarts map[string]int
+}
+
+func (ps *fakePluginProcessSupervisor) Status(pluginID string) (process.Status, bool) {
+	status, exists := ps.statuses[pluginID]
+	return status, exists
+}
+
+func (ps *fakePluginProcessSupervisor) Restart(_ context.Context, pluginID string) error {
+	if _, exists := ps.statuses[pluginID]; !exists {
+		return process.ErrPluginProcessNotFound
+	}
+	ps.restarts[pluginID]++
+	return nil
+}
+
 type fakeRendererManager struct {
 	plugins.RendererManager
 }
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestLivePipelineAPI_RequiresOrgAdmin(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagLivePipeline)
+		hs.Live = newTestLive(t, db.InitTestDB(t))
+	})
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/live/pipeline-convert-test"},
+		{http.MethodGet, "/api/live/pipeline-entities"},
+		{http.MethodGet, "/api/live/channel-rules"},
+		{http.MethodPost, "/api/live/channel-rules"},
+		{http.MethodPut, "/api/live/channel-rules"},
+		{http.MethodDelete, "/api/live/channel-rules"},
+		{http.MethodGet, "/api/live/write-configs"},
+		{http.MethodPost, "/api/live/write-configs"},
+		{http.MethodPut, "/api/live/write-configs"},
+		{http.MethodDelete, "/api/live/write-configs"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path+" as viewer", func(t *testing.T) {
+			req := server.NewRequest(route.method, route.path, strings.NewReader("{}"))
+			req = webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+		})
+	}
+}
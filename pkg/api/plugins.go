@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/plugindef"
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -232,6 +233,10 @@ func (hs *HTTPServer) GetPluginSettingByID(c *contextmodel.ReqContext) response.
 		dto.HasUpdate = true
 	}
 
+	if status, exists := hs.pluginProcess.Status(plugin.ID); exists {
+		dto.Process = &status
+	}
+
 	return response.JSON(http.StatusOK, dto)
 }
 
@@ -498,6 +503,23 @@ func (hs *HTTPServer) UninstallPlugin(c *contextmodel.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, []byte{})
 }
 
+func (hs *HTTPServer) RestartPlugin(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	if _, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID); !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found, no installed plugin with that id", nil)
+	}
+
+	err := hs.pluginProcess.Restart(c.Req.Context(), pluginID)
+	if err != nil {
+		if errors.Is(err, process.ErrPluginProcessNotFound) {
+			return response.Error(http.StatusBadRequest, "Plugin has no backend process that can be restarted", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to restart plugin", err)
+	}
+	return response.Success("Plugin restarted")
+}
+
 func translatePluginRequestErrorToAPIError(err error) response.Response {
 	return response.ErrOrFallback(http.StatusInternalServerError, "Plugin request failed", err)
 }
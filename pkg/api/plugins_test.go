@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/manager/filestore"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
 	"github.com/grafana/grafana/pkg/plugins/plugindef"
@@ -110,6 +111,58 @@ func Test_PluginsInstallAndUninstall(t *testing.T) {
 	}
 }
 
+func Test_PluginsRestart(t *testing.T) {
+	canInstall := []ac.Permission{{Action: pluginaccesscontrol.ActionInstall}}
+	cannotInstall := []ac.Permission{{Action: "plugins:cannotinstall"}}
+
+	pluginRegistry := &fakes.FakePluginRegistry{
+		Store: map[string]*plugins.Plugin{
+			"backend-datasource": {JSONData: plugins.JSONData{ID: "backend-datasource", Type: plugins.TypeDataSource, Backend: true}},
+			"frontend-panel":     {JSONData: plugins.JSONData{ID: "frontend-panel", Type: plugins.TypePanel}},
+		},
+	}
+
+	tcs := []struct {
+		desc         string
+		pluginID     string
+		permissions  []ac.Permission
+		expectedCode int
+	}{
+		{desc: "should not restart without install permission", pluginID: "backend-datasource", permissions: cannotInstall, expectedCode: http.StatusForbidden},
+		{desc: "should not restart unknown plugin", pluginID: "unknown", permissions: canInstall, expectedCode: http.StatusNotFound},
+		{desc: "should not restart plugin without backend process", pluginID: "frontend-panel", permissions: canInstall, expectedCode: http.StatusBadRequest},
+		{desc: "should restart backend plugin", pluginID: "backend-datasource", permissions: canInstall, expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			supervisor := &fakePluginProcessSupervisor{
+				statuses: map[string]process.Status{"backend-datasource": {State: process.StateCrashLoop}},
+				restarts: map[string]int{},
+			}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.orgService = &orgtest.FakeOrgService{ExpectedOrg: &org.Org{}}
+				hs.accesscontrolService = &actest.FakeService{}
+				hs.pluginStore = pluginstore.New(pluginRegistry, &fakes.FakeLoader{})
+				hs.pluginProcess = supervisor
+			})
+
+			req := webtest.RequestWithSignedInUser(server.NewPostRequest("/api/plugins/"+tc.pluginID+"/restart", strings.NewReader("{ }")), userWithPermissions(ac.GlobalOrgID, tc.permissions))
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+
+			expectedRestarts := 0
+			if tc.expectedCode == http.StatusOK {
+				expectedRestarts = 1
+			}
+			require.Equal(t, expectedRestarts, supervisor.restarts[tc.pluginID])
+		})
+	}
+}
+
 func Test_GetPluginAssetCDNRedirect(t *testing.T) {
 	const cdnPluginID = "cdn-plugin"
 	const nonCDNPluginID = "non-cdn-plugin"
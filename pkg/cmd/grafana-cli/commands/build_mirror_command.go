@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/plugins/repo"
+)
+
+// buildMirrorCommand writes the index of a plugin repository mirror for the plugin zip archives
+// in a directory. The directory can then be used with --repoMirror or the repository_mirror
+// setting, either directly or served by an HTTP server.
+func buildMirrorCommand(c utils.CommandLine) error {
+	dir := c.Args().First()
+	if dir == "" {
+		return errors.New("please specify the directory containing the plugin archives")
+	}
+
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !fileInfo.IsDir() {
+		return errors.New("path is not a directory")
+	}
+
+	index, err := repo.BuildMirror(dir)
+	if err != nil {
+		return fmt.Errorf("failed to build plugin repository mirror: %w", err)
+	}
+
+	for _, p := range index.Plugins {
+		versions := make([]string, 0, len(p.Versions))
+		for _, v := range p.Versions {
+			versions = append(versions, v.Version)
+		}
+		logger.Infof("id: %v versions: %s\n", p.ID, strings.Join(versions, ", "))
+	}
+	logger.Infof("%s Wrote index of %d plugins to %s\n", color.GreenString("✔"), len(index.Plugins),
+		filepath.Join(dir, repo.MirrorIndexFile))
+	return nil
+}
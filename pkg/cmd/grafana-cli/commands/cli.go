@@ -48,6 +48,11 @@ func CLICommand(version string) *cli.Command {
 				Value:   "https://grafana.com/api/plugins",
 				EnvVars: []string{"GF_PLUGIN_REPO"},
 			},
+			&cli.StringFlag{
+				Name:    "repoMirror",
+				Usage:   "Path or URL of a plugin repository mirror to install plugins from instead of the plugin repository",
+				EnvVars: []string{"GF_PLUGIN_REPO_MIRROR"},
+			},
 			&cli.StringFlag{
 				Name:    "pluginUrl",
 				Usage:   "Full url to the plugin zip file instead of downloading the plugin from grafana.com/api",
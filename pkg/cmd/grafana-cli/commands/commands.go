@@ -108,6 +108,10 @@ var pluginCommands = []*cli.Command{
 		Name:   "ls",
 		Usage:  "list installed plugins (excludes core plugins)",
 		Action: runPluginCommand(lsCommand),
+	}, {
+		Name:   "build-mirror",
+		Usage:  "build-mirror <directory>",
+		Action: runPluginCommand(buildMirrorCommand),
 	}, {
 		Name:    "uninstall",
 		Aliases: []string{"remove"},
@@ -82,8 +82,8 @@ func installCommand(c utils.CommandLine) error {
 	return err
 }
 
-// installPlugin downloads the plugin code as a zip file from the Grafana.com API
-// and then extracts the zip into the plugin's directory.
+// installPlugin downloads the plugin code as a zip file from the Grafana.com API,
+// or from a plugin repository mirror, and then extracts the zip into the plugin's directory.
 func installPlugin(ctx context.Context, pluginID, version string, c utils.CommandLine) error {
 	// If a version is specified, check if it is already installed
 	if version != "" {
@@ -96,6 +96,7 @@ func installPlugin(ctx context.Context, pluginID, version string, c utils.Comman
 	repository := repo.NewManager(repo.ManagerCfg{
 		SkipTLSVerify: c.Bool("insecure"),
 		BaseURL:       c.PluginRepoURL(),
+		MirrorURL:     c.PluginRepoMirror(),
 		Logger:        services.Logger,
 	})
 
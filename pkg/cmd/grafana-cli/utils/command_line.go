@@ -20,6 +20,7 @@ type CommandLine interface {
 
 	PluginDirectory() string
 	PluginRepoURL() string
+	PluginRepoMirror() string
 	PluginURL() string
 }
 
@@ -56,6 +57,10 @@ func (c *ContextCommandLine) PluginRepoURL() string {
 	return c.String("repo")
 }
 
+func (c *ContextCommandLine) PluginRepoMirror() string {
+	return c.String("repoMirror")
+}
+
 func (c *ContextCommandLine) PluginURL() string {
 	return c.String("pluginUrl")
 }
@@ -85,7 +85,7 @@ type ReduceCommand struct {
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+	_, err := mathexp.GetSeriesReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -323,6 +323,10 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeSQL is the CMDType for running a SQL statement over the results of other queries.
+	TypeSQL
+	// TypeForecast is the CMDType for computing a seasonal baseline and outlier bands of a timeseries.
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -335,6 +339,12 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
+	case TypeSQL:
+		return "sql"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -353,6 +363,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "sql":
+		return TypeSQL, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -0,0 +1,408 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	// ForecastHoltWinters computes the baseline with additive Holt-Winters (triple exponential) smoothing
+	// and the bands with the smoothed absolute deviation of the forecast errors (Brutlag).
+	ForecastHoltWinters = "holt_winters"
+	// ForecastMedian computes the baseline as the median of the values at the same time of the
+	// previous seasons, and the bands with the scaled median absolute deviation of these values.
+	ForecastMedian = "median"
+
+	// ForecastOutputScore is the number of deviations between the value and the baseline.
+	ForecastOutputScore = "score"
+	// ForecastOutputBaseline is the expected value.
+	ForecastOutputBaseline = "baseline"
+	// ForecastOutputUpper is the upper band, the baseline plus the configured number of deviations.
+	ForecastOutputUpper = "upper"
+	// ForecastOutputLower is the lower band, the baseline minus the configured number of deviations.
+	ForecastOutputLower = "lower"
+	// ForecastOutputAll returns all outputs, distinguished by the forecastOutputLabel label.
+	ForecastOutputAll = "all"
+
+	forecastOutputLabel = "forecast"
+
+	defaultForecastPeriods    = 3
+	defaultForecastDeviations = 3
+	defaultForecastAlpha      = 0.5
+	defaultForecastBeta       = 0.1
+	defaultForecastGamma      = 0.3
+
+	// madScale turns the median absolute deviation into an estimate of the standard deviation for normally distributed data.
+	madScale = 1.4826
+)
+
+var (
+	supportedForecastMethods = []string{ForecastHoltWinters, ForecastMedian}
+	supportedForecastOutputs = []string{ForecastOutputScore, ForecastOutputBaseline, ForecastOutputUpper, ForecastOutputLower, ForecastOutputAll}
+	forecastOutputs          = []string{ForecastOutputBaseline, ForecastOutputUpper, ForecastOutputLower, ForecastOutputScore}
+)
+
+// ForecastCommand is an expression command that computes a seasonal baseline and outlier bands
+// for each series of its input. It runs inside Grafana and does not need the Machine Learning plugin.
+type ForecastCommand struct {
+	VarToForecast string
+	Method        string
+	Season        time.Duration
+	// Periods is the number of previous seasons the median method uses.
+	Periods int
+	// Deviations is the width of the bands, in deviations from the baseline.
+	Deviations float64
+	// Alpha, Beta and Gamma are the smoothing factors of the level, trend and seasonality of the Holt-Winters method.
+	Alpha  float64
+	Beta   float64
+	Gamma  float64
+	Output string
+	refID  string
+}
+
+// ForecastCommandConfig is the model of a forecast expression.
+type ForecastCommandConfig struct {
+	Expression string   `json:"expression"`
+	Method     string   `json:"method"`
+	Season     string   `json:"season"`
+	Periods    int      `json:"periods,omitempty"`
+	Deviations float64  `json:"deviations,omitempty"`
+	Alpha      *float64 `json:"alpha,omitempty"`
+	Beta       *float64 `json:"beta,omitempty"`
+	Gamma      *float64 `json:"gamma,omitempty"`
+	Output     string   `json:"output,omitempty"`
+}
+
+// NewForecastCommand creates a new ForecastCommand. Zero values of the optional settings are replaced by their defaults.
+func NewForecastCommand(refID, varToForecast string, cfg ForecastCommandConfig) (*ForecastCommand, error) {
+	cmd := &ForecastCommand{
+		VarToForecast: varToForecast,
+		Method:        cfg.Method,
+		Periods:       cfg.Periods,
+		Deviations:    cfg.Deviations,
+		Alpha:         defaultForecastAlpha,
+		Beta:          defaultForecastBeta,
+		Gamma:         defaultForecastGamma,
+		Output:        cfg.Output,
+		refID:         refID,
+	}
+
+	switch cmd.Method {
+	case ForecastHoltWinters, ForecastMedian:
+	default:
+		return nil, fmt.Errorf("expected forecast method to be one of [%s], got %s", strings.Join(supportedForecastMethods, ", "), cmd.Method)
+	}
+
+	season, err := gtime.ParseDuration(cfg.Season)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse forecast "season" duration field %q: %w`, cfg.Season, err)
+	}
+	if season <= 0 {
+		return nil, fmt.Errorf("forecast season must be positive, got %s", cfg.Season)
+	}
+	cmd.Season = season
+
+	if cmd.Periods == 0 {
+		cmd.Periods = defaultForecastPeriods
+	}
+	if cmd.Periods < 0 {
+		return nil, fmt.Errorf("forecast periods must be positive, got %d", cmd.Periods)
+	}
+	if cmd.Deviations == 0 {
+		cmd.Deviations = defaultForecastDeviations
+	}
+	if cmd.Deviations < 0 {
+		return nil, fmt.Errorf("forecast deviations must be positive, got %v", cmd.Deviations)
+	}
+
+	for _, f := range []struct {
+		name  string
+		value *float64
+		dst   *float64
+	}{{"alpha", cfg.Alpha, &cmd.Alpha}, {"beta", cfg.Beta, &cmd.Beta}, {"gamma", cfg.Gamma, &cmd.Gamma}} {
+		if f.value == nil {
+			continue
+		}
+		if *f.value <= 0 || *f.value > 1 {
+			return nil, fmt.Errorf("forecast %s must be greater than 0 and less than or equal to 1, got %v", f.name, *f.value)
+		}
+		*f.dst = *f.value
+	}
+
+	if cmd.Output == "" {
+		cmd.Output = ForecastOutputScore
+	}
+	switch cmd.Output {
+	case ForecastOutputScore, ForecastOutputBaseline, ForecastOutputUpper, ForecastOutputLower, ForecastOutputAll:
+	default:
+		return nil, fmt.Errorf("expected forecast output to be one of [%s], got %s", strings.Join(supportedForecastOutputs, ", "), cmd.Output)
+	}
+	return cmd, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	cfg := ForecastCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	if cfg.Expression == "" {
+		return nil, errors.New("no expression ID to forecast. must be a reference to an existing query or expression")
+	}
+	return NewForecastCommand(rn.RefID, strings.TrimPrefix(cfg.Expression, "$"), cfg)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (fc *ForecastCommand) NeedsVars() []string {
+	return []string{fc.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (fc *ForecastCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("method", fc.Method), attribute.String("output", fc.Output))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[fc.VarToForecast].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			bands := fc.forecast(v)
+			if fc.Output != ForecastOutputAll {
+				newRes.Values = append(newRes.Values, fc.toSeries(v, bands, fc.Output, v.GetLabels()))
+				continue
+			}
+			for _, output := range forecastOutputs {
+				labels := v.GetLabels().Copy()
+				if labels == nil {
+					labels = data.Labels{}
+				}
+				labels[forecastOutputLabel] = output
+				newRes.Values = append(newRes.Values, fc.toSeries(v, bands, output, labels))
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// forecastPoint is the baseline and the deviation expected at a point. Points without enough history are nil.
+type forecastPoint struct {
+	baseline  float64
+	deviation float64
+}
+
+// forecast returns the forecast of each point of a series. Points are expected to be sorted by time.
+func (fc *ForecastCommand) forecast(s mathexp.Series) []*forecastPoint {
+	switch fc.Method {
+	case ForecastHoltWinters:
+		return holtWinters(s, fc.Season, fc.Alpha, fc.Beta, fc.Gamma)
+	default:
+		return medianOfPeriods(s, fc.Season, fc.Periods)
+	}
+}
+
+func (fc *ForecastCommand) toSeries(s mathexp.Series, points []*forecastPoint, output string, labels data.Labels) mathexp.Series {
+	newSeries := mathexp.NewSeries(fc.refID, labels, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		p := points[i]
+		if p == nil {
+			newSeries.SetPoint(i, t, nil)
+			continue
+		}
+		var f float64
+		switch output {
+		case ForecastOutputBaseline:
+			f = p.baseline
+		case ForecastOutputUpper:
+			f = p.baseline + fc.Deviations*p.deviation
+		case ForecastOutputLower:
+			f = p.baseline - fc.Deviations*p.deviation
+		case ForecastOutputScore:
+			if v == nil || math.IsNaN(*v) {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			f = forecastScore(*v, p)
+		}
+		newSeries.SetPoint(i, t, &f)
+	}
+	return newSeries
+}
+
+// forecastScore returns the number of deviations between the value and the baseline.
+// A value that differs from a baseline without deviation is infinitely far from it.
+func forecastScore(v float64, p *forecastPoint) float64 {
+	diff := v - p.baseline
+	if p.deviation == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return math.Inf(int(math.Copysign(1, diff)))
+	}
+	return diff / p.deviation
+}
+
+// medianOfPeriods forecasts each point from the values at the same time of the previous periods seasons.
+func medianOfPeriods(s mathexp.Series, season time.Duration, periods int) []*forecastPoint {
+	points := make([]*forecastPoint, s.Len())
+	tolerance := seriesStep(s) / 2
+	history := make([]float64, 0, periods)
+	for i := 0; i < s.Len(); i++ {
+		t := s.GetTime(i)
+		history = history[:0]
+		for k := 1; k <= periods; k++ {
+			if v := valueNear(s, t.Add(-time.Duration(k)*season), tolerance); v != nil {
+				history = append(history, *v)
+			}
+		}
+		if len(history) == 0 {
+			continue
+		}
+		baseline := median(history)
+		deviations := make([]float64, len(history))
+		for j, v := range history {
+			deviations[j] = math.Abs(v - baseline)
+		}
+		points[i] = &forecastPoint{baseline: baseline, deviation: madScale * median(deviations)}
+	}
+	return points
+}
+
+// holtWinters forecasts each point one step ahead with additive Holt-Winters smoothing. The series
+// is expected to be evenly spaced. The first season initializes the model and has no forecast.
+func holtWinters(s mathexp.Series, season time.Duration, alpha, beta, gamma float64) []*forecastPoint {
+	points := make([]*forecastPoint, s.Len())
+	step := seriesStep(s)
+	if step <= 0 {
+		return points
+	}
+	m := int(math.Round(float64(season) / float64(step)))
+	if m < 2 || s.Len() <= m {
+		return points
+	}
+
+	firstMean, ok := meanOf(s, 0, m)
+	if !ok {
+		return points
+	}
+	level, trend := firstMean, 0.0
+	if secondMean, ok := meanOf(s, m, 2*m); ok && s.Len() >= 2*m {
+		trend = (secondMean - firstMean) / float64(m)
+	}
+	seasonal := make([]float64, m)
+	deviation := make([]float64, m)
+	initialDeviation := 0.0
+	for i := 0; i < m; i++ {
+		if v := s.GetValue(i); v != nil && !math.IsNaN(*v) {
+			seasonal[i] = *v - firstMean
+			initialDeviation += math.Abs(seasonal[i])
+		}
+	}
+	for i := range deviation {
+		deviation[i] = initialDeviation / float64(m)
+	}
+
+	for t := m; t < s.Len(); t++ {
+		idx := t % m
+		expected := level + trend + seasonal[idx]
+		points[t] = &forecastPoint{baseline: expected, deviation: deviation[idx]}
+
+		v := s.GetValue(t)
+		if v == nil || math.IsNaN(*v) {
+			level += trend
+			continue
+		}
+		newLevel := alpha*(*v-seasonal[idx]) + (1-alpha)*(level+trend)
+		trend = beta*(newLevel-level) + (1-beta)*trend
+		level = newLevel
+		seasonal[idx] = gamma*(*v-level) + (1-gamma)*seasonal[idx]
+		deviation[idx] = gamma*math.Abs(*v-expected) + (1-gamma)*deviation[idx]
+	}
+	return points
+}
+
+// seriesStep returns the median interval between the points of a series sorted by time.
+func seriesStep(s mathexp.Series) time.Duration {
+	if s.Len() < 2 {
+		return 0
+	}
+	steps := make([]float64, 0, s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		steps = append(steps, float64(s.GetTime(i).Sub(s.GetTime(i-1))))
+	}
+	return time.Duration(median(steps))
+}
+
+// valueNear returns the value of the point closest to t of a series sorted by time, if it is not farther than tolerance.
+func valueNear(s mathexp.Series, t time.Time, tolerance time.Duration) *float64 {
+	i := sort.Search(s.Len(), func(i int) bool {
+		return !s.GetTime(i).Before(t)
+	})
+	best := -1
+	var bestDiff time.Duration
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= s.Len() {
+			continue
+		}
+		diff := s.GetTime(j).Sub(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if best == -1 || diff < bestDiff {
+			best, bestDiff = j, diff
+		}
+	}
+	if best == -1 || bestDiff > tolerance {
+		return nil
+	}
+	v := s.GetValue(best)
+	if v == nil || math.IsNaN(*v) {
+		return nil
+	}
+	return v
+}
+
+// meanOf returns the mean of the non-null values of the points in [from, to).
+func meanOf(s mathexp.Series, from, to int) (float64, bool) {
+	sum, count := 0.0, 0
+	for i := from; i < to && i < s.Len(); i++ {
+		if v := s.GetValue(i); v != nil && !math.IsNaN(*v) {
+			sum += *v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// median returns the median of values. It sorts values in place.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,180 @@
+package expr
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		errMsg string
+		assert func(t *testing.T, cmd *ForecastCommand)
+	}{
+		{
+			name:  "should use defaults",
+			query: `{"expression":"$A","method":"median","season":"1d"}`,
+			assert: func(t *testing.T, cmd *ForecastCommand) {
+				require.Equal(t, "A", cmd.VarToForecast)
+				require.Equal(t, 24*time.Hour, cmd.Season)
+				require.Equal(t, defaultForecastPeriods, cmd.Periods)
+				require.Equal(t, float64(defaultForecastDeviations), cmd.Deviations)
+				require.Equal(t, ForecastOutputScore, cmd.Output)
+				require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			},
+		},
+		{
+			name:  "should read settings",
+			query: `{"expression":"A","method":"holt_winters","season":"1h","deviations":2,"alpha":0.2,"output":"upper"}`,
+			assert: func(t *testing.T, cmd *ForecastCommand) {
+				require.Equal(t, float64(2), cmd.Deviations)
+				require.Equal(t, 0.2, cmd.Alpha)
+				require.Equal(t, defaultForecastBeta, cmd.Beta)
+				require.Equal(t, ForecastOutputUpper, cmd.Output)
+			},
+		},
+		{
+			name:   "should fail without expression",
+			query:  `{"method":"median","season":"1d"}`,
+			errMsg: "no expression ID",
+		},
+		{
+			name:   "should fail with unknown method",
+			query:  `{"expression":"A","method":"prophet","season":"1d"}`,
+			errMsg: "expected forecast method",
+		},
+		{
+			name:   "should fail with invalid season",
+			query:  `{"expression":"A","method":"median","season":"daily"}`,
+			errMsg: "season",
+		},
+		{
+			name:   "should fail with smoothing factor out of range",
+			query:  `{"expression":"A","method":"holt_winters","season":"1d","gamma":1.5}`,
+			errMsg: "gamma",
+		},
+		{
+			name:   "should fail with unknown output",
+			query:  `{"expression":"A","method":"median","season":"1d","output":"middle"}`,
+			errMsg: "expected forecast output",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := UnmarshalForecastCommand(&rawNode{RefID: "B", QueryRaw: []byte(tc.query)})
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			tc.assert(t, cmd)
+		})
+	}
+}
+
+func TestForecastCommand_FeatureToggle(t *testing.T) {
+	rn := &rawNode{
+		RefID:    "B",
+		Query:    map[string]any{"type": "forecast"},
+		QueryRaw: []byte(`{"type":"forecast","expression":"A","method":"median","season":"1d"}`),
+	}
+
+	_, err := buildCMDNode(rn, featuremgmt.WithFeatures())
+	require.ErrorContains(t, err, featuremgmt.FlagForecastExpressions)
+
+	node, err := buildCMDNode(rn, featuremgmt.WithFeatures(featuremgmt.FlagForecastExpressions))
+	require.NoError(t, err)
+	require.Equal(t, TypeForecast, node.CMDType)
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	start := time.Unix(0, 0)
+	season := []float64{10, 20, 30, 20}
+	// four seasons of a repeating pattern, followed by a spike at the first point of the fifth season
+	newSeries := func() mathexp.Series {
+		s := mathexp.NewSeries("A", data.Labels{"host": "a"}, 0)
+		for i := 0; i < 4*len(season); i++ {
+			v := season[i%len(season)]
+			s.AppendPoint(start.Add(time.Duration(i)*time.Minute), &v)
+		}
+		spike := float64(100)
+		s.AppendPoint(start.Add(time.Duration(4*len(season))*time.Minute), &spike)
+		return s
+	}
+	vars := func() mathexp.Vars {
+		return mathexp.Vars{"A": mathexp.Results{Values: []mathexp.Value{newSeries()}}}
+	}
+
+	execute := func(t *testing.T, cfg ForecastCommandConfig) mathexp.Results {
+		t.Helper()
+		cmd, err := NewForecastCommand("B", "A", cfg)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars(), tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("median should return the value of the previous seasons as baseline", func(t *testing.T) {
+		res := execute(t, ForecastCommandConfig{Method: ForecastMedian, Season: "4m", Output: ForecastOutputBaseline})
+		require.Len(t, res.Values, 1)
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+		require.Nil(t, s.GetValue(0), "first season has no history")
+		for i := len(season); i < s.Len(); i++ {
+			require.Equal(t, season[i%len(season)], *s.GetValue(i))
+		}
+	})
+
+	t.Run("median should score the spike as an outlier", func(t *testing.T) {
+		res := execute(t, ForecastCommandConfig{Method: ForecastMedian, Season: "4m"})
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, float64(0), *s.GetValue(s.Len()-2))
+		require.True(t, math.IsInf(*s.GetValue(s.Len()-1), 1))
+	})
+
+	t.Run("holt-winters should learn the season and score the spike as an outlier", func(t *testing.T) {
+		res := execute(t, ForecastCommandConfig{Method: ForecastHoltWinters, Season: "4m", Output: ForecastOutputAll})
+		require.Len(t, res.Values, 4)
+		byOutput := map[string]mathexp.Series{}
+		for _, v := range res.Values {
+			s := v.(mathexp.Series)
+			require.Equal(t, "a", s.GetLabels()["host"])
+			byOutput[s.GetLabels()[forecastOutputLabel]] = s
+		}
+
+		baseline, upper, lower, score := byOutput[ForecastOutputBaseline], byOutput[ForecastOutputUpper], byOutput[ForecastOutputLower], byOutput[ForecastOutputScore]
+		last := baseline.Len() - 1
+		require.Nil(t, baseline.GetValue(0), "first season initializes the model")
+		require.InDelta(t, season[0], *baseline.GetValue(last), 1)
+		require.Greater(t, *upper.GetValue(last), *baseline.GetValue(last))
+		require.Less(t, *lower.GetValue(last), *baseline.GetValue(last))
+		require.Greater(t, *score.GetValue(last), float64(defaultForecastDeviations))
+		require.Less(t, math.Abs(*score.GetValue(last-1)), float64(defaultForecastDeviations))
+	})
+
+	t.Run("should pass no data through", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", ForecastCommandConfig{Method: ForecastMedian, Season: "1d"})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: []mathexp.Value{mathexp.NewNoData()}}}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.IsType(t, mathexp.NoData{}, res.Values[0])
+	})
+
+	t.Run("should fail on numbers", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", ForecastCommandConfig{Method: ForecastMedian, Season: "1d"})
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: []mathexp.Value{mathexp.NewNumber("A", nil)}}}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
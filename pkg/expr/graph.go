@@ -279,6 +279,14 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 				}
 			}
 
+			if dsNode, ok := neededNode.(*DSNode); ok {
+				if cmdNode.CMDType == TypeSQL {
+					dsNode.isInputToSQLExpr = true
+				} else {
+					dsNode.isInputToOtherExpr = true
+				}
+			}
+
 			if neededNode.NodeType() == TypeCMDNode {
 				if neededNode.(*CMDNode).CMDType == TypeClassicConditions {
 					return fmt.Errorf("classic conditions may not be the input for other expressions, but %v is the input for %v", neededVar, cmdNode.RefID())
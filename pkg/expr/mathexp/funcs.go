@@ -1,7 +1,11 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +72,43 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"clamp_min": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clampMin,
+	},
+	"clamp_max": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clampMax,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"cumulative_sum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumulativeSum,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkDurationArg(1, false),
+	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      shift,
+		Check:  checkDurationArg(1, true),
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +348,222 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// clampMin returns the greater of the value and min for each result in NumberSet, SeriesSet, or Scalar.
+func clampMin(e *State, varSet Results, minRes Results) (Results, error) {
+	limit, err := scalarArg(minRes)
+	if err != nil {
+		return Results{}, fmt.Errorf("clamp_min: %w", err)
+	}
+	return clamp(e, varSet, func(f float64) float64 {
+		return math.Max(f, limit)
+	})
+}
+
+// clampMax returns the lesser of the value and max for each result in NumberSet, SeriesSet, or Scalar.
+func clampMax(e *State, varSet Results, maxRes Results) (Results, error) {
+	limit, err := scalarArg(maxRes)
+	if err != nil {
+		return Results{}, fmt.Errorf("clamp_max: %w", err)
+	}
+	return clamp(e, varSet, func(f float64) float64 {
+		return math.Min(f, limit)
+	})
+}
+
+// clamp applies clampF to each value of varSet. Null values stay null and NaN values stay NaN.
+func clamp(e *State, varSet Results, clampF func(f float64) float64) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perNullableFloat(e, res, func(f *float64) *float64 {
+			if f == nil || math.IsNaN(*f) {
+				return f
+			}
+			nF := clampF(*f)
+			return &nF
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// rate returns the per-second change between each point and the previous point of each series.
+// The first point of each series has no previous point and is dropped. Points where either
+// value is null are null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, func(s Series) (Series, error) {
+		return pairwise(e, s, func(prevT, t time.Time, prev, cur float64) float64 {
+			return (cur - prev) / t.Sub(prevT).Seconds()
+		}), nil
+	})
+}
+
+// delta returns the difference between each point and the previous point of each series.
+// The first point of each series has no previous point and is dropped. Points where either
+// value is null are null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, func(s Series) (Series, error) {
+		return pairwise(e, s, func(_, _ time.Time, prev, cur float64) float64 {
+			return cur - prev
+		}), nil
+	})
+}
+
+// cumulativeSum returns the running total of each series. Null points stay null and do
+// not change the total.
+func cumulativeSum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		sum := float64(0)
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries, nil
+	})
+}
+
+// movingAvg returns the average of the non-null values of each series within the window
+// that ends at each point, e.g. moving_avg($A, "5m").
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := gtime.ParseDuration(rawWindow)
+	if err != nil {
+		return Results{}, fmt.Errorf("moving_avg: failed to parse window %q: %w", rawWindow, err)
+	}
+	return perSeries(e, varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		start, sum, count := 0, float64(0), 0
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f != nil {
+				sum += *f
+				count++
+			}
+			// drop the points that are no longer in the window (t-window, t]
+			for ; start < i && !s.GetTime(start).After(t.Add(-window)); start++ {
+				if v := s.GetValue(start); v != nil {
+					sum -= *v
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, t, &avg)
+		}
+		return newSeries, nil
+	})
+}
+
+// shift moves each point of each series forward in time by the given duration, e.g.
+// $A - shift($A, "1d") compares the current value with the value at the same time yesterday.
+// Negative durations move points backward in time.
+func shift(e *State, varSet Results, rawOffset string) (Results, error) {
+	offset, err := parseSignedDuration(rawOffset)
+	if err != nil {
+		return Results{}, fmt.Errorf("shift: failed to parse duration %q: %w", rawOffset, err)
+	}
+	return perSeries(e, varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(offset), f)
+		}
+		return newSeries, nil
+	})
+}
+
+// perSeries passes each Series of varSet to seriesF. NoData values are passed through, any other
+// value type is an error. Points of the series are expected to be sorted by time.
+func perSeries(e *State, varSet Results, seriesF func(s Series) (Series, error)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch res.Type() {
+		case parse.TypeSeriesSet:
+			newSeries, err := seriesF(res.(Series))
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, newSeries)
+		case parse.TypeNoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("expected a series, got %v", res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// pairwise returns a series with a point for each point of s but the first, computed by pairF from
+// the point and the previous point. The resulting point is null if either value is null.
+func pairwise(e *State, s Series, pairF func(prevT, t time.Time, prev, cur float64) float64) Series {
+	if s.Len() < 2 {
+		return NewSeries(e.RefID, s.GetLabels(), 0)
+	}
+	newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		prevT, prev := s.GetPoint(i - 1)
+		t, cur := s.GetPoint(i)
+		if prev == nil || cur == nil {
+			newSeries.SetPoint(i-1, t, nil)
+			continue
+		}
+		nF := pairF(prevT, t, *prev, *cur)
+		newSeries.SetPoint(i-1, t, &nF)
+	}
+	return newSeries
+}
+
+// scalarArg returns the value of a scalar function argument.
+func scalarArg(res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("expected a scalar argument")
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("expected a non-null scalar argument")
+	}
+	return *f, nil
+}
+
+// parseSignedDuration parses a duration such as "1d" or "-5m".
+func parseSignedDuration(s string) (time.Duration, error) {
+	if len(s) > 0 && s[0] == '-' {
+		d, err := gtime.ParseDuration(s[1:])
+		return -d, err
+	}
+	return gtime.ParseDuration(s)
+}
+
+// checkDurationArg returns a parse time check that the string argument at argIdx is a valid
+// duration. Unless allowNegative is set, the duration must be positive.
+func checkDurationArg(argIdx int, allowNegative bool) func(*parse.Tree, *parse.FuncNode) error {
+	return func(_ *parse.Tree, f *parse.FuncNode) error {
+		arg, ok := f.Args[argIdx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", argIdx, f.Name)
+		}
+		d, err := parseSignedDuration(arg.Text)
+		if err != nil {
+			return fmt.Errorf("parse: invalid duration %q for argument %v of %s: %w", arg.Text, argIdx, f.Name, err)
+		}
+		if d == 0 {
+			return fmt.Errorf("parse: duration for argument %v of %s must not be zero", argIdx, f.Name)
+		}
+		if d < 0 && !allowNegative {
+			return fmt.Errorf("parse: duration for argument %v of %s must be positive", argIdx, f.Name)
+		}
+		return nil
+	}
+}
@@ -140,3 +140,151 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	series := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(8)}),
+		),
+	}
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "rate on series",
+			expr: "rate($A)",
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(0.2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil}),
+			),
+		},
+		{
+			name: "delta on series",
+			expr: "delta($A)",
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil}),
+			),
+		},
+		{
+			name: "delta on series with a single point",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(1)})),
+			},
+			results: resultValuesNoErr(makeSeries("", nil)),
+		},
+		{
+			name: "cumulative_sum on series",
+			expr: "cumulative_sum($A)",
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(4)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(12)}),
+			),
+		},
+		{
+			name: "moving_avg on series",
+			expr: `moving_avg($A, "20s")`,
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(3)},
+					tp{time.Unix(30, 0), float64Pointer(8)}),
+			),
+		},
+		{
+			name: "shift on series",
+			expr: `shift($A, "1d")`,
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0).Add(24 * time.Hour), float64Pointer(1)},
+					tp{time.Unix(10, 0).Add(24 * time.Hour), float64Pointer(3)},
+					tp{time.Unix(20, 0).Add(24 * time.Hour), nil},
+					tp{time.Unix(30, 0).Add(24 * time.Hour), float64Pointer(8)}),
+			),
+		},
+		{
+			name: "shift backward on series",
+			expr: `shift($A, "-10s")`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeSeries("", nil, tp{time.Unix(10, 0), float64Pointer(1)})),
+			},
+			results: resultValuesNoErr(makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(1)})),
+		},
+		{
+			name: "clamp_min and clamp_max on series",
+			expr: "clamp_max(clamp_min($A, 2), 5)",
+			vars: series,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(5)}),
+			),
+		},
+		{
+			name:    "clamp_min on scalar",
+			expr:    "clamp_min(-3, 0)",
+			vars:    Vars{},
+			results: resultValuesNoErr(NewScalar("", float64Pointer(0))),
+		},
+		{
+			name:    "rate on no data",
+			expr:    "rate($A)",
+			vars:    Vars{"A": Results{Values: []Value{NewNoData()}}},
+			results: Results{Values: []Value{NewNoData()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				require.NoError(t, err)
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestSeriesFuncsParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		`moving_avg($A)`,
+		`moving_avg($A, "1x")`,
+		`moving_avg($A, "-5m")`,
+		`shift($A, "0s")`,
+		`shift($A, 5)`,
+		`clamp_min($A, "1")`,
+		`rate(1)`,
+		`moving_avg($A,,"5m")`,
+		`moving_avg($A, "5m",)`,
+		`abs(,$A)`,
+		`moving_avg($A "5m")`,
+		`clamp_min($A 1)`,
+	} {
+		_, err := New(expr)
+		require.Error(t, err, expr)
+	}
+}
@@ -314,23 +314,32 @@ func (t *Tree) Func() (f *FuncNode) {
 	}
 	f = newFunc(token.pos, token.val, funcv)
 	t.expect(itemLeftParen, "func")
+	if t.peek().typ == itemRightParen {
+		t.next()
+		return
+	}
+	// arguments are separated by commas
 	for {
-		switch token = t.next(); token.typ {
-		default:
+		if token = t.next(); token.typ == itemString {
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			f.append(newString(token.pos, token.val, s))
+		} else {
 			t.backup()
 			node := t.O()
 			f.append(node)
 			if len(f.Args) == 1 && f.F.VariantReturn {
 				f.F.Return = node.Return()
 			}
-		case itemString:
-			s, err := strconv.Unquote(token.val)
-			if err != nil {
-				t.errorf("Unquoting error: %s", err)
-			}
-			f.append(newString(token.pos, token.val, s))
+		}
+		switch token = t.next(); token.typ {
+		case itemComma:
 		case itemRightParen:
 			return
+		default:
+			t.unexpected(token, "func")
 		}
 	}
 }
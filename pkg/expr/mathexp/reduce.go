@@ -3,6 +3,8 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,8 +83,129 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// CountNonNull returns the number of points that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Median returns the middle value of the points, or the mean of the two middle values
+// if the number of points is even.
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a ReducerFunc that computes the p-th percentile of the points,
+// interpolating linearly between the two closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := sortedValues(fv)
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// StdDev returns the population standard deviation of the points.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Delta returns the difference between the last and the first point.
+func Delta(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil || math.IsNaN(*first) || math.IsNaN(*last) {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Rate returns the per-second rate of change between the first and the last point of the series.
+func Rate(s Series) *float64 {
+	nan := math.NaN()
+	if s.Len() < 2 {
+		return &nan
+	}
+	fv := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+	delta := Delta(&fv)
+	if math.IsNaN(*delta) {
+		return delta
+	}
+	seconds := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if seconds == 0 {
+		return &nan
+	}
+	f := *delta / seconds
+	return &f
+}
+
+// sortedValues returns the values of the field in ascending order. It returns false
+// if any of the values is null or NaN.
+func sortedValues(fv *Float64Field) ([]float64, bool) {
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	sort.Float64s(values)
+	return values, true
+}
+
+// SeriesReducerFunc reduces a whole series, so unlike ReducerFunc it has access to the time of the points.
+type SeriesReducerFunc = func(s Series) *float64
+
+// GetReduceFunc returns the reducer function for the name. Percentiles are requested
+// with the percentile(p) form, where p is between 0 and 100.
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
-	switch strings.ToLower(rFunc) {
+	name := strings.ToLower(strings.TrimSpace(rFunc))
+	if strings.HasPrefix(name, "percentile") {
+		p, err := parsePercentile(name)
+		if err != nil {
+			return nil, err
+		}
+		return Percentile(p), nil
+	}
+	switch name {
 	case "sum":
 		return Sum, nil
 	case "mean":
@@ -95,14 +218,53 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return StdDev, nil
+	case "delta":
+		return Delta, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
-		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
+		return nil, fmt.Errorf("reduction %v not implemented, supported reductions are %s", rFunc, strings.Join(GetSupportedReduceFuncs(), ", "))
 	}
 }
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSeriesReduceFunc returns the series reducer function for the name. It supports
+// all the reducers of GetReduceFunc and the ones that need the time of the points.
+func GetSeriesReduceFunc(rFunc string) (SeriesReducerFunc, error) {
+	if strings.EqualFold(strings.TrimSpace(rFunc), "rate") {
+		return Rate, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, err
+	}
+	return func(s Series) *float64 {
+		fv := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+		return reduceFunc(&fv)
+	}, nil
+}
+
+func parsePercentile(name string) (float64, error) {
+	arg := strings.TrimPrefix(name, "percentile")
+	if !strings.HasPrefix(arg, "(") || !strings.HasSuffix(arg, ")") {
+		return 0, fmt.Errorf("reduction %v must be in the form percentile(p)", name)
+	}
+	p, err := strconv.ParseFloat(strings.TrimSpace(arg[1:len(arg)-1]), 64)
+	if err != nil || p < 0 || p > 100 {
+		return 0, fmt.Errorf("percentile in reduction %v must be a number between 0 and 100", name)
+	}
+	return p, nil
+}
+
+// GetSupportedReduceFuncs returns collection of supported function names. Percentiles need
+// a parameter, so they are listed with the 95th percentile, but any p between 0 and 100 is supported.
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "percentile(95)", "stddev", "delta", "rate", "count_non_null"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
@@ -119,13 +281,11 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
@@ -21,6 +21,16 @@ var seriesWithNil = Vars{
 	),
 }
 
+var seriesToStats = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil,
+			tp{time.Unix(0, 0), float64Pointer(1)},
+			tp{time.Unix(10, 0), float64Pointer(4)},
+			tp{time.Unix(20, 0), float64Pointer(2)},
+			tp{time.Unix(30, 0), float64Pointer(3)}),
+	),
+}
+
 var seriesEmpty = Vars{
 	"A": resultValuesNoErr(
 		makeSeries("temp", nil),
@@ -179,6 +189,157 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "median series",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2.5))),
+		},
+		{
+			name:        "median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "median empty series",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "percentile series",
+			red:         "percentile(90)",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(3.7))),
+		},
+		{
+			name:        "percentile 0 is the min",
+			red:         "percentile(0)",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(math.Sqrt(1.25)))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "delta series",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "delta series with a nil value",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "rate series",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2.0/30))),
+		},
+		{
+			name:        "rate empty series",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "count_non_null empty series",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0))),
+		},
+		{
+			name:        "percentile without a parameter will error",
+			red:         "percentile",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "percentile out of range will error",
+			red:         "percentile(101)",
+			varToReduce: "A",
+			vars:        seriesToStats,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,6 +375,13 @@ var seriesNonNumbers = Vars{
 	),
 }
 
+func TestGetSupportedReduceFuncs(t *testing.T) {
+	for _, name := range GetSupportedReduceFuncs() {
+		_, err := GetSeriesReduceFunc(name)
+		require.NoErrorf(t, err, "supported reducer %s", name)
+	}
+}
+
 func TestSeriesReduceDropNN(t *testing.T) {
 	var tests = []struct {
 		name        string
@@ -278,6 +446,34 @@ func TestSeriesReduceDropNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
 		},
+		{
+			name:        "DropNN: median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "DropNN: stddev series that becomes empty after filtering non-number",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
+		{
+			name:        "DropNN: first series with a nil value",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "DropNN: rate series that has a single point after filtering non-number",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
 	}
 
 	for _, tt := range tests {
@@ -365,6 +561,20 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
 		},
+		{
+			name:        "replaceNN: delta series with a nil value",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(replaceWith-2))),
+		},
+		{
+			name:        "replaceNN: count_non_null series with a nil value counts the replaced value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
 	}
 
 	for _, tt := range tests {
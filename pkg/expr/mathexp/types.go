@@ -11,6 +11,9 @@ import (
 type Results struct {
 	Values Values
 	Error  error
+	// Tables are the frames of a data source query as they were returned, kept for SQL
+	// expressions when Values holds the frames converted for other expressions.
+	Tables Values
 }
 
 // IsNoData checks whether the result contains NoData value
@@ -246,3 +249,46 @@ func (s NoData) New() NoData {
 func NewNoData() NoData {
 	return NoData{data.NewFrame("no data")}
 }
+
+// TableData is a tabular frame as it was returned by a data source or an expression.
+// Its schema is not restricted to numbers or time series so it can only be consumed
+// by commands that operate on whole tables, such as the SQL command.
+type TableData struct{ Frame *data.Frame }
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (t TableData) Type() parse.ReturnType { return parse.TypeTableData }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (t TableData) Value() any { return t }
+
+func (t TableData) GetLabels() data.Labels { return nil }
+
+func (t TableData) SetLabels(ls data.Labels) {}
+
+func (t TableData) GetMeta() any {
+	if t.Frame.Meta == nil {
+		return nil
+	}
+	return t.Frame.Meta.Custom
+}
+
+func (t TableData) SetMeta(v any) {
+	m := t.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		t.Frame.SetMeta(m)
+	}
+	m.Custom = v
+}
+
+func (t TableData) AddNotice(notice data.Notice) {
+	m := t.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		t.Frame.SetMeta(m)
+	}
+	m.Notices = append(m.Notices, notice)
+}
+
+// AsDataFrame returns the underlying *data.Frame.
+func (t TableData) AsDataFrame() *data.Frame { return t.Frame }
@@ -121,6 +121,16 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
+	case TypeSQL:
+		if !toggles.IsEnabledGlobally(featuremgmt.FlagSqlExpressions) {
+			return nil, fmt.Errorf("sql expressions are disabled, enable the %s feature toggle to use expression '%v'", featuremgmt.FlagSqlExpressions, rn.RefID)
+		}
+		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeForecast:
+		if !toggles.IsEnabledGlobally(featuremgmt.FlagForecastExpressions) {
+			return nil, fmt.Errorf("forecast expressions are disabled, enable the %s feature toggle to use expression '%v'", featuremgmt.FlagForecastExpressions, rn.RefID)
+		}
+		node.Command, err = UnmarshalForecastCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -148,6 +158,13 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is set when the results of the query are used as a table by
+	// a SQL expression, in which case the frames are kept as they are, and
+	// isInputToOtherExpr when they are used by other expressions, which need the
+	// frames converted to numbers or series.
+	isInputToSQLExpr   bool
+	isInputToOtherExpr bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -288,10 +305,9 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
-				var result mathexp.Results
-				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
+				responseType, result, err := dn.framesToResults(ctx, dataFrames, s, logger)
 				if err != nil {
-					result.Error = makeConversionError(dn.RefID(), err)
+					result.Error = err
 				}
 				instrument(err, responseType)
 				vars[dn.refID] = result
@@ -357,11 +373,39 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	}
 
 	var result mathexp.Results
-	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
+	responseType, result, err = dn.framesToResults(ctx, dataFrames, s, logger)
+	return result, err
+}
+
+// framesToResults converts the frames of the response to the shapes needed by the
+// expressions which use the query: tables for SQL expressions, and numbers or series
+// for other expressions. When both are needed, the tables are kept in the Tables of
+// the results.
+func (dn *DSNode) framesToResults(ctx context.Context, frames data.Frames, s *Service, logger log.Logger) (string, mathexp.Results, error) {
+	if dn.isInputToSQLExpr && !dn.isInputToOtherExpr {
+		return "table", framesToTableResults(frames), nil
+	}
+
+	responseType, result, err := convertDataFramesToResults(ctx, frames, dn.datasource.Type, s, logger)
 	if err != nil {
-		err = makeConversionError(dn.refID, err)
+		return responseType, result, makeConversionError(dn.refID, err)
 	}
-	return result, err
+	if dn.isInputToSQLExpr {
+		result.Tables = framesToTableResults(frames).Values
+	}
+	return responseType, result, nil
+}
+
+// framesToTableResults keeps the frames of a data source response as tables.
+func framesToTableResults(frames data.Frames) mathexp.Results {
+	if len(frames) == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}
+	}
+	vals := make(mathexp.Values, 0, len(frames))
+	for _, frame := range frames {
+		vals = append(vals, mathexp.TableData{Frame: frame})
+	}
+	return mathexp.Results{Values: vals}
 }
 
 func getResponseFrame(resp *backend.QueryDataResponse, refID string) (data.Frames, error) {
@@ -559,7 +603,10 @@ func extractNumberSet(frame *data.Frame) ([]mathexp.Number, error) {
 				labels = make(data.Labels)
 			}
 			key := stringFieldNames[i] // TODO check for duplicate string column names
-			val, _ := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			val, ok := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			if !ok {
+				continue // null values do not become labels
+			}
 			labels[key] = val.(string) // TODO check assertion / return error
 		}
 
@@ -0,0 +1,378 @@
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrNotSelect is returned when the query is not a single read-only SELECT statement.
+	ErrNotSelect = errors.New("only a single SELECT statement is supported")
+)
+
+// DB runs SQL queries over data frames. Every query is executed in a new in-memory
+// SQLite database where each input frame is loaded as a table, so no state is
+// shared between queries.
+type DB struct{}
+
+// NewInMemoryDB returns a DB that loads frames into a transient in-memory database.
+func NewInMemoryDB() *DB {
+	return &DB{}
+}
+
+// QueryFrames runs the query with each of the frames in tables registered as a table
+// named after its key and returns the result as a frame named name.
+func (db *DB) QueryFrames(ctx context.Context, name, query string, tables map[string]*data.Frame) (*data.Frame, error) {
+	if err := validateSelect(query); err != nil {
+		return nil, err
+	}
+
+	conn, err := gosql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// Every connection of the pool would get its own in-memory database.
+	conn.SetMaxOpenConns(1)
+	defer func() { _ = conn.Close() }()
+
+	names := make([]string, 0, len(tables))
+	for tableName := range tables {
+		names = append(names, tableName)
+	}
+	sort.Strings(names)
+	for _, tableName := range names {
+		if err := loadFrame(ctx, conn, tableName, tables[tableName]); err != nil {
+			return nil, fmt.Errorf("failed to load table %s: %w", tableName, err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	frame, err := rowsToFrame(name, rows)
+	if err != nil {
+		return nil, err
+	}
+	return frame, rows.Err()
+}
+
+// validateSelect makes sure the query is exactly one SELECT (or WITH ... SELECT) statement.
+func validateSelect(query string) error {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 || !(tokens[0].is("SELECT") || tokens[0].is("WITH")) {
+		return ErrNotSelect
+	}
+	for i, t := range tokens {
+		if t.kind == tokenPunct && t.text == ";" && i != len(tokens)-1 {
+			return ErrNotSelect
+		}
+	}
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columnNames returns unique, non-empty column names for the fields of the frame.
+func columnNames(frame *data.Frame) []string {
+	names := make([]string, len(frame.Fields))
+	seen := map[string]int{}
+	for i, field := range frame.Fields {
+		name := field.Name
+		if name == "" {
+			name = fmt.Sprintf("field%d", i)
+		}
+		if n, ok := seen[strings.ToLower(name)]; ok {
+			seen[strings.ToLower(name)] = n + 1
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[strings.ToLower(name)] = 1
+		names[i] = name
+	}
+	return names
+}
+
+func columnType(ft data.FieldType) string {
+	switch {
+	case ft.Time():
+		return "TIMESTAMP"
+	case ft == data.FieldTypeBool || ft == data.FieldTypeNullableBool:
+		return "BOOLEAN"
+	case ft == data.FieldTypeFloat32 || ft == data.FieldTypeNullableFloat32 ||
+		ft == data.FieldTypeFloat64 || ft == data.FieldTypeNullableFloat64:
+		return "REAL"
+	case ft.Numeric():
+		return "INTEGER"
+	default:
+		return "TEXT"
+	}
+}
+
+func loadFrame(ctx context.Context, conn *gosql.DB, tableName string, frame *data.Frame) error {
+	if frame == nil || len(frame.Fields) == 0 {
+		_, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (value REAL)", quoteIdentifier(tableName)))
+		return err
+	}
+
+	names := columnNames(frame)
+	columns := make([]string, len(frame.Fields))
+	placeholders := make([]string, len(frame.Fields))
+	for i, field := range frame.Fields {
+		columns[i] = quoteIdentifier(names[i]) + " " + columnType(field.Type())
+		placeholders[i] = "?"
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(tableName), strings.Join(columns, ", "))); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdentifier(tableName), strings.Join(placeholders, ", ")))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	args := make([]any, len(frame.Fields))
+	for row := 0; row < frame.Rows(); row++ {
+		for i, field := range frame.Fields {
+			args[i], err = sqlValue(field, row)
+			if err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func sqlValue(field *data.Field, row int) (any, error) {
+	v, ok := field.ConcreteAt(row)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	switch val := v.(type) {
+	case json.RawMessage:
+		return string(val), nil
+	case data.EnumItemIndex:
+		if field.Config != nil && field.Config.TypeConfig != nil && field.Config.TypeConfig.Enum != nil {
+			text := field.Config.TypeConfig.Enum.Text
+			if int(val) < len(text) {
+				return text[val], nil
+			}
+		}
+		return int64(val), nil
+	case time.Time:
+		return val.UTC(), nil
+	case uint64:
+		if val > 1<<63-1 {
+			return float64(val), nil
+		}
+		return int64(val), nil
+	default:
+		return val, nil
+	}
+}
+
+// rowsToFrame reads all rows into a frame. Columns with a declared type, such as
+// columns selected directly from an input table, keep that type. The type of computed
+// columns is inferred from their values.
+func rowsToFrame(name string, rows *gosql.Rows) (*data.Frame, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for rows.Next() {
+		row := make([]any, len(columnTypes))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			values[i] = append(values[i], v)
+		}
+	}
+
+	frame := data.NewFrame(name)
+	for i, ct := range columnTypes {
+		field, err := buildField(ct.Name(), strings.ToUpper(ct.DatabaseTypeName()), values[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", ct.Name(), err)
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame, nil
+}
+
+func buildField(name, declaredType string, values []any) (*data.Field, error) {
+	kind := declaredType
+	if kind == "" {
+		kind = inferType(values)
+	}
+
+	switch kind {
+	case "INTEGER":
+		vals := make([]*int64, len(values))
+		for i, v := range values {
+			switch n := v.(type) {
+			case nil:
+			case int64:
+				vals[i] = &n
+			case float64:
+				i64 := int64(n)
+				vals[i] = &i64
+			case bool:
+				var i64 int64
+				if n {
+					i64 = 1
+				}
+				vals[i] = &i64
+			default:
+				return nil, fmt.Errorf("unexpected value of type %T in integer column", v)
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case "REAL":
+		vals := make([]*float64, len(values))
+		for i, v := range values {
+			switch n := v.(type) {
+			case nil:
+			case float64:
+				vals[i] = &n
+			case int64:
+				f := float64(n)
+				vals[i] = &f
+			default:
+				return nil, fmt.Errorf("unexpected value of type %T in real column", v)
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case "BOOLEAN":
+		vals := make([]*bool, len(values))
+		for i, v := range values {
+			switch b := v.(type) {
+			case nil:
+			case bool:
+				vals[i] = &b
+			case int64:
+				t := b != 0
+				vals[i] = &t
+			default:
+				return nil, fmt.Errorf("unexpected value of type %T in boolean column", v)
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	case "TIMESTAMP", "DATETIME":
+		vals := make([]*time.Time, len(values))
+		for i, v := range values {
+			switch t := v.(type) {
+			case nil:
+			case time.Time:
+				vals[i] = &t
+			case string:
+				parsed, ok := parseTimestamp(t)
+				if !ok {
+					return nil, fmt.Errorf("unable to parse %q as timestamp", t)
+				}
+				vals[i] = &parsed
+			default:
+				return nil, fmt.Errorf("unexpected value of type %T in timestamp column", v)
+			}
+		}
+		return data.NewField(name, nil, vals), nil
+	default:
+		vals := make([]*string, len(values))
+		for i, v := range values {
+			if v == nil {
+				continue
+			}
+			s := fmt.Sprintf("%v", v)
+			vals[i] = &s
+		}
+		return data.NewField(name, nil, vals), nil
+	}
+}
+
+// inferType returns the column type that fits all the non-null values.
+func inferType(values []any) string {
+	kind := ""
+	for _, v := range values {
+		var k string
+		switch t := v.(type) {
+		case nil:
+			continue
+		case int64:
+			k = "INTEGER"
+		case float64:
+			k = "REAL"
+		case bool:
+			k = "BOOLEAN"
+		case time.Time:
+			k = "TIMESTAMP"
+		case string:
+			k = "TEXT"
+			// Timestamps lose their type when they go through functions such as max().
+			if _, ok := parseTimestamp(t); ok {
+				k = "TIMESTAMP"
+			}
+		default:
+			k = "TEXT"
+		}
+		switch {
+		case kind == "" || kind == k:
+			kind = k
+		case (kind == "INTEGER" && k == "REAL") || (kind == "REAL" && k == "INTEGER"):
+			kind = "REAL"
+		default:
+			return "TEXT"
+		}
+	}
+	if kind == "" {
+		return "REAL"
+	}
+	return kind
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, format := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
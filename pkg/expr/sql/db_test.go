@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFrames(t *testing.T) {
+	ts := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{ts, ts.Add(time.Minute), ts.Add(2 * time.Minute)}),
+		data.NewField("host", nil, []*string{strP("a"), strP("a"), nil}),
+		data.NewField("value", nil, []float64{1, 2, 3}),
+	)
+
+	db := NewInMemoryDB()
+
+	t.Run("should keep column types", func(t *testing.T) {
+		f, err := db.QueryFrames(context.Background(), "B", "SELECT time, host, value FROM A ORDER BY time", map[string]*data.Frame{"A": metrics})
+		require.NoError(t, err)
+		require.Equal(t, "B", f.Name)
+		require.Equal(t, 3, f.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, f.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableString, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, f.Fields[2].Type())
+		tm, _ := f.Fields[0].ConcreteAt(1)
+		require.True(t, ts.Add(time.Minute).Equal(tm.(time.Time)))
+		require.Nil(t, f.Fields[1].At(2))
+	})
+
+	t.Run("should infer types of computed columns", func(t *testing.T) {
+		f, err := db.QueryFrames(context.Background(), "B", "SELECT max(time) AS last, count(*) AS c, avg(value) AS a FROM A", map[string]*data.Frame{"A": metrics})
+		require.NoError(t, err)
+		require.Equal(t, data.FieldTypeNullableTime, f.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableInt64, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, f.Fields[2].Type())
+		c, _ := f.Fields[1].ConcreteAt(0)
+		require.Equal(t, int64(3), c)
+	})
+
+	t.Run("should only allow a single select", func(t *testing.T) {
+		for _, q := range []string{
+			"DROP TABLE A",
+			"INSERT INTO A (value) VALUES (1)",
+			"SELECT * FROM A; DELETE FROM A",
+		} {
+			_, err := db.QueryFrames(context.Background(), "B", q, map[string]*data.Frame{"A": metrics})
+			require.ErrorIs(t, err, ErrNotSelect, q)
+		}
+	})
+
+	t.Run("should create an empty table for empty frames", func(t *testing.T) {
+		f, err := db.QueryFrames(context.Background(), "B", "SELECT count(*) AS c FROM A", map[string]*data.Frame{"A": data.NewFrame("")})
+		require.NoError(t, err)
+		c, _ := f.Fields[0].ConcreteAt(0)
+		require.Equal(t, int64(0), c)
+	})
+}
+
+func strP(s string) *string {
+	return &s
+}
@@ -0,0 +1,188 @@
+package sql
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// clauseKeywords terminate the list of tables that follows a FROM keyword.
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "LIMIT": true, "HAVING": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"CROSS": true, "OUTER": true, "NATURAL": true, "ON": true, "USING": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "WINDOW": true, "OFFSET": true,
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuoted
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isIdentifier() bool {
+	if t.kind == tokenQuoted {
+		return true
+	}
+	return t.kind == tokenWord && !clauseKeywords[strings.ToUpper(t.text)] && !strings.EqualFold(t.text, "SELECT")
+}
+
+// TablesList returns the names of the tables referenced by the query in the
+// order they first appear. Names of common table expressions that are defined
+// by the query itself are not included.
+func TablesList(rawSQL string) ([]string, error) {
+	tokens, err := tokenize(rawSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctes := map[string]bool{}
+	for i := 0; i+2 < len(tokens); i++ {
+		// name AS ( ... ) defines a common table expression.
+		if tokens[i].isIdentifier() && tokens[i+1].is("AS") && tokens[i+2].text == "(" {
+			ctes[tokens[i].text] = true
+		}
+	}
+
+	// position of the first reference to each table
+	positions := map[string]int{}
+	add := func(name string, pos int) {
+		if ctes[name] {
+			return
+		}
+		if p, ok := positions[name]; !ok || pos < p {
+			positions[name] = pos
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("FROM") && !tokens[i].is("JOIN") {
+			continue
+		}
+		isFrom := tokens[i].is("FROM")
+		j := i + 1
+		for j < len(tokens) {
+			if tokens[j].isIdentifier() {
+				add(tokens[j].text, j)
+			}
+			if !isFrom {
+				break
+			}
+			// Skip the alias and any parenthesized subquery until the next
+			// table of the FROM list, or the end of the list.
+			depth := 0
+			next := false
+		scan:
+			for ; j < len(tokens); j++ {
+				switch {
+				case tokens[j].text == "(" && tokens[j].kind == tokenPunct:
+					depth++
+				case tokens[j].text == ")" && tokens[j].kind == tokenPunct:
+					if depth == 0 {
+						break scan
+					}
+					depth--
+				case depth > 0:
+				case tokens[j].text == "," && tokens[j].kind == tokenPunct:
+					next = true
+					j++
+					break scan
+				case tokens[j].text == ";" && tokens[j].kind == tokenPunct:
+					break scan
+				case tokens[j].kind == tokenWord && clauseKeywords[strings.ToUpper(tokens[j].text)]:
+					break scan
+				}
+			}
+			if !next {
+				break
+			}
+		}
+	}
+
+	tables := make([]string, 0, len(positions))
+	for name := range positions {
+		tables = append(tables, name)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return positions[tables[i]] < positions[tables[j]]
+	})
+	return tables, nil
+}
+
+// tokenize splits a SQL statement into words, quoted identifiers and punctuation.
+// String literals and comments are dropped.
+func tokenize(rawSQL string) ([]token, error) {
+	var tokens []token
+	runes := []rune(rawSQL)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && (runes[i] != '*' || runes[i+1] != '/') {
+				i++
+			}
+			if i+1 >= len(runes) {
+				return nil, errors.New("unterminated comment")
+			}
+			i += 2
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == closing {
+					// A doubled quote is an escaped quote.
+					if i+1 < len(runes) && runes[i+1] == closing && closing != ']' {
+						sb.WriteRune(closing)
+						i += 2
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, errors.New("unterminated quoted string or identifier")
+			}
+			if r != '\'' {
+				tokens = append(tokens, token{kind: tokenQuoted, text: sb.String()})
+			}
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
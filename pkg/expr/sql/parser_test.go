@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablesList(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "single table",
+			query:    "SELECT * FROM A",
+			expected: []string{"A"},
+		},
+		{
+			name:     "table list with aliases",
+			query:    "SELECT * FROM A AS a, B b WHERE a.x = b.x",
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "joins",
+			query:    "SELECT * FROM A LEFT JOIN B ON A.x = B.x INNER JOIN C USING (x)",
+			expected: []string{"A", "B", "C"},
+		},
+		{
+			name:     "subquery",
+			query:    "SELECT * FROM (SELECT x FROM A) s, B",
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "common table expressions are not inputs",
+			query:    "WITH t AS (SELECT x FROM A) SELECT * FROM t JOIN B ON t.x = B.x",
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "quoted identifiers, strings and comments",
+			query:    "SELECT 'FROM X' FROM \"my query\" -- FROM Y\n /* FROM Z */",
+			expected: []string{"my query"},
+		},
+		{
+			name:     "duplicates are listed once",
+			query:    "SELECT * FROM A UNION SELECT * FROM A",
+			expected: []string{"A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := TablesList(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tables)
+		})
+	}
+
+	t.Run("unterminated string", func(t *testing.T) {
+		_, err := TablesList("SELECT 'x FROM A")
+		require.Error(t, err)
+	})
+}
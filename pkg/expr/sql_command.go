@@ -0,0 +1,216 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// SQLCommand is an expression command that runs a SQL SELECT statement over the
+// results of other queries or expressions. Each input refID is exposed as a table.
+type SQLCommand struct {
+	query       string
+	varsToQuery []string
+	refID       string
+}
+
+// NewSQLCommand creates a new SQLCommand. It will return an error if the
+// statement is not valid or it does not reference any input.
+func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
+	if rawSQL == "" {
+		return nil, errors.New("sql expression is missing a query")
+	}
+	tables, err := sql.TablesList(rawSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sql expression: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("sql expression must query from at least one query or expression")
+	}
+	return &SQLCommand{
+		query:       rawSQL,
+		varsToQuery: tables,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	rawExpr, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("sql command is missing an expression")
+	}
+	expression, ok := rawExpr.(string)
+	if !ok {
+		return nil, fmt.Errorf("sql expression is expected to be a string, got %T", rawExpr)
+	}
+	return NewSQLCommand(rn.RefID, expression)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gr *SQLCommand) NeedsVars() []string {
+	return gr.varsToQuery
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gr *SQLCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteSQL")
+	span.SetAttributes(attribute.String("expression", gr.query))
+	defer span.End()
+
+	tables := make(map[string]*data.Frame, len(gr.varsToQuery))
+	for _, ref := range gr.varsToQuery {
+		values := vars[ref].Values
+		if tables := vars[ref].Tables; tables != nil {
+			values = tables
+		}
+		frame, err := valuesToTable(ref, values)
+		if err != nil {
+			return mathexp.Results{}, fmt.Errorf("failed to convert %s to a table: %w", ref, err)
+		}
+		tables[ref] = frame
+	}
+
+	frame, err := sql.NewInMemoryDB().QueryFrames(ctx, gr.refID, gr.query, tables)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute sql expression %s: %w", gr.refID, err)
+	}
+	frame.RefID = gr.refID
+
+	if frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+	}
+
+	// Results in the shape of a number table, such as the result of a GROUP BY, are turned
+	// into numbers so they can be used by other expressions and as an alert condition.
+	if isNumberTable(frame) {
+		numbers, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make(mathexp.Values, 0, len(numbers))
+		for _, n := range numbers {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// valuesToTable returns the results of a query or expression as a single frame.
+// Tables are used as they are. Numbers and series are converted to a long frame
+// with a column per label, so they can be filtered and grouped by their labels.
+func valuesToTable(refID string, values mathexp.Values) (*data.Frame, error) {
+	var tables []*data.Frame
+	var others mathexp.Values
+	for _, v := range values {
+		switch v := v.(type) {
+		case mathexp.TableData:
+			tables = append(tables, v.Frame)
+		case mathexp.NoData:
+		default:
+			others = append(others, v)
+		}
+	}
+
+	switch {
+	case len(tables) > 0 && len(others) > 0:
+		return nil, errors.New("mixed tables and numbers or series are not supported")
+	case len(tables) == 1:
+		return tables[0], nil
+	case len(tables) > 1:
+		return appendFrames(refID, tables)
+	}
+
+	hasTime := false
+	labelKeys := map[string]struct{}{}
+	for _, v := range others {
+		switch v.(type) {
+		case mathexp.Series:
+			hasTime = true
+		case mathexp.Number, mathexp.Scalar:
+		default:
+			return nil, fmt.Errorf("unsupported value type %s", v.Type())
+		}
+		for k := range v.GetLabels() {
+			labelKeys[k] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(labelKeys))
+	for k := range labelKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	timeField := data.NewField("time", nil, []time.Time{})
+	valueField := data.NewField("value", nil, []*float64{})
+	labelFields := make([]*data.Field, len(keys))
+	for i, k := range keys {
+		labelFields[i] = data.NewField(k, nil, []*string{})
+	}
+
+	appendRow := func(labels data.Labels, t time.Time, f *float64) {
+		timeField.Append(t)
+		valueField.Append(f)
+		for i, k := range keys {
+			var lv *string
+			if l, ok := labels[k]; ok {
+				lv = &l
+			}
+			labelFields[i].Append(lv)
+		}
+	}
+
+	for _, v := range others {
+		switch v := v.(type) {
+		case mathexp.Series:
+			for i := 0; i < v.Len(); i++ {
+				appendRow(v.GetLabels(), v.GetTime(i), v.GetValue(i))
+			}
+		case mathexp.Number:
+			appendRow(v.GetLabels(), time.Time{}, v.GetFloat64Value())
+		case mathexp.Scalar:
+			appendRow(nil, time.Time{}, v.GetFloat64Value())
+		}
+	}
+
+	frame := data.NewFrame(refID)
+	if hasTime {
+		frame.Fields = append(frame.Fields, timeField)
+	}
+	frame.Fields = append(frame.Fields, labelFields...)
+	frame.Fields = append(frame.Fields, valueField)
+	return frame, nil
+}
+
+// appendFrames concatenates the rows of frames that share the same schema.
+func appendFrames(refID string, frames []*data.Frame) (*data.Frame, error) {
+	result := frames[0].EmptyCopy()
+	result.Name = refID
+	for _, f := range frames {
+		if len(f.Fields) != len(result.Fields) {
+			return nil, fmt.Errorf("frames of %s have different schemas", refID)
+		}
+		for i, field := range f.Fields {
+			if field.Type() != result.Fields[i].Type() || field.Name != result.Fields[i].Name {
+				return nil, fmt.Errorf("frames of %s have different schemas", refID)
+			}
+		}
+		for row := 0; row < f.Rows(); row++ {
+			result.AppendRow(f.RowCopy(row)...)
+		}
+	}
+	return result, nil
+}
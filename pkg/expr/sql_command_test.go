@@ -0,0 +1,235 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewSQLCommand(t *testing.T) {
+	t.Run("should find the input tables", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT a.host, b.value FROM A a JOIN B b ON a.host = b.host")
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+	})
+
+	t.Run("should fail when there are no input tables", func(t *testing.T) {
+		_, err := NewSQLCommand("C", "SELECT 1")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail when the query is empty", func(t *testing.T) {
+		_, err := NewSQLCommand("C", "")
+		require.Error(t, err)
+	})
+}
+
+func TestSQLCommandExecute(t *testing.T) {
+	inventory := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b", "c"}),
+		data.NewField("team", nil, []string{"x", "x", "y"}),
+	)
+
+	cpuA := mathexp.NewNumber("cpu", data.Labels{"host": "a"})
+	cpuA.SetValue(fp(1))
+	cpuB := mathexp.NewNumber("cpu", data.Labels{"host": "b"})
+	cpuB.SetValue(fp(2))
+	cpuC := mathexp.NewNumber("cpu", data.Labels{"host": "c"})
+	cpuC.SetValue(fp(5))
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: inventory}}},
+		"B": mathexp.Results{Values: mathexp.Values{cpuA, cpuB, cpuC}},
+	}
+
+	t.Run("should return numbers for a number table", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT A.team, sum(B.value) AS total FROM A JOIN B ON A.host = B.host GROUP BY A.team ORDER BY A.team")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+
+		expected := map[string]float64{"x": 3, "y": 5}
+		for _, v := range res.Values {
+			n, ok := v.(mathexp.Number)
+			require.True(t, ok)
+			require.Equal(t, expected[n.GetLabels()["team"]], *n.GetFloat64Value())
+		}
+	})
+
+	t.Run("should return a table for any other result", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT host, team FROM A WHERE team = 'x'")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+		require.Equal(t, "C", table.Frame.RefID)
+		require.Equal(t, 2, table.Frame.Rows())
+	})
+
+	t.Run("should return no data when there are no rows", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT host FROM A WHERE team = 'z'")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("should convert series to a long table", func(t *testing.T) {
+		series := mathexp.NewSeries("A", data.Labels{"host": "a"}, 2)
+		series.SetPoint(0, time.Unix(1, 0), fp(1))
+		series.SetPoint(1, time.Unix(2, 0), fp(3))
+
+		cmd, err := NewSQLCommand("B", "SELECT host, max(value) AS m FROM A GROUP BY host")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{series}},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		n, ok := res.Values[0].(mathexp.Number)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a"}, n.GetLabels())
+		require.Equal(t, 3.0, *n.GetFloat64Value())
+	})
+
+	t.Run("should reject statements that are not a select", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "DELETE FROM A")
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
+
+func TestSQLExpressionPipeline(t *testing.T) {
+	inventory := data.NewFrame("inventory",
+		data.NewField("host", nil, []string{"a", "b"}),
+		data.NewField("cores", nil, []int64{4, 8}),
+		data.NewField("rack", nil, []string{"r1", "r2"}),
+	)
+
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{inventory}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeDataSourceService{}, nil, fakes.NewFakeLicensingService(), &config.Cfg{})
+
+	newService := func(features *featuremgmt.FeatureManager) *Service {
+		return &Service{
+			cfg:          setting.NewCfg(),
+			dataService:  me,
+			pCtxProvider: pCtxProvider,
+			features:     features,
+			tracer:       tracing.InitializeTracerForTest(),
+			metrics:      newMetrics(nil),
+		}
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "1" } }`),
+			TimeRange: AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT host, rack FROM A WHERE cores > 4" }`),
+		},
+	}
+	req := &Request{Queries: queries, User: &user.SignedInUser{}}
+
+	t.Run("should fail when the feature toggle is disabled", func(t *testing.T) {
+		_, err := newService(featuremgmt.WithFeatures()).BuildPipeline(req)
+		require.ErrorContains(t, err, featuremgmt.FlagSqlExpressions)
+	})
+
+	t.Run("should query the frames of the data source as a table", func(t *testing.T) {
+		s := newService(featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions))
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+
+		frames := res.Responses["B"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		host, _ := frames[0].Fields[0].ConcreteAt(0)
+		require.Equal(t, "b", host)
+	})
+
+	t.Run("should keep the tables for SQL expressions when other expressions use the query", func(t *testing.T) {
+		me.Responses["A"] = backend.DataResponse{Frames: data.Frames{data.NewFrame("load",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("value", nil, []float64{1, 3}),
+		)}}
+		mixedReq := &Request{Queries: []Query{
+			queries[0],
+			{
+				RefID:      "B",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT host FROM A WHERE value > 2" }`),
+			},
+			{
+				RefID:      "C",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+			},
+		}, User: &user.SignedInUser{}}
+
+		s := newService(featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions))
+		pl, err := s.BuildPipeline(mixedReq)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+
+		require.NoError(t, res.Responses["B"].Error)
+		frames := res.Responses["B"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		host, _ := frames[0].Fields[0].ConcreteAt(0)
+		require.Equal(t, "b", host)
+
+		require.NoError(t, res.Responses["C"].Error)
+		require.Len(t, res.Responses["C"].Frames, 2, "the math expression gets a number per host")
+	})
+}
@@ -7,3 +7,27 @@ import (
 
 // PluginFactoryFunc is a function type for creating a Plugin.
 type PluginFactoryFunc func(pluginID string, logger log.Logger, env func() []string) (Plugin, error)
+
+// ProcessLimits restricts the resources and environment of a backend plugin process.
+// Resource limits are only enforced on Linux, a zero value means no limit.
+type ProcessLimits struct {
+	// MemoryBytes is the maximum amount of memory the process can use.
+	MemoryBytes uint64
+	// CPUs is the maximum number of CPUs the process can use.
+	CPUs float64
+	// MaxOpenFiles is the maximum number of files the process can have open.
+	MaxOpenFiles uint64
+	// MaxFileSizeBytes is the maximum size of the files the process can write.
+	MaxFileSizeBytes uint64
+	// AllowedEnvVars lists the only environment variables passed to the process, a name
+	// ending with * allows all variables with that prefix.
+	AllowedEnvVars []string
+	// CgroupPath is a cgroup v2 directory in which a cgroup is created for the process to be
+	// started in. Memory and CPU limits fall back to rlimits when it is not set or cannot be used.
+	CgroupPath string
+}
+
+// Enabled returns whether any limit is set.
+func (l ProcessLimits) Enabled() bool {
+	return l.MemoryBytes > 0 || l.CPUs > 0 || l.MaxOpenFiles > 0 || l.MaxFileSizeBytes > 0 || len(l.AllowedEnvVars) > 0
+}
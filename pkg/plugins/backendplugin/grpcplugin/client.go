@@ -1,6 +1,7 @@
 package grpcplugin
 
 import (
+	"os"
 	"os/exec"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/grpcplugin"
@@ -39,12 +40,21 @@ var pluginSet = map[int]goplugin.PluginSet{
 	},
 }
 
-func newClientConfig(executablePath string, args []string, env []string, skipHostEnvVars bool, logger log.Logger,
-	versionedPlugins map[int]goplugin.PluginSet) *goplugin.ClientConfig {
+func newClientConfig(executablePath string, args []string, env []string, skipHostEnvVars bool, limits backendplugin.ProcessLimits,
+	logger log.Logger, versionedPlugins map[int]goplugin.PluginSet) *goplugin.ClientConfig {
 	// We can ignore gosec G201 here, since the dynamic part of executablePath comes from the plugin definition
 	// nolint:gosec
 	cmd := exec.Command(executablePath, args...)
 	cmd.Env = env
+	if len(limits.AllowedEnvVars) > 0 {
+		// the host environment is filtered as well, go-plugin must not add it back
+		if !skipHostEnvVars {
+			cmd.Env = append(cmd.Env, os.Environ()...)
+		}
+		cmd.Env = filterEnv(cmd.Env, limits.AllowedEnvVars)
+		skipHostEnvVars = true
+	}
+	cmd.Env = append(cmd.Env, limitsEnv(limits)...)
 
 	return &goplugin.ClientConfig{
 		Cmd:              cmd,
@@ -77,6 +87,7 @@ type PluginDescriptor struct {
 	executablePath        string
 	executableArgs        []string
 	skipHostEnvVars       bool
+	limits                backendplugin.ProcessLimits
 	managed               bool
 	versionedPlugins      map[int]goplugin.PluginSet
 	startRendererFn       StartRendererFunc
@@ -84,22 +95,23 @@ type PluginDescriptor struct {
 }
 
 // NewBackendPlugin creates a new backend plugin factory used for registering a backend plugin.
-func NewBackendPlugin(pluginID, executablePath string, skipHostEnvVars bool, executableArgs ...string) backendplugin.PluginFactoryFunc {
-	return newBackendPlugin(pluginID, executablePath, true, skipHostEnvVars, executableArgs...)
+func NewBackendPlugin(pluginID, executablePath string, skipHostEnvVars bool, limits backendplugin.ProcessLimits, executableArgs ...string) backendplugin.PluginFactoryFunc {
+	return newBackendPlugin(pluginID, executablePath, true, skipHostEnvVars, limits, executableArgs...)
 }
 
 // NewUnmanagedBackendPlugin creates a new backend plugin factory used for registering an unmanaged backend plugin.
 func NewUnmanagedBackendPlugin(pluginID, executablePath string, skipHostEnvVars bool, executableArgs ...string) backendplugin.PluginFactoryFunc {
-	return newBackendPlugin(pluginID, executablePath, false, skipHostEnvVars, executableArgs...)
+	return newBackendPlugin(pluginID, executablePath, false, skipHostEnvVars, backendplugin.ProcessLimits{}, executableArgs...)
 }
 
 // NewBackendPlugin creates a new backend plugin factory used for registering a backend plugin.
-func newBackendPlugin(pluginID, executablePath string, managed bool, skipHostEnvVars bool, executableArgs ...string) backendplugin.PluginFactoryFunc {
+func newBackendPlugin(pluginID, executablePath string, managed bool, skipHostEnvVars bool, limits backendplugin.ProcessLimits, executableArgs ...string) backendplugin.PluginFactoryFunc {
 	return newPlugin(PluginDescriptor{
 		pluginID:         pluginID,
 		executablePath:   executablePath,
 		executableArgs:   executableArgs,
 		skipHostEnvVars:  skipHostEnvVars,
+		limits:           limits,
 		managed:          managed,
 		versionedPlugins: pluginSet,
 	})
@@ -3,6 +3,7 @@ package grpcplugin
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -27,6 +28,7 @@ type grpcPlugin struct {
 	clientFactory  func() *plugin.Client
 	client         *plugin.Client
 	pluginClient   *ClientV2
+	limiter        *processLimiter
 	logger         log.Logger
 	mutex          sync.RWMutex
 	decommissioned bool
@@ -40,11 +42,18 @@ func newPlugin(descriptor PluginDescriptor) backendplugin.PluginFactoryFunc {
 }
 
 func newGrpcPlugin(descriptor PluginDescriptor, logger log.Logger, env func() []string) *grpcPlugin {
+	limiter := newProcessLimiter(descriptor.pluginID, descriptor.limits, logger)
 	return &grpcPlugin{
 		descriptor: descriptor,
 		logger:     logger,
+		limiter:    limiter,
 		clientFactory: func() *plugin.Client {
-			return plugin.NewClient(newClientConfig(descriptor.executablePath, descriptor.executableArgs, env(), descriptor.skipHostEnvVars, logger, descriptor.versionedPlugins))
+			cfg := newClientConfig(descriptor.executablePath, descriptor.executableArgs, env(), descriptor.skipHostEnvVars,
+				descriptor.limits, logger, descriptor.versionedPlugins)
+			if descriptor.limits.Enabled() {
+				limiter.prepare(cfg)
+			}
+			return plugin.NewClient(cfg)
 		},
 	}
 }
@@ -70,6 +79,15 @@ func (p *grpcPlugin) Start(_ context.Context) error {
 	if p.client.NegotiatedVersion() < 2 {
 		return errors.New("plugin protocol version not supported")
 	}
+
+	if p.descriptor.limits.Enabled() {
+		if reattach := p.client.ReattachConfig(); reattach != nil && reattach.Pid > 0 {
+			if err := p.limiter.apply(reattach.Pid, p.client.Exited); err != nil {
+				p.client.Kill()
+				return fmt.Errorf("failed to apply plugin process limits: %w", err)
+			}
+		}
+	}
 	p.pluginClient, err = newClientV2(p.descriptor, p.logger, rpcClient)
 	if err != nil {
 		return err
@@ -128,6 +146,10 @@ func (p *grpcPlugin) IsDecommissioned() bool {
 	return p.decommissioned
 }
 
+func (p *grpcPlugin) LimitViolations() map[string]int {
+	return p.limiter.Violations()
+}
+
 func (p *grpcPlugin) Target() backendplugin.Target {
 	return backendplugin.TargetLocal
 }
@@ -0,0 +1,95 @@
+package grpcplugin
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// Limits reported in the violations of a plugin process.
+const (
+	limitMemory = "memory"
+	limitCPU    = "cpu"
+)
+
+// processLimiter applies the limits of a backend plugin to its process and records the
+// violations of these limits. A plugin keeps its limiter across process restarts.
+type processLimiter struct {
+	pluginID string
+	limits   backendplugin.ProcessLimits
+	logger   log.Logger
+
+	mu         sync.RWMutex
+	violations map[string]int
+	// cgroup is the cgroup of the plugin, if one could be used, cgroupDir the open directory
+	// processes are started in and counters the last read values of its event counters.
+	cgroup    string
+	cgroupDir *os.File
+	counters  cgroupCounters
+	// inCgroup is set when the last process was started in the cgroup.
+	inCgroup bool
+	// throttled is set while the process is being throttled for exceeding its CPU limit.
+	throttled bool
+}
+
+type cgroupCounters struct {
+	oomKills  int
+	throttled int
+}
+
+func newProcessLimiter(pluginID string, limits backendplugin.ProcessLimits, logger log.Logger) *processLimiter {
+	return &processLimiter{
+		pluginID:   pluginID,
+		limits:     limits,
+		logger:     logger,
+		violations: map[string]int{},
+	}
+}
+
+// Violations returns the number of times the process exceeded each of its limits.
+func (l *processLimiter) Violations() map[string]int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	violations := make(map[string]int, len(l.violations))
+	for limit, n := range l.violations {
+		violations[limit] = n
+	}
+	return violations
+}
+
+// limitsEnv returns environment variables making the Go runtime of a plugin aware of its
+// limits, so that it collects garbage before reaching its memory limit and does not run
+// more threads than the CPUs it can use. Plugins not written in Go ignore them.
+func limitsEnv(limits backendplugin.ProcessLimits) []string {
+	var env []string
+	if limits.MemoryBytes > 0 {
+		// leave some headroom for the memory not managed by the Go runtime
+		env = append(env, fmt.Sprintf("GOMEMLIMIT=%d", limits.MemoryBytes/10*9))
+	}
+	if limits.CPUs > 0 {
+		env = append(env, fmt.Sprintf("GOMAXPROCS=%d", int(math.Ceil(limits.CPUs))))
+	}
+	return env
+}
+
+// filterEnv returns the variables of env allowed by the allow list, a name ending with *
+// allows all variables with that prefix.
+func filterEnv(env []string, allowed []string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, a := range allowed {
+			if prefix, ok := strings.CutSuffix(a, "*"); (ok && strings.HasPrefix(name, prefix)) || name == a {
+				filtered = append(filtered, kv)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,305 @@
+//go:build linux
+// +build linux
+
+package grpcplugin
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	limitsMonitorInterval = time.Second * 10
+	// cpuPeriod is the period, in microseconds, over which the CPU limit of a cgroup applies.
+	cpuPeriod = 100000
+)
+
+// prepare configures the command of the process before it is started. When a cgroup can be
+// used, the process is started in it, so that it never runs without its memory and CPU limits.
+// Otherwise the stderr of the process is watched to report memory limit violations.
+func (l *processLimiter) prepare(cfg *goplugin.ClientConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inCgroup = false
+	if l.limits.MemoryBytes == 0 && l.limits.CPUs == 0 {
+		return
+	}
+
+	if l.limits.CgroupPath != "" {
+		if err := l.openCgroup(); err != nil {
+			l.logger.Warn("Could not limit plugin process with a cgroup, falling back to rlimits", "cgroup", l.limits.CgroupPath, "error", err)
+		} else {
+			cfg.Cmd.SysProcAttr = &syscall.SysProcAttr{UseCgroupFD: true, CgroupFD: int(l.cgroupDir.Fd())}
+			l.inCgroup = true
+			return
+		}
+	}
+
+	if l.limits.MemoryBytes > 0 {
+		cfg.Stderr = &outOfMemoryWriter{limiter: l}
+	}
+}
+
+// apply applies the limits to the started process. Memory and CPU limits are enforced by the
+// cgroup the process was started in, which also allows reporting violations, and otherwise
+// with rlimits, where only the memory limit can be enforced.
+func (l *processLimiter) apply(pid int, exited func() bool) error {
+	if l.limits.MemoryBytes > 0 || l.limits.CPUs > 0 {
+		l.mu.RLock()
+		inCgroup := l.inCgroup
+		l.mu.RUnlock()
+
+		if inCgroup {
+			go l.monitor(exited)
+		} else {
+			if l.limits.MemoryBytes > 0 {
+				if err := setRlimit(pid, unix.RLIMIT_DATA, l.limits.MemoryBytes); err != nil {
+					return fmt.Errorf("failed to limit memory: %w", err)
+				}
+			}
+			if l.limits.CPUs > 0 {
+				l.logger.Warn("CPU limit of plugin process requires a cgroup, only GOMAXPROCS is set", "cpus", l.limits.CPUs)
+			}
+		}
+	}
+
+	if l.limits.MaxOpenFiles > 0 {
+		if err := setRlimit(pid, unix.RLIMIT_NOFILE, l.limits.MaxOpenFiles); err != nil {
+			return fmt.Errorf("failed to limit open files: %w", err)
+		}
+	}
+	if l.limits.MaxFileSizeBytes > 0 {
+		if err := setRlimit(pid, unix.RLIMIT_FSIZE, l.limits.MaxFileSizeBytes); err != nil {
+			return fmt.Errorf("failed to limit file size: %w", err)
+		}
+	}
+	return nil
+}
+
+func setRlimit(pid int, resource int, limit uint64) error {
+	return unix.Prlimit(pid, resource, &unix.Rlimit{Cur: limit, Max: limit}, nil)
+}
+
+// openCgroup opens the cgroup of the plugin, which is created in the configured cgroup the
+// first time a process of the plugin is started. The cgroup stays open to start every process
+// of the plugin in it.
+func (l *processLimiter) openCgroup() error {
+	if l.cgroupDir != nil {
+		return nil
+	}
+	if !supportsCloneIntoCgroup() {
+		return errors.New("starting processes in a cgroup requires Linux 5.7 or later")
+	}
+
+	dir, err := l.createCgroup()
+	if err != nil {
+		return err
+	}
+	counters, err := readCgroupCounters(dir)
+	if err != nil {
+		return err
+	}
+	// nolint:gosec
+	f, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open cgroup: %w", err)
+	}
+	l.cgroup, l.cgroupDir, l.counters = dir, f, counters
+	return nil
+}
+
+// supportsCloneIntoCgroup returns whether the kernel can start a process in a cgroup, which
+// was added in Linux 5.7.
+func supportsCloneIntoCgroup() bool {
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		return false
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(unix.ByteSliceToString(uname.Release[:]), "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+	return major > 5 || major == 5 && minor >= 7
+}
+
+func (l *processLimiter) createCgroup() (string, error) {
+	parent := l.limits.CgroupPath
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("%s is not a cgroup v2 directory: %w", parent, err)
+	}
+
+	var controllers []string
+	if l.limits.MemoryBytes > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if l.limits.CPUs > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if err := enableCgroupControllers(parent, controllers); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(parent, cgroupName(l.pluginID))
+	if err := os.Mkdir(dir, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("failed to create cgroup: %w", err)
+	}
+
+	if l.limits.MemoryBytes > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatUint(l.limits.MemoryBytes, 10)); err != nil {
+			return "", err
+		}
+		// swapping would let the process exceed its memory limit, not every host has swap accounting
+		_ = writeCgroupFile(dir, "memory.swap.max", "0")
+	}
+	if l.limits.CPUs > 0 {
+		quota := int(l.limits.CPUs * float64(cpuPeriod))
+		if err := writeCgroupFile(dir, "cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod)); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// monitor reports the violations of the cgroup limits until the process exits.
+func (l *processLimiter) monitor(exited func() bool) {
+	ticker := time.NewTicker(limitsMonitorInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		done := exited()
+		if err := l.updateViolations(); err != nil {
+			l.logger.Debug("Failed to read plugin process cgroup", "error", err)
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func (l *processLimiter) updateViolations() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counters, err := readCgroupCounters(l.cgroup)
+	if err != nil {
+		return err
+	}
+
+	if n := counters.oomKills - l.counters.oomKills; n > 0 {
+		l.violations[limitMemory] += n
+		l.logger.Error("Plugin process was killed for exceeding its memory limit", "limit", l.limits.MemoryBytes, "kills", n)
+	}
+
+	n := counters.throttled - l.counters.throttled
+	if n > 0 {
+		l.violations[limitCPU] += n
+		if !l.throttled {
+			l.logger.Warn("Plugin process is throttled for exceeding its CPU limit", "cpus", l.limits.CPUs)
+		}
+	}
+	l.throttled = n > 0
+	l.counters = counters
+	return nil
+}
+
+// outOfMemoryWriter receives the stderr of a process limited with rlimits, which, unlike a
+// cgroup, do not count violations. The Go runtime of a plugin exceeding its memory limit fails
+// to allocate memory and writes an out of memory error before exiting, which is reported once
+// per process. Plugins not written in Go are not reported.
+type outOfMemoryWriter struct {
+	limiter  *processLimiter
+	reported bool
+}
+
+func (w *outOfMemoryWriter) Write(p []byte) (int, error) {
+	if !w.reported && bytes.Contains(p, []byte("runtime: out of memory")) {
+		w.reported = true
+		w.limiter.mu.Lock()
+		w.limiter.violations[limitMemory]++
+		w.limiter.mu.Unlock()
+		w.limiter.logger.Error("Plugin process ran out of memory under its memory limit", "limit", w.limiter.limits.MemoryBytes)
+	}
+	return len(p), nil
+}
+
+func readCgroupCounters(dir string) (cgroupCounters, error) {
+	var counters cgroupCounters
+	var err error
+	if _, statErr := os.Stat(filepath.Join(dir, "memory.events")); statErr == nil {
+		if counters.oomKills, err = readCgroupCounter(dir, "memory.events", "oom_kill"); err != nil {
+			return counters, err
+		}
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "cpu.stat")); statErr == nil {
+		if counters.throttled, err = readCgroupCounter(dir, "cpu.stat", "nr_throttled"); err != nil {
+			return counters, err
+		}
+	}
+	return counters, nil
+}
+
+// readCgroupCounter reads a counter of a flat keyed cgroup file such as memory.events.
+func readCgroupCounter(dir, file, key string) (int, error) {
+	// nolint:gosec
+	f, err := os.Open(filepath.Join(dir, file))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), " ")
+		if ok && k == key {
+			return strconv.Atoi(v)
+		}
+	}
+	return 0, scanner.Err()
+}
+
+func enableCgroupControllers(dir string, controllers []string) error {
+	// nolint:gosec
+	enabled, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("failed to read cgroup controllers: %w", err)
+	}
+	for _, c := range controllers {
+		if strings.Contains(" "+strings.TrimSpace(string(enabled))+" ", " "+c+" ") {
+			continue
+		}
+		if err := writeCgroupFile(dir, "cgroup.subtree_control", "+"+c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCgroupFile(dir, file, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0); err != nil {
+		return fmt.Errorf("failed to write %s of cgroup: %w", file, err)
+	}
+	return nil
+}
+
+// cgroupName returns the name of the cgroup of a plugin, keeping only the characters of
+// the plugin ID which are safe in a path.
+func cgroupName(pluginID string) string {
+	return "grafana-plugin-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, pluginID)
+}
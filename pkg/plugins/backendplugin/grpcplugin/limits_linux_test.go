@@ -0,0 +1,142 @@
+//go:build linux
+// +build linux
+
+package grpcplugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+func TestProcessLimiter_Rlimits(t *testing.T) {
+	cmd := exec.Command("sleep", "60")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	l := newProcessLimiter("test-datasource", backendplugin.ProcessLimits{
+		MemoryBytes:      512 * 1024 * 1024,
+		MaxOpenFiles:     64,
+		MaxFileSizeBytes: 1024 * 1024,
+	}, log.NewTestLogger())
+	cfg := &goplugin.ClientConfig{Cmd: exec.Command("true")}
+	l.prepare(cfg)
+	require.Nil(t, cfg.Cmd.SysProcAttr)
+	require.NoError(t, l.apply(cmd.Process.Pid, func() bool { return false }))
+
+	limits, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(cmd.Process.Pid), "limits"))
+	require.NoError(t, err)
+	require.Regexp(t, `Max data size\s+536870912\s+536870912`, string(limits))
+	require.Regexp(t, `Max open files\s+64\s+64`, string(limits))
+	require.Regexp(t, `Max file size\s+1048576\s+1048576`, string(limits))
+
+	t.Run("Out of memory errors are reported once per process", func(t *testing.T) {
+		_, _ = cfg.Stderr.Write([]byte("runtime: out of memory: cannot allocate 4194304-byte block (536870912 in use)"))
+		_, _ = cfg.Stderr.Write([]byte("fatal error: runtime: out of memory"))
+		require.Equal(t, map[string]int{limitMemory: 1}, l.Violations())
+
+		cfg := &goplugin.ClientConfig{Cmd: exec.Command("true")}
+		l.prepare(cfg)
+		_, _ = cfg.Stderr.Write([]byte("fatal error: runtime: out of memory"))
+		require.Equal(t, map[string]int{limitMemory: 2}, l.Violations())
+	})
+}
+
+func TestProcessLimiter_Cgroup(t *testing.T) {
+	if !supportsCloneIntoCgroup() {
+		t.Skip("starting processes in a cgroup requires Linux 5.7 or later")
+	}
+
+	// a fake cgroup hierarchy, cgroupfs creates the interface files of new cgroups itself
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "grafana-plugin-test-datasource")
+	require.NoError(t, os.Mkdir(dir, 0o750))
+	writeFiles(t, parent, map[string]string{
+		"cgroup.controllers":     "cpu memory pids",
+		"cgroup.subtree_control": "",
+	})
+	writeFiles(t, dir, map[string]string{
+		"cgroup.procs":  "",
+		"memory.max":    "",
+		"cpu.max":       "",
+		"memory.events": "low 0\nhigh 0\nmax 0\noom 0\noom_kill 1\n",
+		"cpu.stat":      "usage_usec 100\nnr_periods 10\nnr_throttled 2\nthrottled_usec 50\n",
+	})
+
+	l := newProcessLimiter("test-datasource", backendplugin.ProcessLimits{
+		MemoryBytes: 512 * 1024 * 1024,
+		CPUs:        0.5,
+		CgroupPath:  parent,
+	}, log.NewTestLogger())
+	cfg := &goplugin.ClientConfig{Cmd: exec.Command("true")}
+	l.prepare(cfg)
+	require.NoError(t, l.apply(1234, func() bool { return true }))
+
+	requireFile(t, parent, "cgroup.subtree_control", "+cpu")
+	requireFile(t, dir, "memory.max", "536870912")
+	requireFile(t, dir, "cpu.max", "50000 100000")
+	// the process is started in the cgroup rather than moved to it
+	require.NotNil(t, cfg.Cmd.SysProcAttr)
+	require.True(t, cfg.Cmd.SysProcAttr.UseCgroupFD)
+	require.Equal(t, int(l.cgroupDir.Fd()), cfg.Cmd.SysProcAttr.CgroupFD)
+	requireFile(t, dir, "cgroup.procs", "")
+
+	// events which happened before the process was started in the cgroup are not violations
+	require.NoError(t, l.updateViolations())
+	require.Empty(t, l.Violations())
+
+	writeFiles(t, dir, map[string]string{
+		"memory.events": "low 0\nhigh 0\nmax 3\noom 2\noom_kill 3\n",
+		"cpu.stat":      "usage_usec 200\nnr_periods 20\nnr_throttled 7\nthrottled_usec 90\n",
+	})
+	require.NoError(t, l.updateViolations())
+	require.Equal(t, map[string]int{limitMemory: 2, limitCPU: 5}, l.Violations())
+
+	t.Run("Falls back to rlimits when the cgroup cannot be used", func(t *testing.T) {
+		cmd := exec.Command("sleep", "60")
+		require.NoError(t, cmd.Start())
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		})
+
+		l := newProcessLimiter("test-datasource", backendplugin.ProcessLimits{
+			MemoryBytes: 512 * 1024 * 1024,
+			CgroupPath:  t.TempDir(),
+		}, log.NewTestLogger())
+		cfg := &goplugin.ClientConfig{Cmd: exec.Command("true")}
+		l.prepare(cfg)
+		require.Nil(t, cfg.Cmd.SysProcAttr)
+		require.NoError(t, l.apply(cmd.Process.Pid, func() bool { return true }))
+
+		limits, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(cmd.Process.Pid), "limits"))
+		require.NoError(t, err)
+		require.Regexp(t, `Max data size\s+536870912\s+536870912`, string(limits))
+	})
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+}
+
+func requireFile(t *testing.T, dir, name, expected string) {
+	t.Helper()
+	// nolint:gosec
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+}
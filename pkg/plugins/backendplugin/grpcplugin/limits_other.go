@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package grpcplugin
+
+import goplugin "github.com/hashicorp/go-plugin"
+
+// prepare does nothing, processes are only started with limits on Linux.
+func (l *processLimiter) prepare(_ *goplugin.ClientConfig) {}
+
+// apply only logs that resource limits are not enforced, the environment of the process
+// is restricted on all platforms.
+func (l *processLimiter) apply(_ int, _ func() bool) error {
+	if l.limits.MemoryBytes > 0 || l.limits.CPUs > 0 || l.limits.MaxOpenFiles > 0 || l.limits.MaxFileSizeBytes > 0 {
+		l.logger.Warn("Resource limits of plugin processes are only enforced on Linux")
+	}
+	return nil
+}
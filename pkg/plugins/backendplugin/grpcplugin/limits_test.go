@@ -0,0 +1,44 @@
+package grpcplugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+func TestFilterEnv(t *testing.T) {
+	env := []string{"GF_PLUGIN_A=1", "GF_VERSION=10.0.0", "AWS_SECRET_ACCESS_KEY=secret", "PATH=/bin", "PATHEXT=.exe"}
+
+	require.Equal(t, []string{"GF_PLUGIN_A=1", "GF_VERSION=10.0.0", "PATH=/bin"}, filterEnv(env, []string{"GF_*", "PATH"}))
+	require.Empty(t, filterEnv(env, []string{"HOME"}))
+}
+
+func TestNewClientConfig(t *testing.T) {
+	t.Run("Environment is not restricted without allow list", func(t *testing.T) {
+		cfg := newClientConfig("/plugin", nil, []string{"GF_PLUGIN_A=1"}, false, backendplugin.ProcessLimits{}, log.NewTestLogger(), pluginSet)
+		require.Equal(t, []string{"GF_PLUGIN_A=1"}, cfg.Cmd.Env)
+		require.False(t, cfg.SkipHostEnv)
+	})
+
+	t.Run("Environment and host environment are filtered with allow list", func(t *testing.T) {
+		t.Setenv("GF_HOST_VAR", "1")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		cfg := newClientConfig("/plugin", nil, []string{"GF_PLUGIN_A=1", "GF_PLUGIN_SECRET=secret"}, false, backendplugin.ProcessLimits{
+			AllowedEnvVars: []string{"GF_PLUGIN_A", "GF_HOST_*"},
+		}, log.NewTestLogger(), pluginSet)
+		require.Equal(t, []string{"GF_PLUGIN_A=1", "GF_HOST_VAR=1"}, cfg.Cmd.Env)
+		require.True(t, cfg.SkipHostEnv)
+	})
+
+	t.Run("Go runtime is configured with the resource limits", func(t *testing.T) {
+		cfg := newClientConfig("/plugin", nil, []string{"GF_PLUGIN_A=1"}, true, backendplugin.ProcessLimits{
+			MemoryBytes: 1000,
+			CPUs:        1.5,
+		}, log.NewTestLogger(), pluginSet)
+		require.Equal(t, []string{"GF_PLUGIN_A=1", "GOMEMLIMIT=900", "GOMAXPROCS=2"}, cfg.Cmd.Env)
+		require.True(t, cfg.SkipHostEnv)
+	})
+}
@@ -26,6 +26,12 @@ type Plugin interface {
 	backend.StreamHandler
 }
 
+// LimitedPlugin is implemented by backend plugins whose process runs with resource limits.
+type LimitedPlugin interface {
+	// LimitViolations returns the number of times the process exceeded each of its limits.
+	LimitViolations() map[string]int
+}
+
 type Target string
 
 const (
@@ -66,5 +66,5 @@ var SecretsManagerProvider PluginBackendProvider = func(_ context.Context, p *pl
 }
 
 var DefaultProvider = PluginBackendProvider(func(_ context.Context, p *plugins.Plugin) backendplugin.PluginFactoryFunc {
-	return grpcplugin.NewBackendPlugin(p.ID, p.ExecutablePath(), p.SkipHostEnvVars)
+	return grpcplugin.NewBackendPlugin(p.ID, p.ExecutablePath(), p.SkipHostEnvVars, p.ProcessLimits)
 })
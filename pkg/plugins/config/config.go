@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/grafana/grafana-azure-sdk-go/azsettings"
 
 	"github.com/grafana/grafana/pkg/plugins"
@@ -46,12 +48,25 @@ type Cfg struct {
 	Features plugins.FeatureToggles
 
 	AngularSupportEnabled bool
+
+	// ProcessCgroupPath is the cgroup v2 directory in which backend plugin processes with
+	// resource limits get their own cgroup.
+	ProcessCgroupPath string
+
+	// RepositoryMirror is the directory or the URL of a plugin repository mirror used instead of grafana.com.
+	RepositoryMirror string
+
+	// HotReload enables loading the external plugins which are added, changed or removed on disk
+	// while Grafana is running, checking for changes every HotReloadInterval.
+	HotReload         bool
+	HotReloadInterval time.Duration
 }
 
 func NewCfg(devMode bool, pluginsPath string, pluginSettings setting.PluginSettings, pluginsAllowUnsigned []string,
 	awsAllowedAuthProviders []string, awsAssumeRoleEnabled bool, awsExternalId string, azure *azsettings.AzureSettings, secureSocksDSProxy setting.SecureSocksDSProxySettings,
 	grafanaVersion string, logDatasourceRequests bool, pluginsCDNURLTemplate string, appURL string, appSubURL string, tracing Tracing, features plugins.FeatureToggles, angularSupportEnabled bool,
-	grafanaComURL string, disablePlugins []string) *Cfg {
+	grafanaComURL string, disablePlugins []string, processCgroupPath string, repositoryMirror string,
+	hotReload bool, hotReloadInterval time.Duration) *Cfg {
 	return &Cfg{
 		log:                     log.New("plugin.cfg"),
 		PluginsPath:             pluginsPath,
@@ -73,5 +88,9 @@ func NewCfg(devMode bool, pluginsPath string, pluginSettings setting.PluginSetti
 		GrafanaAppSubURL:        appSubURL,
 		Features:                features,
 		AngularSupportEnabled:   angularSupportEnabled,
+		ProcessCgroupPath:       processCgroupPath,
+		RepositoryMirror:        repositoryMirror,
+		HotReload:               hotReload,
+		HotReloadInterval:       hotReloadInterval,
 	}
 }
@@ -41,6 +41,7 @@ func (i *FakePluginInstaller) Remove(ctx context.Context, pluginID string) error
 type FakeLoader struct {
 	LoadFunc   func(_ context.Context, _ plugins.PluginSource) ([]*plugins.Plugin, error)
 	UnloadFunc func(_ context.Context, _ *plugins.Plugin) (*plugins.Plugin, error)
+	ReloadFunc func(_ context.Context, _ plugins.PluginSource, _ []*plugins.Plugin) ([]*plugins.Plugin, error)
 }
 
 func (l *FakeLoader) Load(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error) {
@@ -57,6 +58,13 @@ func (l *FakeLoader) Unload(ctx context.Context, p *plugins.Plugin) (*plugins.Pl
 	return nil, nil
 }
 
+func (l *FakeLoader) Reload(ctx context.Context, src plugins.PluginSource, loaded []*plugins.Plugin) ([]*plugins.Plugin, error) {
+	if l.ReloadFunc != nil {
+		return l.ReloadFunc(ctx, src, loaded)
+	}
+	return nil, nil
+}
+
 type FakePluginClient struct {
 	ID      string
 	Managed bool
@@ -512,6 +520,9 @@ type FakeBackendPlugin struct {
 	Decommissioned bool
 	Running        bool
 
+	// CheckHealthFunc is called by CheckHealth, a healthy result is returned if not set.
+	CheckHealthFunc backend.CheckHealthHandlerFunc
+
 	// ExitedCheckDoneOrStopped is used to signal that the Exited() or Stop() method has been called.
 	ExitedCheckDoneOrStopped chan struct{}
 
@@ -569,6 +580,13 @@ func (p *FakeBackendPlugin) Exited() bool {
 	return !p.Running
 }
 
+func (p *FakeBackendPlugin) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	if p.CheckHealthFunc != nil {
+		return p.CheckHealthFunc(ctx, req)
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk}, nil
+}
+
 func (p *FakeBackendPlugin) Kill() {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -12,4 +12,7 @@ type Service interface {
 	Load(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error)
 	// Unload will unload a specified plugin from the file system.
 	Unload(ctx context.Context, p *plugins.Plugin) (*plugins.Plugin, error)
+	// Reload will load the plugins found in the provided file system paths in place of the loaded plugins. A loaded
+	// plugin is only unloaded once its new version is validated, or if the paths no longer contain it.
+	Reload(ctx context.Context, src plugins.PluginSource, loaded []*plugins.Plugin) ([]*plugins.Plugin, error)
 }
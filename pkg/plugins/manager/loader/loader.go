@@ -68,6 +68,75 @@ func (l *Loader) Unload(ctx context.Context, p *plugins.Plugin) (*plugins.Plugin
 	return l.termination.Terminate(ctx, p)
 }
 
+// Reload discovers, bootstraps and validates the plugins of the source while the loaded plugins keep running, then
+// unloads the loaded plugins that were validated again or are no longer found and initializes the validated plugins.
+// A loaded plugin whose new version fails bootstrap or validation is kept.
+func (l *Loader) Reload(ctx context.Context, src plugins.PluginSource, loaded []*plugins.Plugin) ([]*plugins.Plugin, error) {
+	end := l.instrumentLoad(ctx, src)
+
+	ids := make([]string, 0, len(loaded))
+	for _, p := range loaded {
+		ids = append(ids, p.ID)
+	}
+	discoveredPlugins, err := l.discovery.Discover(discovery.WithReplacedPlugins(ctx, ids...), src)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrappedPlugins, err := l.bootstrap.Bootstrap(ctx, src, discoveredPlugins)
+	if err != nil {
+		return nil, err
+	}
+
+	validatedPlugins, err := l.validation.Validate(ctx, bootstrappedPlugins)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := make(map[string]struct{})
+	for _, b := range discoveredPlugins {
+		discovered[b.Primary.JSONData.ID] = struct{}{}
+		for _, child := range b.Children {
+			discovered[child.JSONData.ID] = struct{}{}
+		}
+	}
+	validated := make(map[string]struct{}, len(validatedPlugins))
+	for _, p := range validatedPlugins {
+		validated[p.ID] = struct{}{}
+	}
+
+	unloadFailed := make(map[string]struct{})
+	for _, p := range loaded {
+		_, isDiscovered := discovered[p.ID]
+		_, isValidated := validated[p.ID]
+		if isDiscovered && !isValidated {
+			l.log.Warn("New version of plugin could not be validated, keeping the loaded version", "pluginId", p.ID, "version", p.Info.Version)
+			continue
+		}
+		if _, err := l.termination.Terminate(ctx, p); err != nil {
+			l.log.Error("Could not unload plugin", "pluginId", p.ID, "error", err)
+			unloadFailed[p.ID] = struct{}{}
+		}
+	}
+
+	toInitialize := make([]*plugins.Plugin, 0, len(validatedPlugins))
+	for _, p := range validatedPlugins {
+		if _, failed := unloadFailed[p.ID]; failed {
+			continue
+		}
+		toInitialize = append(toInitialize, p)
+	}
+
+	initializedPlugins, err := l.initializer.Initialize(ctx, toInitialize)
+	if err != nil {
+		return nil, err
+	}
+
+	end(initializedPlugins)
+
+	return initializedPlugins, nil
+}
+
 func (l *Loader) instrumentLoad(ctx context.Context, src plugins.PluginSource) func([]*plugins.Plugin) {
 	start := time.Now()
 	sourceLogger := l.log.New("source", src.PluginClass(ctx)).FromContext(ctx)
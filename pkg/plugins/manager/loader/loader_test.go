@@ -558,6 +558,109 @@ func TestLoader_Unload(t *testing.T) {
 	})
 }
 
+func TestLoader_Reload(t *testing.T) {
+	src := &fakes.FakePluginSource{
+		PluginClassFunc: func(ctx context.Context) plugins.Class {
+			return plugins.ClassExternal
+		},
+	}
+	newPlugin := func(id, version string) *plugins.Plugin {
+		return &plugins.Plugin{JSONData: plugins.JSONData{ID: id, Type: plugins.TypePanel, Info: plugins.Info{Version: version}}}
+	}
+	setup := func(found []string, valid func(p *plugins.Plugin) bool, terminateErr error) (*Loader, *[]string) {
+		var steps []string
+		l := New(
+			&fakes.FakeDiscoverer{
+				DiscoverFunc: func(ctx context.Context, s plugins.PluginSource) ([]*plugins.FoundBundle, error) {
+					steps = append(steps, "discover")
+					bundles := make([]*plugins.FoundBundle, 0, len(found))
+					for _, id := range found {
+						bundles = append(bundles, &plugins.FoundBundle{Primary: plugins.FoundPlugin{JSONData: plugins.JSONData{ID: id}}})
+					}
+					return bundles, nil
+				},
+			}, &fakes.FakeBootstrapper{
+				BootstrapFunc: func(ctx context.Context, s plugins.PluginSource, b []*plugins.FoundBundle) ([]*plugins.Plugin, error) {
+					steps = append(steps, "bootstrap")
+					ps := make([]*plugins.Plugin, 0, len(b))
+					for _, bundle := range b {
+						ps = append(ps, newPlugin(bundle.Primary.JSONData.ID, "2.0.0"))
+					}
+					return ps, nil
+				},
+			}, &fakes.FakeValidator{ValidateFunc: func(ctx context.Context, ps []*plugins.Plugin) ([]*plugins.Plugin, error) {
+				steps = append(steps, "validate")
+				res := make([]*plugins.Plugin, 0, len(ps))
+				for _, p := range ps {
+					if valid(p) {
+						res = append(res, p)
+					}
+				}
+				return res, nil
+			}},
+			&fakes.FakeInitializer{
+				IntializeFunc: func(ctx context.Context, ps []*plugins.Plugin) ([]*plugins.Plugin, error) {
+					for _, p := range ps {
+						steps = append(steps, "initialize "+p.ID+" v"+p.Info.Version)
+					}
+					return ps, nil
+				},
+			}, &fakes.FakeTerminator{
+				TerminateFunc: func(ctx context.Context, p *plugins.Plugin) (*plugins.Plugin, error) {
+					steps = append(steps, "terminate "+p.ID+" v"+p.Info.Version)
+					return p, terminateErr
+				},
+			})
+		return l, &steps
+	}
+	allValid := func(*plugins.Plugin) bool { return true }
+
+	t.Run("Loaded plugin is unloaded once the new version is validated", func(t *testing.T) {
+		l, steps := setup([]string{"test-panel"}, allValid, nil)
+
+		got, err := l.Reload(context.Background(), src, []*plugins.Plugin{newPlugin("test-panel", "1.0.0")})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "2.0.0", got[0].Info.Version)
+		require.Equal(t, []string{"discover", "bootstrap", "validate", "terminate test-panel v1.0.0", "initialize test-panel v2.0.0"}, *steps)
+	})
+
+	t.Run("Loaded plugin is kept when the new version fails validation", func(t *testing.T) {
+		l, steps := setup([]string{"test-panel"}, func(*plugins.Plugin) bool { return false }, nil)
+
+		got, err := l.Reload(context.Background(), src, []*plugins.Plugin{newPlugin("test-panel", "1.0.0")})
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Equal(t, []string{"discover", "bootstrap", "validate"}, *steps)
+	})
+
+	t.Run("Only the plugins whose new version is validated are replaced", func(t *testing.T) {
+		l, steps := setup([]string{"test-app", "test-app-child"}, func(p *plugins.Plugin) bool { return p.ID == "test-app" }, nil)
+
+		got, err := l.Reload(context.Background(), src, []*plugins.Plugin{newPlugin("test-app", "1.0.0"), newPlugin("test-app-child", "1.0.0")})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, []string{"discover", "bootstrap", "validate", "terminate test-app v1.0.0", "initialize test-app v2.0.0"}, *steps)
+	})
+
+	t.Run("Loaded plugin which is no longer found is unloaded", func(t *testing.T) {
+		l, steps := setup([]string{"test-panel"}, allValid, nil)
+
+		_, err := l.Reload(context.Background(), src, []*plugins.Plugin{newPlugin("test-old-panel", "1.0.0")})
+		require.NoError(t, err)
+		require.Equal(t, []string{"discover", "bootstrap", "validate", "terminate test-old-panel v1.0.0", "initialize test-panel v2.0.0"}, *steps)
+	})
+
+	t.Run("New version is not initialized when the loaded plugin could not be unloaded", func(t *testing.T) {
+		l, steps := setup([]string{"test-panel"}, allValid, errors.New("failed"))
+
+		got, err := l.Reload(context.Background(), src, []*plugins.Plugin{newPlugin("test-panel", "1.0.0")})
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Equal(t, []string{"discover", "bootstrap", "validate", "terminate test-panel v1.0.0"}, *steps)
+	})
+}
+
 func mustNewStaticFSForTests(t *testing.T, dir string) plugins.FS {
 	sfs, err := plugins.NewStaticFS(plugins.NewLocalFS(dir))
 	require.NoError(t, err)
@@ -2,11 +2,16 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
+	"github.com/dustin/go-humanize"
+
 	"github.com/grafana/grafana/pkg/infra/slugify"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/loader/assetpath"
@@ -34,6 +39,7 @@ func DefaultDecorateFuncs(cfg *config.Cfg) []DecorateFunc {
 		TemplateDecorateFunc,
 		AppChildDecorateFunc(cfg),
 		SkipHostEnvVarsDecorateFunc(cfg),
+		ProcessLimitsDecorateFunc(cfg),
 	}
 }
 
@@ -166,3 +172,54 @@ func SkipHostEnvVarsDecorateFunc(cfg *config.Cfg) DecorateFunc {
 		return p, nil
 	}
 }
+
+// ProcessLimitsDecorateFunc returns a DecorateFunc that configures the ProcessLimits field of a backend plugin
+// from the process_* keys of its plugin settings. Invalid limits prevent the plugin from being loaded, so that
+// it never runs without the limits it was configured with.
+func ProcessLimitsDecorateFunc(cfg *config.Cfg) DecorateFunc {
+	return func(_ context.Context, p *plugins.Plugin) (*plugins.Plugin, error) {
+		if !p.Backend {
+			return p, nil
+		}
+
+		limits, err := parseProcessLimits(cfg.PluginSettings[p.ID])
+		if err != nil {
+			return nil, fmt.Errorf("invalid process limits: %w", err)
+		}
+		limits.CgroupPath = cfg.ProcessCgroupPath
+		p.ProcessLimits = limits
+		return p, nil
+	}
+}
+
+func parseProcessLimits(settings map[string]string) (backendplugin.ProcessLimits, error) {
+	limits := backendplugin.ProcessLimits{}
+	var err error
+
+	if v := settings["process_memory_limit"]; v != "" {
+		if limits.MemoryBytes, err = humanize.ParseBytes(v); err != nil {
+			return limits, fmt.Errorf("process_memory_limit: %w", err)
+		}
+	}
+	if v := settings["process_cpu_limit"]; v != "" {
+		if limits.CPUs, err = strconv.ParseFloat(v, 64); err != nil || limits.CPUs < 0 {
+			return limits, fmt.Errorf("process_cpu_limit: %q is not a number of CPUs", v)
+		}
+	}
+	if v := settings["process_max_open_files"]; v != "" {
+		if limits.MaxOpenFiles, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return limits, fmt.Errorf("process_max_open_files: %w", err)
+		}
+	}
+	if v := settings["process_max_file_size"]; v != "" {
+		if limits.MaxFileSizeBytes, err = humanize.ParseBytes(v); err != nil {
+			return limits, fmt.Errorf("process_max_file_size: %w", err)
+		}
+	}
+	for _, name := range strings.Split(settings["process_env_allowlist"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			limits.AllowedEnvVars = append(limits.AllowedEnvVars, name)
+		}
+	}
+	return limits, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
@@ -209,3 +210,58 @@ func TestSkipEnvVarsDecorateFunc(t *testing.T) {
 		})
 	})
 }
+
+func TestProcessLimitsDecorateFunc(t *testing.T) {
+	const pluginID = "plugin-id"
+
+	decorate := func(settings map[string]string, backend bool) (*plugins.Plugin, error) {
+		f := ProcessLimitsDecorateFunc(&config.Cfg{
+			PluginSettings:    setting.PluginSettings{pluginID: settings},
+			ProcessCgroupPath: "/sys/fs/cgroup/grafana-plugins",
+		})
+		return f(context.Background(), &plugins.Plugin{JSONData: plugins.JSONData{ID: pluginID, Backend: backend}})
+	}
+
+	t.Run("No plugin settings should not set limits", func(t *testing.T) {
+		p, err := decorate(nil, true)
+		require.NoError(t, err)
+		require.False(t, p.ProcessLimits.Enabled())
+	})
+
+	t.Run("Plugin settings should set limits", func(t *testing.T) {
+		p, err := decorate(map[string]string{
+			"process_memory_limit":   "512MiB",
+			"process_cpu_limit":      "0.5",
+			"process_max_open_files": "1024",
+			"process_max_file_size":  "10MB",
+			"process_env_allowlist":  "GF_PLUGIN_*, PATH",
+		}, true)
+		require.NoError(t, err)
+		require.Equal(t, backendplugin.ProcessLimits{
+			MemoryBytes:      512 * 1024 * 1024,
+			CPUs:             0.5,
+			MaxOpenFiles:     1024,
+			MaxFileSizeBytes: 10 * 1000 * 1000,
+			AllowedEnvVars:   []string{"GF_PLUGIN_*", "PATH"},
+			CgroupPath:       "/sys/fs/cgroup/grafana-plugins",
+		}, p.ProcessLimits)
+	})
+
+	t.Run("Invalid plugin settings should return an error", func(t *testing.T) {
+		for _, settings := range []map[string]string{
+			{"process_memory_limit": "a lot"},
+			{"process_cpu_limit": "-1"},
+			{"process_max_open_files": "many"},
+			{"process_max_file_size": "10XB"},
+		} {
+			_, err := decorate(settings, true)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("Limits should not be set for non-backend plugins", func(t *testing.T) {
+		p, err := decorate(map[string]string{"process_memory_limit": "512MiB"}, false)
+		require.NoError(t, err)
+		require.False(t, p.ProcessLimits.Enabled())
+	})
+}
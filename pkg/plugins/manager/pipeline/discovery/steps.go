@@ -31,19 +31,37 @@ func NewDuplicatePluginFilterStep(registry registry.Service) *DuplicatePluginVal
 	}
 }
 
-// Filter will filter out any plugins that are already registered with the registry.
+type replacedPluginsKey struct{}
+
+// WithReplacedPlugins returns a context in which the registered plugins with the given IDs are not filtered out as
+// duplicates, because the plugins being loaded replace them.
+func WithReplacedPlugins(ctx context.Context, pluginIDs ...string) context.Context {
+	replaced := make(map[string]struct{}, len(pluginIDs))
+	for _, id := range pluginIDs {
+		replaced[id] = struct{}{}
+	}
+	return context.WithValue(ctx, replacedPluginsKey{}, replaced)
+}
+
+func isReplaced(ctx context.Context, pluginID string) bool {
+	replaced, _ := ctx.Value(replacedPluginsKey{}).(map[string]struct{})
+	_, ok := replaced[pluginID]
+	return ok
+}
+
+// Filter will filter out any plugins that are already registered with the registry, unless they are replaced.
 func (d *DuplicatePluginValidation) Filter(ctx context.Context, bundles []*plugins.FoundBundle) ([]*plugins.FoundBundle, error) {
 	res := make([]*plugins.FoundBundle, 0, len(bundles))
 	for _, b := range bundles {
 		_, exists := d.registry.Plugin(ctx, b.Primary.JSONData.ID)
-		if exists {
+		if exists && !isReplaced(ctx, b.Primary.JSONData.ID) {
 			d.log.Warn("Skipping loading of plugin as it's a duplicate", "pluginId", b.Primary.JSONData.ID)
 			continue
 		}
 
 		for _, child := range b.Children {
 			_, exists = d.registry.Plugin(ctx, child.JSONData.ID)
-			if exists {
+			if exists && !isReplaced(ctx, child.JSONData.ID) {
 				d.log.Warn("Skipping loading of child plugin as it's a duplicate", "pluginId", child.JSONData.ID)
 				continue
 			}
@@ -2,6 +2,7 @@ package process
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/plugins"
 )
@@ -12,3 +13,39 @@ type Manager interface {
 	// Stop terminates a backend plugin process.
 	Stop(ctx context.Context, p *plugins.Plugin) error
 }
+
+type Supervisor interface {
+	// Status returns the status of a backend plugin process.
+	Status(pluginID string) (Status, bool)
+	// Statuses returns the status of all backend plugin processes.
+	Statuses() map[string]Status
+	// Restart restarts a backend plugin process.
+	Restart(ctx context.Context, pluginID string) error
+}
+
+// State is the state of a backend plugin process.
+type State string
+
+const (
+	// StateRunning means the process is running.
+	StateRunning State = "running"
+	// StateRestarting means the process exited and is waiting to be restarted.
+	StateRestarting State = "restarting"
+	// StateCrashLoop means the process keeps exiting after being restarted. It is still
+	// restarted, with an increasing backoff.
+	StateCrashLoop State = "crashLoop"
+)
+
+// Status is the status of a backend plugin process.
+type Status struct {
+	State State `json:"state"`
+	// Restarts is the number of times the process has been restarted.
+	Restarts int `json:"restarts"`
+	// HealthCheckFailures is the number of health checks the process did not respond to.
+	HealthCheckFailures int        `json:"healthCheckFailures"`
+	LastRestart         *time.Time `json:"lastRestart,omitempty"`
+	NextRestart         *time.Time `json:"nextRestart,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+	// LimitViolations is the number of times the process exceeded each of its resource limits.
+	LimitViolations map[string]int `json:"limitViolations,omitempty"`
+}
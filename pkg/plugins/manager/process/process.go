@@ -2,27 +2,58 @@ package process
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
 	"github.com/grafana/grafana/pkg/plugins"
 )
 
 var (
 	keepPluginAliveTickerDuration = time.Second * 1
+
+	// restartInitialBackoff is the delay before restarting a process that exited, it is
+	// doubled for each consecutive exit up to restartMaxBackoff.
+	restartInitialBackoff = time.Second * 1
+	restartMaxBackoff     = time.Minute * 5
+	// restartBackoffResetDuration is how long a restarted process must keep running to
+	// be considered stable again, which resets the backoff.
+	restartBackoffResetDuration = time.Minute * 5
+	// crashLoopThreshold is the number of consecutive exits after which a process is
+	// reported as crash looping.
+	crashLoopThreshold = 5
+
+	healthCheckInterval = time.Second * 30
+	healthCheckTimeout  = time.Second * 10
+	// healthCheckFailureThreshold is the number of consecutive unanswered health checks
+	// after which a hung process is killed and restarted.
+	healthCheckFailureThreshold = 3
+)
+
+var (
+	ErrPluginProcessNotFound = errors.New("plugin process not found")
+	ErrPluginDecommissioned  = errors.New("plugin is decommissioned")
 )
 
-type Service struct{}
+type Service struct {
+	mu          sync.RWMutex
+	supervisors map[string]*supervisor
+}
 
 func ProvideService() *Service {
 	return &Service{}
 }
 
-func (*Service) Start(ctx context.Context, p *plugins.Plugin) error {
+func (s *Service) Start(ctx context.Context, p *plugins.Plugin) error {
 	if !p.IsManaged() || !p.Backend || p.SignatureError != nil {
 		return nil
 	}
 
-	if err := startPluginAndKeepItAlive(ctx, p); err != nil {
+	if err := s.startPluginAndKeepItAlive(ctx, p); err != nil {
 		return err
 	}
 
@@ -43,7 +74,44 @@ func (*Service) Stop(ctx context.Context, p *plugins.Plugin) error {
 	return nil
 }
 
-func startPluginAndKeepItAlive(ctx context.Context, p *plugins.Plugin) error {
+// Status returns the status of the process of a backend plugin. Only processes kept
+// alive by the service have a status, core plugins do not.
+func (s *Service) Status(pluginID string) (Status, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sv, exists := s.supervisors[pluginID]
+	if !exists {
+		return Status{}, false
+	}
+	return sv.getStatus(), true
+}
+
+// Statuses returns the status of all backend plugin processes kept alive by the service.
+func (s *Service) Statuses() map[string]Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make(map[string]Status, len(s.supervisors))
+	for pluginID, sv := range s.supervisors {
+		statuses[pluginID] = sv.getStatus()
+	}
+	return statuses
+}
+
+// Restart stops and starts the process of a backend plugin, regardless of its state.
+func (s *Service) Restart(ctx context.Context, pluginID string) error {
+	s.mu.RLock()
+	sv, exists := s.supervisors[pluginID]
+	s.mu.RUnlock()
+	if !exists {
+		return ErrPluginProcessNotFound
+	}
+
+	return sv.requestRestart(ctx)
+}
+
+func (s *Service) startPluginAndKeepItAlive(ctx context.Context, p *plugins.Plugin) error {
 	if err := p.Start(ctx); err != nil {
 		return err
 	}
@@ -52,35 +120,273 @@ func startPluginAndKeepItAlive(ctx context.Context, p *plugins.Plugin) error {
 		return nil
 	}
 
-	go func(p *plugins.Plugin) {
-		if err := keepPluginAlive(p); err != nil {
-			p.Logger().Error("Attempt to restart killed plugin process failed", "error", err)
+	sv := newSupervisor(p)
+	s.mu.Lock()
+	if s.supervisors == nil {
+		s.supervisors = make(map[string]*supervisor)
+	}
+	s.supervisors[p.ID] = sv
+	s.mu.Unlock()
+
+	go func() {
+		keepPluginAlive(sv)
+
+		s.mu.Lock()
+		// a new instance of the plugin may have been started in the meantime
+		if s.supervisors[p.ID] == sv {
+			delete(s.supervisors, p.ID)
 		}
-	}(p)
+		s.mu.Unlock()
+	}()
 
 	return nil
 }
 
-// keepPluginAlive will restart the plugin if the process is killed or exits
-func keepPluginAlive(p *plugins.Plugin) error {
+// keepPluginAlive will restart the plugin if the process is killed, exits or stops
+// responding to health checks, until the plugin is decommissioned.
+func keepPluginAlive(sv *supervisor) {
 	ticker := time.NewTicker(keepPluginAliveTickerDuration)
+	defer ticker.Stop()
+	defer close(sv.done)
 
 	for {
-		<-ticker.C
-		if p.IsDecommissioned() {
-			p.Logger().Debug("Plugin decommissioned")
-			return nil
+		select {
+		case req := <-sv.restartRequests:
+			req.result <- sv.restart(req.ctx)
+			continue
+		case <-ticker.C:
 		}
 
-		if !p.Exited() {
-			continue
+		if sv.p.IsDecommissioned() {
+			sv.p.Logger().Debug("Plugin decommissioned")
+			return
 		}
 
-		p.Logger().Debug("Restarting plugin")
-		if err := p.Start(context.Background()); err != nil {
-			p.Logger().Error("Failed to restart plugin", "error", err)
-			continue
+		sv.tick(time.Now())
+	}
+}
+
+type restartRequest struct {
+	ctx    context.Context
+	result chan error
+}
+
+// supervisor holds the state of a backend plugin process kept alive by the service.
+// Apart from the status, its fields are only accessed by the keepPluginAlive goroutine.
+type supervisor struct {
+	p               *plugins.Plugin
+	restartRequests chan restartRequest
+	done            chan struct{}
+
+	mu     sync.RWMutex
+	status Status
+
+	// exits is the number of consecutive exits without the process becoming stable.
+	exits              int
+	startedAt          time.Time
+	nextRestart        time.Time
+	lastHealthCheck    time.Time
+	failedHealthChecks int
+}
+
+func newSupervisor(p *plugins.Plugin) *supervisor {
+	now := time.Now()
+	return &supervisor{
+		p:               p,
+		restartRequests: make(chan restartRequest),
+		done:            make(chan struct{}),
+		status:          Status{State: StateRunning},
+		startedAt:       now,
+		lastHealthCheck: now,
+	}
+}
+
+func (sv *supervisor) getStatus() Status {
+	sv.mu.RLock()
+	status := sv.status
+	sv.mu.RUnlock()
+
+	if violations := sv.p.LimitViolations(); len(violations) > 0 {
+		status.LimitViolations = violations
+	}
+	return status
+}
+
+func (sv *supervisor) updateStatus(fn func(s *Status)) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	fn(&sv.status)
+}
+
+func (sv *supervisor) requestRestart(ctx context.Context) error {
+	req := restartRequest{ctx: ctx, result: make(chan error, 1)}
+	select {
+	case sv.restartRequests <- req:
+	case <-sv.done:
+		return ErrPluginDecommissioned
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (sv *supervisor) tick(now time.Time) {
+	if sv.p.Exited() {
+		sv.handleExit(now)
+		return
+	}
+
+	if sv.exits > 0 && now.Sub(sv.startedAt) >= restartBackoffResetDuration {
+		if sv.exits >= crashLoopThreshold {
+			sv.p.Logger().Info("Plugin process recovered from crash loop")
+		}
+		sv.exits = 0
+		sv.updateStatus(func(s *Status) {
+			s.State = StateRunning
+		})
+	}
+
+	if now.Sub(sv.lastHealthCheck) >= healthCheckInterval {
+		sv.lastHealthCheck = now
+		sv.checkHealth()
+	}
+}
+
+func (sv *supervisor) handleExit(now time.Time) {
+	if sv.nextRestart.IsZero() {
+		sv.exits++
+		backoff := restartBackoff(sv.exits)
+		sv.nextRestart = now.Add(backoff)
+
+		state := StateRestarting
+		if sv.exits >= crashLoopThreshold {
+			state = StateCrashLoop
+			if sv.exits == crashLoopThreshold {
+				sv.p.Logger().Error("Plugin process is crash looping", "exits", sv.exits)
+			}
+		}
+		nextRestart := sv.nextRestart
+		sv.updateStatus(func(s *Status) {
+			s.State = state
+			s.NextRestart = &nextRestart
+		})
+		sv.p.Logger().Debug("Plugin process exited, scheduling restart", "backoff", backoff)
+	}
+
+	if now.Before(sv.nextRestart) {
+		return
+	}
+
+	sv.p.Logger().Debug("Restarting plugin")
+	sv.nextRestart = time.Time{}
+	if err := sv.p.Start(context.Background()); err != nil {
+		// the next tick counts the failed start as another exit
+		sv.p.Logger().Error("Failed to restart plugin", "error", err)
+		sv.updateStatus(func(s *Status) {
+			s.LastError = err.Error()
+		})
+		return
+	}
+	sv.started(now, sv.exits >= crashLoopThreshold)
+	sv.p.Logger().Debug("Plugin restarted")
+}
+
+func (sv *supervisor) restart(ctx context.Context) error {
+	if sv.p.IsDecommissioned() {
+		return ErrPluginDecommissioned
+	}
+
+	sv.p.Logger().Info("Restarting plugin process on request")
+	if err := sv.p.Stop(ctx); err != nil {
+		return err
+	}
+
+	sv.exits = 0
+	sv.nextRestart = time.Time{}
+	if err := sv.p.Start(ctx); err != nil {
+		sv.updateStatus(func(s *Status) {
+			s.LastError = err.Error()
+		})
+		return err
+	}
+	sv.started(time.Now(), false)
+	return nil
+}
+
+// started records a successful restart, a crash looping process keeps its state until
+// it has been running long enough to be considered stable.
+func (sv *supervisor) started(now time.Time, crashLoop bool) {
+	sv.startedAt = now
+	sv.lastHealthCheck = now
+	sv.failedHealthChecks = 0
+
+	state := StateRunning
+	if crashLoop {
+		state = StateCrashLoop
+	}
+	sv.updateStatus(func(s *Status) {
+		s.State = state
+		s.Restarts++
+		s.LastRestart = &now
+		s.NextRestart = nil
+	})
+}
+
+// checkHealth probes the process with a health check. Any answer, including an error
+// returned by the plugin, means the process is responsive. After too many unanswered
+// checks the process is killed, so that it is restarted by the next tick.
+func (sv *supervisor) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	_, err := sv.p.CheckHealth(ctx, &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{PluginID: sv.p.ID},
+	})
+	if !isUnresponsive(ctx, err) {
+		sv.failedHealthChecks = 0
+		return
+	}
+
+	sv.failedHealthChecks++
+	sv.p.Logger().Warn("Plugin process did not respond to health check", "failures", sv.failedHealthChecks, "error", err)
+	sv.updateStatus(func(s *Status) {
+		s.HealthCheckFailures++
+		s.LastError = err.Error()
+	})
+	if sv.failedHealthChecks < healthCheckFailureThreshold {
+		return
+	}
+
+	sv.p.Logger().Error("Killing unresponsive plugin process", "failures", sv.failedHealthChecks)
+	sv.failedHealthChecks = 0
+	if err := sv.p.Stop(context.Background()); err != nil {
+		sv.p.Logger().Error("Failed to kill unresponsive plugin process", "error", err)
+	}
+}
+
+func isUnresponsive(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return grpcstatus.Code(err) == codes.DeadlineExceeded
+}
+
+func restartBackoff(exits int) time.Duration {
+	backoff := restartInitialBackoff
+	for i := 1; i < exits; i++ {
+		backoff *= 2
+		if backoff >= restartMaxBackoff {
+			return restartMaxBackoff
 		}
-		p.Logger().Debug("Plugin restarted")
 	}
+	return backoff
 }
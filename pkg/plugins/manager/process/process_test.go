@@ -2,10 +2,12 @@ package process
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/plugins"
@@ -66,6 +68,9 @@ func TestProcessManager_Start(t *testing.T) {
 
 				if tc.expectedStartCount > 0 {
 					require.True(t, !p.Exited())
+					t.Cleanup(func() {
+						stopAndWait(t, m, p)
+					})
 				} else {
 					require.True(t, p.Exited())
 				}
@@ -96,6 +101,10 @@ func TestProcessManager_Start(t *testing.T) {
 		<-bp.ExitedCheckDoneOrStopped
 		require.False(t, p.Exited())
 		require.Equal(t, 0, bp.StopCount)
+
+		t.Cleanup(func() {
+			stopAndWait(t, m, p)
+		})
 	})
 }
 
@@ -149,9 +158,169 @@ func TestProcessManager_ManagedBackendPluginLifecycle(t *testing.T) {
 		require.Equal(t, 0, bp.StopCount)
 
 		t.Cleanup(func() {
-			require.NoError(t, m.Stop(context.Background(), p))
+			stopAndWait(t, m, p)
+		})
+	})
+}
+
+func TestProcessManager_Supervision(t *testing.T) {
+	setVars := func(t *testing.T) {
+		t.Helper()
+		tickerDuration, initialBackoff, maxBackoff, threshold := keepPluginAliveTickerDuration, restartInitialBackoff, restartMaxBackoff, crashLoopThreshold
+		interval, timeout, failureThreshold := healthCheckInterval, healthCheckTimeout, healthCheckFailureThreshold
+		keepPluginAliveTickerDuration = time.Millisecond
+		restartInitialBackoff = time.Millisecond
+		restartMaxBackoff = 4 * time.Millisecond
+		crashLoopThreshold = 3
+		healthCheckInterval = time.Hour
+		healthCheckTimeout = 10 * time.Millisecond
+		healthCheckFailureThreshold = 2
+		t.Cleanup(func() {
+			keepPluginAliveTickerDuration, restartInitialBackoff, restartMaxBackoff, crashLoopThreshold = tickerDuration, initialBackoff, maxBackoff, threshold
+			healthCheckInterval, healthCheckTimeout, healthCheckFailureThreshold = interval, timeout, failureThreshold
+		})
+	}
+
+	t.Run("Restart backoff grows exponentially up to the maximum", func(t *testing.T) {
+		setVars(t)
+		restartInitialBackoff = time.Second
+		restartMaxBackoff = 5 * time.Second
+
+		require.Equal(t, time.Second, restartBackoff(1))
+		require.Equal(t, 2*time.Second, restartBackoff(2))
+		require.Equal(t, 4*time.Second, restartBackoff(3))
+		require.Equal(t, 5*time.Second, restartBackoff(4))
+		require.Equal(t, 5*time.Second, restartBackoff(10))
+	})
+
+	t.Run("Plugin exiting repeatedly is reported as crash looping", func(t *testing.T) {
+		setVars(t)
+		bp := fakes.NewFakeBackendPlugin(true)
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := &Service{}
+		require.NoError(t, m.Start(context.Background(), p))
+		t.Cleanup(func() {
+			stopAndWait(t, m, p)
 		})
+
+		status, exists := m.Status(p.ID)
+		require.True(t, exists)
+		require.Equal(t, StateRunning, status.State)
+
+		for i := 1; i <= crashLoopThreshold; i++ {
+			bp.Kill()
+			require.Eventually(t, func() bool { return bp.StartCount == i+1 }, time.Second, time.Millisecond)
+		}
+
+		status, exists = m.Status(p.ID)
+		require.True(t, exists)
+		require.Equal(t, StateCrashLoop, status.State)
+		require.Equal(t, crashLoopThreshold, status.Restarts)
+		require.NotNil(t, status.LastRestart)
+		require.Equal(t, map[string]Status{p.ID: status}, m.Statuses())
 	})
+
+	t.Run("Plugin not responding to health checks is restarted", func(t *testing.T) {
+		setVars(t)
+		healthCheckInterval = time.Millisecond
+		bp := fakes.NewFakeBackendPlugin(true)
+		bp.CheckHealthFunc = func(ctx context.Context, _ *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := &Service{}
+		require.NoError(t, m.Start(context.Background(), p))
+		t.Cleanup(func() {
+			stopAndWait(t, m, p)
+		})
+
+		require.Eventually(t, func() bool { return bp.StartCount >= 2 }, time.Second, time.Millisecond)
+		status, exists := m.Status(p.ID)
+		require.True(t, exists)
+		require.GreaterOrEqual(t, status.HealthCheckFailures, healthCheckFailureThreshold)
+		require.GreaterOrEqual(t, status.Restarts, 1)
+	})
+
+	t.Run("Plugin answering health checks with an error is not restarted", func(t *testing.T) {
+		setVars(t)
+		healthCheckInterval = time.Millisecond
+		checked := make(chan struct{}, 1)
+		bp := fakes.NewFakeBackendPlugin(true)
+		bp.CheckHealthFunc = func(_ context.Context, _ *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+			select {
+			case checked <- struct{}{}:
+			default:
+			}
+			return nil, errors.New("datasource not found")
+		}
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := &Service{}
+		require.NoError(t, m.Start(context.Background(), p))
+		t.Cleanup(func() {
+			stopAndWait(t, m, p)
+		})
+
+		for i := 0; i < healthCheckFailureThreshold+1; i++ {
+			<-checked
+		}
+		require.Equal(t, 1, bp.StartCount)
+		status, _ := m.Status(p.ID)
+		require.Zero(t, status.HealthCheckFailures)
+	})
+
+	t.Run("Plugin can be restarted on request", func(t *testing.T) {
+		setVars(t)
+		bp := fakes.NewFakeBackendPlugin(true)
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := &Service{}
+		require.ErrorIs(t, m.Restart(context.Background(), p.ID), ErrPluginProcessNotFound)
+
+		require.NoError(t, m.Start(context.Background(), p))
+		require.NoError(t, m.Restart(context.Background(), p.ID))
+		require.Equal(t, 2, bp.StartCount)
+		require.Equal(t, 1, bp.StopCount)
+
+		status, exists := m.Status(p.ID)
+		require.True(t, exists)
+		require.Equal(t, StateRunning, status.State)
+		require.Equal(t, 1, status.Restarts)
+
+		stopAndWait(t, m, p)
+		_, exists = m.Status(p.ID)
+		require.False(t, exists)
+		require.ErrorIs(t, m.Restart(context.Background(), p.ID), ErrPluginProcessNotFound)
+	})
+}
+
+// stopAndWait stops the plugin and waits for the service to stop keeping it alive.
+func stopAndWait(t *testing.T, m *Service, p *plugins.Plugin) {
+	t.Helper()
+
+	m.mu.RLock()
+	sv, exists := m.supervisors[p.ID]
+	m.mu.RUnlock()
+
+	require.NoError(t, m.Stop(context.Background(), p))
+	if exists {
+		<-sv.done
+	}
+	require.Eventually(t, func() bool {
+		_, exists := m.Status(p.ID)
+		return !exists
+	}, time.Second, time.Millisecond)
 }
 
 func createPlugin(t *testing.T, bp backendplugin.Plugin, cbs ...func(p *plugins.Plugin)) *plugins.Plugin {
@@ -9,3 +9,9 @@ import (
 type Registry interface {
 	List(context.Context) []plugins.PluginSource
 }
+
+// Watcher is a plugin source which reports the changes of its plugin directories.
+type Watcher interface {
+	plugins.PluginSource
+	Watch(ctx context.Context, onChange func(ctx context.Context, changes []PluginDirChange))
+}
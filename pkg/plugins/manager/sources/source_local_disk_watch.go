@@ -0,0 +1,215 @@
+package sources
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// PluginDirOp is the kind of change of a plugin directory.
+type PluginDirOp string
+
+const (
+	PluginDirAdded   PluginDirOp = "added"
+	PluginDirChanged PluginDirOp = "changed"
+	PluginDirRemoved PluginDirOp = "removed"
+)
+
+// PluginDirChange is a change of a plugin directory detected by a WatchedLocalSource.
+type PluginDirChange struct {
+	// Path is the absolute path of the plugin directory, as the plugin finder reports it
+	// for the plugins loaded from it.
+	Path string
+	Op   PluginDirOp
+}
+
+// WatchedLocalSource is a LocalSource which watches its paths for plugin directories being added,
+// changed or removed. The plugin directories are the subdirectories of the paths which contain a
+// plugin.json, or the paths themselves when they are the directory of a plugin.
+//
+// The paths are scanned periodically rather than watched with file system notifications, which
+// are not available on every file system plugins are stored on, e.g. network file systems.
+// A change is only reported once a directory stayed the same for a whole interval, so that
+// plugins being copied are not reported before they are complete.
+type WatchedLocalSource struct {
+	*LocalSource
+	interval time.Duration
+	log      log.Logger
+
+	// reported are the fingerprints of the plugin directories as last reported, and scanned
+	// the ones found by the last scan.
+	reported map[string]uint64
+	scanned  map[string]uint64
+}
+
+func NewWatchedLocalSource(class plugins.Class, paths []string, interval time.Duration) *WatchedLocalSource {
+	return &WatchedLocalSource{
+		LocalSource: NewLocalSource(class, paths),
+		interval:    interval,
+		log:         log.New("plugin.sources.watch"),
+	}
+}
+
+// Watch scans the paths of the source every interval and calls onChange with the changes of the
+// plugin directories since the previous call, until ctx is done. The plugin directories found by
+// the first scan are assumed to be loaded already.
+func (s *WatchedLocalSource) Watch(ctx context.Context, onChange func(ctx context.Context, changes []PluginDirChange)) {
+	s.reported = s.scan()
+	s.scanned = s.reported
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changes := s.changes(s.scan()); len(changes) > 0 {
+				onChange(ctx, changes)
+			}
+		}
+	}
+}
+
+// changes returns the changes of the plugin directories which are the same in the current
+// and the previous scan.
+func (s *WatchedLocalSource) changes(scanned map[string]uint64) []PluginDirChange {
+	var changes []PluginDirChange
+	for dir, fingerprint := range scanned {
+		reported, exists := s.reported[dir]
+		if exists && reported == fingerprint {
+			continue
+		}
+		if previous, stable := s.scanned[dir]; !stable || previous != fingerprint {
+			continue
+		}
+
+		op := PluginDirAdded
+		if exists {
+			op = PluginDirChanged
+		}
+		changes = append(changes, PluginDirChange{Path: dir, Op: op})
+		s.reported[dir] = fingerprint
+	}
+	for dir := range s.reported {
+		_, exists := scanned[dir]
+		_, existed := s.scanned[dir]
+		if !exists && !existed {
+			changes = append(changes, PluginDirChange{Path: dir, Op: PluginDirRemoved})
+			delete(s.reported, dir)
+		}
+	}
+	s.scanned = scanned
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// scan returns the fingerprints of the plugin directories in the paths of the source.
+func (s *WatchedLocalSource) scan() map[string]uint64 {
+	dirs := map[string]uint64{}
+	for _, path := range s.paths {
+		path = walkedPath(path)
+		// a path can be the directory of a single plugin rather than contain plugin directories
+		if isPluginDir(path) {
+			if fingerprint, ok := s.fingerprint(path); ok {
+				dirs[path] = fingerprint
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				s.log.Warn("Could not scan plugin directory", "path", path, "error", err)
+			}
+			continue
+		}
+		for _, e := range entries {
+			dir := walkedPath(filepath.Join(path, e.Name()))
+			if fingerprint, ok := s.fingerprint(dir); ok {
+				dirs[dir] = fingerprint
+			}
+		}
+	}
+	return dirs
+}
+
+// fingerprint returns a hash of the names, sizes and modification times of the files of a
+// plugin directory, and false if dir is not a directory containing a plugin.json.
+func (s *WatchedLocalSource) fingerprint(dir string) (uint64, bool) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return 0, false
+	}
+
+	h := fnv.New64a()
+	hasPluginJSON := false
+	buf := make([]byte, 8)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// files can be removed while walking
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if d.Name() == "plugin.json" {
+			hasPluginJSON = true
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		_, _ = h.Write(append([]byte(rel), 0))
+		binary.LittleEndian.PutUint64(buf, uint64(info.Size()))
+		_, _ = h.Write(buf)
+		binary.LittleEndian.PutUint64(buf, uint64(info.ModTime().UnixNano()))
+		_, _ = h.Write(buf)
+		return nil
+	})
+	if err != nil {
+		s.log.Warn("Could not scan plugin directory", "path", dir, "error", err)
+		return 0, false
+	}
+	return h.Sum64(), hasPluginJSON
+}
+
+func isPluginDir(path string) bool {
+	for _, pluginJSON := range []string{"plugin.json", filepath.Join("dist", "plugin.json")} {
+		if _, err := os.Stat(filepath.Join(path, pluginJSON)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// walkedPath returns the absolute path of path as the plugin finder walks it: path is resolved
+// when it is a symbolic link, but not the symbolic links in the directories above it. The paths
+// of the plugin directories then match the base directories of the plugins loaded from them.
+func walkedPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return resolved
+		}
+	}
+	return path
+}
@@ -0,0 +1,161 @@
+package sources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/loader/finder"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestWatchedLocalSource_changes(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, filepath.Join(dir, "test-panel"), "1.0.0")
+	writePlugin(t, filepath.Join(dir, "test-app"), "1.0.0")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "not-a-plugin"), 0o750))
+
+	s := NewWatchedLocalSource(plugins.ClassExternal, []string{dir}, time.Second)
+	s.reported = s.scan()
+	s.scanned = s.reported
+	require.Len(t, s.reported, 2)
+
+	t.Run("Nothing changed", func(t *testing.T) {
+		require.Empty(t, s.changes(s.scan()))
+	})
+
+	t.Run("Changes are reported once plugin directories are stable", func(t *testing.T) {
+		writePlugin(t, filepath.Join(dir, "test-datasource"), "1.0.0")
+		writePlugin(t, filepath.Join(dir, "test-panel"), "2.0.0-beta")
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "test-app")))
+
+		require.Empty(t, s.changes(s.scan()))
+		require.Equal(t, []PluginDirChange{
+			{Path: filepath.Join(dir, "test-app"), Op: PluginDirRemoved},
+			{Path: filepath.Join(dir, "test-datasource"), Op: PluginDirAdded},
+			{Path: filepath.Join(dir, "test-panel"), Op: PluginDirChanged},
+		}, s.changes(s.scan()))
+		require.Empty(t, s.changes(s.scan()))
+	})
+
+	t.Run("Plugin directory changing between scans is not reported", func(t *testing.T) {
+		writePlugin(t, filepath.Join(dir, "test-panel"), "3.0.0-beta.1")
+		require.Empty(t, s.changes(s.scan()))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test-panel", "module.js"), []byte("//"), 0o600))
+		require.Empty(t, s.changes(s.scan()))
+
+		require.Equal(t, []PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: PluginDirChanged},
+		}, s.changes(s.scan()))
+	})
+
+	t.Run("Plugin directory replaced between scans is reported as changed", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "test-datasource")))
+		require.Empty(t, s.changes(s.scan()))
+		writePlugin(t, filepath.Join(dir, "test-datasource"), "2.0.0-beta")
+		require.Empty(t, s.changes(s.scan()))
+
+		require.Equal(t, []PluginDirChange{
+			{Path: filepath.Join(dir, "test-datasource"), Op: PluginDirChanged},
+		}, s.changes(s.scan()))
+	})
+
+	t.Run("Path of a single plugin", func(t *testing.T) {
+		s := NewWatchedLocalSource(plugins.ClassExternal, []string{filepath.Join(dir, "test-panel")}, time.Second)
+		scanned := s.scan()
+		require.Len(t, scanned, 1)
+		require.Contains(t, scanned, filepath.Join(dir, "test-panel"))
+	})
+}
+
+func TestWatchedLocalSource_Watch(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, filepath.Join(dir, "test-panel"), "1.0.0")
+
+	s := NewWatchedLocalSource(plugins.ClassExternal, []string{dir}, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	changesCh := make(chan []PluginDirChange)
+	done := make(chan struct{})
+	go func() {
+		s.Watch(ctx, func(_ context.Context, changes []PluginDirChange) {
+			changesCh <- changes
+		})
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// wait for the first scan, after which changes are detected
+	time.Sleep(50 * time.Millisecond)
+	writePlugin(t, filepath.Join(dir, "test-app"), "1.0.0")
+
+	select {
+	case changes := <-changesCh:
+		require.Equal(t, []PluginDirChange{{Path: filepath.Join(dir, "test-app"), Op: PluginDirAdded}}, changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Plugin directory was not reported as added")
+	}
+}
+
+func TestWatchedLocalSource_symlinks(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, filepath.Join(dir, "real", "plugins", "test-panel"), "1.0.0")
+	writePlugin(t, filepath.Join(dir, "external", "test-app"), "1.0.0")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "external", "test-app"), filepath.Join(dir, "real", "plugins", "test-app")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "real", "plugins"), filepath.Join(dir, "plugins")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "link")))
+
+	for name, tc := range map[string]struct {
+		path     string
+		expected []string
+	}{
+		"Symlinked plugins directory": {
+			path: filepath.Join(dir, "plugins"),
+			expected: []string{
+				filepath.Join(dir, "external", "test-app"),
+				filepath.Join(dir, "real", "plugins", "test-panel"),
+			},
+		},
+		"Plugins directory in a symlinked directory": {
+			path: filepath.Join(dir, "link", "plugins"),
+			expected: []string{
+				filepath.Join(dir, "external", "test-app"),
+				filepath.Join(dir, "link", "plugins", "test-panel"),
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			s := NewWatchedLocalSource(plugins.ClassExternal, []string{tc.path}, time.Second)
+			var watched []string
+			for path := range s.scan() {
+				watched = append(watched, path)
+			}
+			require.ElementsMatch(t, tc.expected, watched)
+
+			// the paths are the ones of the plugins loaded from them, which the store unloads when they change
+			found, err := finder.NewLocalFinder(false, featuremgmt.WithFeatures()).Find(context.Background(), s)
+			require.NoError(t, err)
+			var bases []string
+			for _, b := range found {
+				bases = append(bases, b.Primary.FS.Base())
+			}
+			require.ElementsMatch(t, tc.expected, bases)
+		})
+	}
+}
+
+func writePlugin(t *testing.T, dir, version string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	pluginJSON := `{"id": "` + filepath.Base(dir) + `", "type": "panel", "info": {"version": "` + version + `"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(pluginJSON), 0o600))
+}
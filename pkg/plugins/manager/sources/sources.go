@@ -25,10 +25,16 @@ func ProvideService(gCfg *setting.Cfg, cfg *config.Cfg) *Service {
 }
 
 func (s *Service) List(_ context.Context) []plugins.PluginSource {
+	externalPaths := append([]string{s.cfg.PluginsPath}, pluginFSPaths(s.cfg.PluginSettings)...)
+	var external plugins.PluginSource = NewLocalSource(plugins.ClassExternal, externalPaths)
+	if s.cfg.HotReload {
+		external = NewWatchedLocalSource(plugins.ClassExternal, externalPaths, s.cfg.HotReloadInterval)
+	}
+
 	return []plugins.PluginSource{
 		NewLocalSource(plugins.ClassCore, corePluginPaths(s.gCfg.StaticRootPath)),
 		NewLocalSource(plugins.ClassBundled, []string{s.gCfg.BundledPluginsPath}),
-		NewLocalSource(plugins.ClassExternal, append([]string{s.cfg.PluginsPath}, pluginFSPaths(s.cfg.PluginSettings)...)),
+		external,
 	}
 }
 
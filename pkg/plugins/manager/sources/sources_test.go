@@ -3,6 +3,7 @@ package sources
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -55,4 +56,23 @@ func TestSources_List(t *testing.T) {
 		require.False(t, exists)
 		require.Equal(t, plugins.Signature{}, sig)
 	})
+
+	t.Run("External plugin source is watched when hot reload is enabled", func(t *testing.T) {
+		s := ProvideService(&setting.Cfg{}, &config.Cfg{
+			PluginsPath:       "path1",
+			HotReload:         true,
+			HotReloadInterval: time.Second,
+		})
+		srcs := s.List(context.Background())
+		require.Len(t, srcs, 3)
+
+		ctx := context.Background()
+		require.Equal(t, plugins.ClassExternal, srcs[2].PluginClass(ctx))
+		require.Equal(t, []string{"path1"}, srcs[2].PluginURIs(ctx))
+		require.Implements(t, (*Watcher)(nil), srcs[2])
+		for _, src := range srcs[:2] {
+			_, ok := src.(Watcher)
+			require.False(t, ok)
+		}
+	})
 }
@@ -65,6 +65,7 @@ type Plugin struct {
 	log            log.Logger
 
 	SkipHostEnvVars bool
+	ProcessLimits   backendplugin.ProcessLimits
 
 	mu sync.Mutex
 }
@@ -290,6 +291,15 @@ func (p *Plugin) Exited() bool {
 	return false
 }
 
+// LimitViolations returns the number of times the backend plugin process exceeded each
+// of its limits, it is empty when the process runs without limits.
+func (p *Plugin) LimitViolations() map[string]int {
+	if lp, ok := p.client.(backendplugin.LimitedPlugin); ok {
+		return lp.LimitViolations()
+	}
+	return nil
+}
+
 func (p *Plugin) Target() backendplugin.Target {
 	if !p.Backend {
 		return backendplugin.TargetNone
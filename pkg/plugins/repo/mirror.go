@@ -0,0 +1,336 @@
+package repo
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// MirrorIndexFile is the name of the index file at the root of a plugin repository mirror.
+const MirrorIndexFile = "index.json"
+
+// MirrorIndex is the index of a plugin repository mirror. The versions of a plugin use the
+// format of the grafana.com API and are sorted so the newest version is first.
+type MirrorIndex struct {
+	Plugins []MirrorPlugin `json:"plugins"`
+}
+
+type MirrorPlugin struct {
+	ID       string    `json:"id"`
+	Versions []Version `json:"versions"`
+}
+
+// mirror is a plugin repository mirror, either a local directory or an HTTP server,
+// used instead of the grafana.com API.
+type mirror struct {
+	// base is the directory of a local mirror or the URL of an HTTP mirror.
+	base  string
+	local bool
+}
+
+func newMirror(mirrorURL string) *mirror {
+	u, err := url.Parse(mirrorURL)
+	if err != nil {
+		// not a URL, e.g. a Windows path
+		return &mirror{base: mirrorURL, local: true}
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return &mirror{base: mirrorURL}
+	case "file":
+		return &mirror{base: filepath.FromSlash(u.Path), local: true}
+	default:
+		return &mirror{base: mirrorURL, local: true}
+	}
+}
+
+func (m *mirror) index(client *Client, compatOpts CompatOpts) (MirrorIndex, error) {
+	var body []byte
+	if m.local {
+		var err error
+		// nolint:gosec
+		if body, err = os.ReadFile(filepath.Join(m.base, MirrorIndexFile)); err != nil {
+			return MirrorIndex{}, fmt.Errorf("failed to read plugin repository mirror index: %w", err)
+		}
+	} else {
+		u, err := url.Parse(m.base)
+		if err != nil {
+			return MirrorIndex{}, err
+		}
+		u.Path = path.Join(u.Path, MirrorIndexFile)
+		if body, err = client.SendReq(u, compatOpts); err != nil {
+			return MirrorIndex{}, err
+		}
+	}
+
+	var index MirrorIndex
+	if err := json.Unmarshal(body, &index); err != nil {
+		return MirrorIndex{}, fmt.Errorf("failed to parse plugin repository mirror index: %w", err)
+	}
+	return index, nil
+}
+
+// versions returns the versions of the plugin listed in the index of the mirror.
+func (m *mirror) versions(client *Client, pluginID string, compatOpts CompatOpts) ([]Version, error) {
+	index, err := m.index(client, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range index.Plugins {
+		if p.ID != pluginID || len(p.Versions) == 0 {
+			continue
+		}
+
+		// the grafana.com API only returns the versions compatible with the Grafana version
+		grafanaVersion, _ := compatOpts.GrafanaVersion()
+		versions := make([]Version, 0, len(p.Versions))
+		for _, v := range p.Versions {
+			if grafanaCompatible(v.GrafanaDependency, grafanaVersion) {
+				versions = append(versions, v)
+			}
+		}
+		if len(versions) == 0 {
+			return nil, newErrResponse4xx(http.StatusNotFound).
+				withMessage("No version of the plugin is compatible with this Grafana version").
+				withCompatibilityInfo(compatOpts)
+		}
+		return versions, nil
+	}
+	return nil, newErrResponse4xx(http.StatusNotFound).withMessage("Plugin not found")
+}
+
+// grafanaCompatible returns whether the Grafana version satisfies the grafanaDependency of a
+// plugin, which is assumed when either of them is unknown.
+func grafanaCompatible(grafanaDependency, grafanaVersion string) bool {
+	if grafanaDependency == "" || grafanaVersion == "" {
+		return true
+	}
+	c, err := semver.NewConstraint(grafanaDependency)
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(grafanaVersion)
+	if err != nil {
+		return true
+	}
+	// pre-releases of Grafana would otherwise not satisfy any dependency without a pre-release
+	if v.Prerelease() != "" {
+		if stable, err := v.SetPrerelease(""); err == nil {
+			v = &stable
+		}
+	}
+	return c.Check(v)
+}
+
+// downloadURL returns the location of the archive of the plugin version for the system,
+// which is a path for a local mirror.
+func (m *mirror) downloadURL(v VersionData, compatOpts SystemCompatOpts) (string, error) {
+	pkg, exists := systemPackage(v.Arch, compatOpts)
+	if !exists || pkg.DownloadURL == "" {
+		return "", fmt.Errorf("plugin repository mirror has no archive of v%s for %s", v.Version, compatOpts.OSAndArch())
+	}
+
+	if u, err := url.Parse(pkg.DownloadURL); err == nil && u.IsAbs() {
+		return pkg.DownloadURL, nil
+	}
+	if m.local {
+		return filepath.Join(m.base, filepath.FromSlash(pkg.DownloadURL)), nil
+	}
+	return url.JoinPath(m.base, pkg.DownloadURL)
+}
+
+// executableSuffix matches the OS and architecture suffix of the executable of a backend plugin,
+// e.g. gpx_example_linux_amd64 or gpx_example_windows_amd64.exe.
+var executableSuffix = regexp.MustCompile(`_([a-z]+)_([a-z0-9]+)(\.exe)?$`)
+
+// mirrorArchive is the plugin in an archive added to a mirror.
+type mirrorArchive struct {
+	pluginID string
+	version  string
+	// platforms are the OS and architecture combinations the archive supports, or any.
+	platforms         []string
+	grafanaDependency string
+}
+
+// BuildMirror writes the index of a plugin repository mirror in dir, listing the plugin zip
+// archives found in dir and its subdirectories. Archives of backend plugins are only installed
+// on the systems for which they contain an executable.
+func BuildMirror(dir string) (MirrorIndex, error) {
+	plugins := map[string]map[string]*Version{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".zip") {
+			return nil
+		}
+
+		a, err := readMirrorArchive(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		checksum, err := fileChecksum(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		if plugins[a.pluginID] == nil {
+			plugins[a.pluginID] = map[string]*Version{}
+		}
+		v, exists := plugins[a.pluginID][a.version]
+		if !exists {
+			v = &Version{Version: a.version, Arch: map[string]ArchMeta{}, GrafanaDependency: a.grafanaDependency}
+			plugins[a.pluginID][a.version] = v
+		}
+		for _, platform := range a.platforms {
+			if existing, exists := v.Arch[platform]; exists {
+				return fmt.Errorf("archives %s and %s both contain %s v%s for %s", existing.DownloadURL, filepath.ToSlash(rel),
+					a.pluginID, a.version, platform)
+			}
+			v.Arch[platform] = ArchMeta{SHA256: checksum, DownloadURL: filepath.ToSlash(rel)}
+		}
+		return nil
+	})
+	if err != nil {
+		return MirrorIndex{}, err
+	}
+
+	index := MirrorIndex{Plugins: make([]MirrorPlugin, 0, len(plugins))}
+	for pluginID, versions := range plugins {
+		p := MirrorPlugin{ID: pluginID, Versions: make([]Version, 0, len(versions))}
+		for _, v := range versions {
+			p.Versions = append(p.Versions, *v)
+		}
+		sort.Slice(p.Versions, func(i, j int) bool {
+			return versionGreater(p.Versions[i].Version, p.Versions[j].Version)
+		})
+		index.Plugins = append(index.Plugins, p)
+	}
+	sort.Slice(index.Plugins, func(i, j int) bool {
+		return index.Plugins[i].ID < index.Plugins[j].ID
+	})
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return MirrorIndex{}, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, MirrorIndexFile), data, 0o644); err != nil {
+		return MirrorIndex{}, fmt.Errorf("failed to write plugin repository mirror index: %w", err)
+	}
+	return index, nil
+}
+
+func readMirrorArchive(archivePath string) (mirrorArchive, error) {
+	rc, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return mirrorArchive{}, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	// the plugin.json closest to the root belongs to the plugin, others to nested plugins
+	var pluginJSON *zip.File
+	for _, f := range rc.File {
+		if path.Base(f.Name) != "plugin.json" {
+			continue
+		}
+		if pluginJSON == nil || strings.Count(f.Name, "/") < strings.Count(pluginJSON.Name, "/") {
+			pluginJSON = f
+		}
+	}
+	if pluginJSON == nil {
+		return mirrorArchive{}, errors.New("plugin.json not found")
+	}
+
+	r, err := pluginJSON.Open()
+	if err != nil {
+		return mirrorArchive{}, err
+	}
+	defer func() { _ = r.Close() }()
+
+	var data struct {
+		ID   string `json:"id"`
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Dependencies struct {
+			GrafanaDependency string `json:"grafanaDependency"`
+		} `json:"dependencies"`
+		Backend    bool   `json:"backend"`
+		Executable string `json:"executable"`
+	}
+	if err = json.NewDecoder(r).Decode(&data); err != nil {
+		return mirrorArchive{}, fmt.Errorf("failed to parse plugin.json: %w", err)
+	}
+	if data.ID == "" || data.Info.Version == "" {
+		return mirrorArchive{}, errors.New("plugin.json has no id or version")
+	}
+
+	a := mirrorArchive{
+		pluginID:          data.ID,
+		version:           normalizeVersion(data.Info.Version),
+		grafanaDependency: data.Dependencies.GrafanaDependency,
+	}
+	if !data.Backend || data.Executable == "" {
+		a.platforms = []string{"any"}
+		return a, nil
+	}
+
+	pluginDir := path.Dir(pluginJSON.Name)
+	for _, f := range rc.File {
+		if path.Dir(f.Name) != pluginDir || !strings.HasPrefix(path.Base(f.Name), data.Executable+"_") {
+			continue
+		}
+		if m := executableSuffix.FindStringSubmatch(path.Base(f.Name)); m != nil {
+			a.platforms = append(a.platforms, fmt.Sprintf("%s-%s", m[1], m[2]))
+		}
+	}
+	if len(a.platforms) == 0 {
+		return mirrorArchive{}, fmt.Errorf("no executable of backend plugin %s found", data.ID)
+	}
+	return a, nil
+}
+
+func fileChecksum(p string) (string, error) {
+	// nolint:gosec
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// versionGreater compares versions semantically, falling back to comparing them as strings.
+func versionGreater(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return a > b
+	}
+	return va.GreaterThan(vb)
+}
@@ -0,0 +1,210 @@
+package repo
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+func TestBuildMirror(t *testing.T) {
+	dir := t.TempDir()
+	writePluginArchive(t, filepath.Join(dir, "test-panel-1.0.0.zip"), "test-panel/",
+		`{"id": "test-panel", "info": {"version": "1.0.0"}}`)
+	writePluginArchive(t, filepath.Join(dir, "test-panel-1.10.0.zip"), "test-panel/",
+		`{"id": "test-panel", "info": {"version": "1.10.0"}, "dependencies": {"grafanaDependency": ">=10.0.0"}}`)
+	writePluginArchive(t, filepath.Join(dir, "backend", "test-datasource-2.0.0.linux_amd64.zip"), "test-datasource/",
+		`{"id": "test-datasource", "info": {"version": "2.0.0"}, "backend": true, "executable": "gpx_test"}`,
+		"gpx_test_linux_amd64")
+	writePluginArchive(t, filepath.Join(dir, "backend", "test-datasource-2.0.0.darwin.zip"), "test-datasource/",
+		`{"id": "test-datasource", "info": {"version": "2.0.0"}, "backend": true, "executable": "gpx_test"}`,
+		"gpx_test_darwin_amd64", "gpx_test_darwin_arm64")
+
+	index, err := BuildMirror(dir)
+	require.NoError(t, err)
+
+	expected := MirrorIndex{Plugins: []MirrorPlugin{
+		{
+			ID: "test-datasource",
+			Versions: []Version{{
+				Version: "2.0.0",
+				Arch: map[string]ArchMeta{
+					"linux-amd64":  {SHA256: checksumOf(t, filepath.Join(dir, "backend", "test-datasource-2.0.0.linux_amd64.zip")), DownloadURL: "backend/test-datasource-2.0.0.linux_amd64.zip"},
+					"darwin-amd64": {SHA256: checksumOf(t, filepath.Join(dir, "backend", "test-datasource-2.0.0.darwin.zip")), DownloadURL: "backend/test-datasource-2.0.0.darwin.zip"},
+					"darwin-arm64": {SHA256: checksumOf(t, filepath.Join(dir, "backend", "test-datasource-2.0.0.darwin.zip")), DownloadURL: "backend/test-datasource-2.0.0.darwin.zip"},
+				},
+			}},
+		},
+		{
+			ID: "test-panel",
+			Versions: []Version{
+				{
+					Version:           "1.10.0",
+					Arch:              map[string]ArchMeta{"any": {SHA256: checksumOf(t, filepath.Join(dir, "test-panel-1.10.0.zip")), DownloadURL: "test-panel-1.10.0.zip"}},
+					GrafanaDependency: ">=10.0.0",
+				},
+				{
+					Version: "1.0.0",
+					Arch:    map[string]ArchMeta{"any": {SHA256: checksumOf(t, filepath.Join(dir, "test-panel-1.0.0.zip")), DownloadURL: "test-panel-1.0.0.zip"}},
+				},
+			},
+		},
+	}}
+	require.Equal(t, expected, index)
+
+	data, err := os.ReadFile(filepath.Join(dir, MirrorIndexFile))
+	require.NoError(t, err)
+	var written MirrorIndex
+	require.NoError(t, json.Unmarshal(data, &written))
+	require.Equal(t, expected, written)
+
+	t.Run("Archives of the same plugin version and system conflict", func(t *testing.T) {
+		writePluginArchive(t, filepath.Join(dir, "copy.zip"), "test-panel/",
+			`{"id": "test-panel", "info": {"version": "1.0.0"}}`)
+
+		_, err := BuildMirror(dir)
+		require.ErrorContains(t, err, "both contain test-panel v1.0.0 for any")
+	})
+
+	t.Run("Archive without plugin.json fails", func(t *testing.T) {
+		dir := t.TempDir()
+		writePluginArchive(t, filepath.Join(dir, "invalid.zip"), "", "")
+
+		_, err := BuildMirror(dir)
+		require.ErrorContains(t, err, "plugin.json not found")
+	})
+}
+
+func TestMirror(t *testing.T) {
+	dir := t.TempDir()
+	writePluginArchive(t, filepath.Join(dir, "test-datasource-1.0.0.zip"), "test-datasource/",
+		`{"id": "test-datasource", "info": {"version": "1.0.0"}, "backend": true, "executable": "gpx_test"}`,
+		"gpx_test_linux_amd64", "gpx_test_darwin_arm64")
+	writePluginArchive(t, filepath.Join(dir, "test-datasource-2.0.0.zip"), "test-datasource/",
+		`{"id": "test-datasource", "info": {"version": "2.0.0"}, "backend": true, "executable": "gpx_test"}`,
+		"gpx_test_linux_amd64")
+	writePluginArchive(t, filepath.Join(dir, "test-datasource-3.0.0.zip"), "test-datasource/",
+		`{"id": "test-datasource", "info": {"version": "3.0.0"}, "backend": true, "executable": "gpx_test", "dependencies": {"grafanaDependency": ">=11.0.0"}}`,
+		"gpx_test_linux_amd64", "gpx_test_darwin_arm64")
+	_, err := BuildMirror(dir)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(srv.Close)
+
+	for name, mirrorURL := range map[string]string{
+		"Local directory": dir,
+		"File URL":        "file://" + filepath.ToSlash(dir),
+		"HTTP server":     srv.URL,
+	} {
+		mirrorURL := mirrorURL
+		t.Run(name, func(t *testing.T) {
+			m := NewManager(ManagerCfg{
+				BaseURL:   "http://localhost:1/api/plugins",
+				MirrorURL: mirrorURL,
+				Logger:    log.NewTestPrettyLogger(),
+			})
+
+			t.Run("Installs latest version compatible with Grafana and system", func(t *testing.T) {
+				archive, err := m.GetPluginArchive(context.Background(), "test-datasource", "", NewCompatOpts("10.2.0-pre", "linux", "amd64"))
+				require.NoError(t, err)
+				require.Equal(t, "2.0.0", archiveVersion(t, archive))
+
+				archive, err = m.GetPluginArchive(context.Background(), "test-datasource", "", NewCompatOpts("10.2.0", "darwin", "arm64"))
+				require.NoError(t, err)
+				require.Equal(t, "1.0.0", archiveVersion(t, archive))
+
+				archive, err = m.GetPluginArchive(context.Background(), "test-datasource", "", NewCompatOpts("11.0.0", "darwin", "arm64"))
+				require.NoError(t, err)
+				require.Equal(t, "3.0.0", archiveVersion(t, archive))
+			})
+
+			t.Run("Installs requested version", func(t *testing.T) {
+				info, err := m.GetPluginArchiveInfo(context.Background(), "test-datasource", "1.0.0", NewCompatOpts("10.2.0", "linux", "amd64"))
+				require.NoError(t, err)
+				require.Equal(t, "1.0.0", info.Version)
+				require.Equal(t, checksumOf(t, filepath.Join(dir, "test-datasource-1.0.0.zip")), info.Checksum)
+			})
+
+			t.Run("Requested version not supported on system", func(t *testing.T) {
+				_, err := m.GetPluginArchive(context.Background(), "test-datasource", "2.0.0", NewCompatOpts("10.2.0", "darwin", "arm64"))
+				require.ErrorAs(t, err, &ErrVersionUnsupported{})
+			})
+
+			t.Run("Unknown plugin", func(t *testing.T) {
+				_, err := m.GetPluginArchive(context.Background(), "unknown", "", NewCompatOpts("10.2.0", "linux", "amd64"))
+				var errResponse ErrResponse4xx
+				require.ErrorAs(t, err, &errResponse)
+				require.Equal(t, http.StatusNotFound, errResponse.StatusCode())
+			})
+		})
+	}
+
+	t.Run("Archive not matching checksum of index fails", func(t *testing.T) {
+		writePluginArchive(t, filepath.Join(dir, "test-datasource-2.0.0.zip"), "test-datasource/",
+			`{"id": "test-datasource", "info": {"version": "2.0.0"}, "backend": true, "executable": "gpx_test"}`,
+			"gpx_test_linux_amd64", "gpx_test_linux_arm64")
+
+		m := NewManager(ManagerCfg{MirrorURL: dir, Logger: log.NewTestPrettyLogger()})
+		_, err := m.GetPluginArchive(context.Background(), "test-datasource", "2.0.0", NewCompatOpts("10.2.0", "linux", "amd64"))
+		require.ErrorAs(t, err, &ErrChecksumMismatch{})
+	})
+}
+
+func writePluginArchive(t *testing.T, archivePath, pluginDir, pluginJSON string, executables ...string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivePath), 0o750))
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	w := zip.NewWriter(f)
+	if pluginJSON != "" {
+		pJSON, err := w.Create(pluginDir + "plugin.json")
+		require.NoError(t, err)
+		_, err = pJSON.Write([]byte(pluginJSON))
+		require.NoError(t, err)
+	} else {
+		_, err := w.Create(pluginDir + "README.md")
+		require.NoError(t, err)
+	}
+	for _, e := range executables {
+		_, err := w.Create(pluginDir + e)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+}
+
+func checksumOf(t *testing.T, p string) string {
+	t.Helper()
+
+	checksum, err := fileChecksum(p)
+	require.NoError(t, err)
+	return checksum
+}
+
+func archiveVersion(t *testing.T, archive *PluginArchive) string {
+	t.Helper()
+	defer func() { require.NoError(t, archive.File.Close()) }()
+
+	pJSON, err := archive.File.Open("test-datasource/plugin.json")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, pJSON.Close()) }()
+
+	var data struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	require.NoError(t, json.NewDecoder(pJSON).Decode(&data))
+	return data.Info.Version
+}
@@ -18,11 +18,14 @@ type PluginVersions struct {
 }
 
 type Version struct {
-	Version string              `json:"version"`
-	Arch    map[string]ArchMeta `json:"packages"`
-	URL     string              `json:"url"`
+	Version           string              `json:"version"`
+	Arch              map[string]ArchMeta `json:"packages"`
+	URL               string              `json:"url"`
+	GrafanaDependency string              `json:"grafanaDependency,omitempty"`
 }
 
 type ArchMeta struct {
 	SHA256 string `json:"sha256"`
+	// DownloadURL is the location of the package, relative to the index of a mirror.
+	DownloadURL string `json:"downloadUrl,omitempty"`
 }
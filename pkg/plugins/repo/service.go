@@ -17,6 +17,8 @@ import (
 type Manager struct {
 	client  *Client
 	baseURL string
+	// mirror is used instead of the repository at baseURL when set.
+	mirror *mirror
 
 	log log.PrettyLogger
 }
@@ -30,6 +32,7 @@ func ProvideService(cfg *config.Cfg) (*Manager, error) {
 	return NewManager(ManagerCfg{
 		SkipTLSVerify: false,
 		BaseURL:       baseURL,
+		MirrorURL:     cfg.RepositoryMirror,
 		Logger:        log.NewPrettyLogger("plugin.repository"),
 	}), nil
 }
@@ -37,15 +40,22 @@ func ProvideService(cfg *config.Cfg) (*Manager, error) {
 type ManagerCfg struct {
 	SkipTLSVerify bool
 	BaseURL       string
-	Logger        log.PrettyLogger
+	// MirrorURL is the directory or the URL of a plugin repository mirror which is used instead
+	// of the repository at BaseURL, see BuildMirror.
+	MirrorURL string
+	Logger    log.PrettyLogger
 }
 
 func NewManager(cfg ManagerCfg) *Manager {
-	return &Manager{
+	m := &Manager{
 		baseURL: cfg.BaseURL,
 		client:  NewClient(cfg.SkipTLSVerify, cfg.Logger),
 		log:     cfg.Logger,
 	}
+	if cfg.MirrorURL != "" {
+		m.mirror = newMirror(cfg.MirrorURL)
+	}
+	return m
 }
 
 // GetPluginArchive fetches the requested plugin archive
@@ -70,16 +80,30 @@ func (m *Manager) GetPluginArchiveInfo(_ context.Context, pluginID, version stri
 		return nil, err
 	}
 
+	u := m.downloadURL(pluginID, v.Version)
+	if m.mirror != nil {
+		sysCompatOpts, _ := compatOpts.System()
+		if u, err = m.mirror.downloadURL(v, sysCompatOpts); err != nil {
+			return nil, err
+		}
+	}
+
 	return &PluginArchiveInfo{
 		Version:  v.Version,
 		Checksum: v.Checksum,
-		URL:      m.downloadURL(pluginID, v.Version),
+		URL:      u,
 	}, nil
 }
 
 // PluginVersion will return plugin version based on the requested information
 func (m *Manager) PluginVersion(pluginID, version string, compatOpts CompatOpts) (VersionData, error) {
-	versions, err := m.grafanaCompatiblePluginVersions(pluginID, compatOpts)
+	var versions []Version
+	var err error
+	if m.mirror != nil {
+		versions, err = m.mirror.versions(m.client, pluginID, compatOpts)
+	} else {
+		versions, err = m.grafanaCompatiblePluginVersions(pluginID, compatOpts)
+	}
 	if err != nil {
 		return VersionData{}, err
 	}
@@ -75,14 +75,17 @@ func SelectSystemCompatibleVersion(log log.PrettyLogger, versions []Version, plu
 }
 
 func checksum(v Version, compatOpts SystemCompatOpts) string {
-	if v.Arch != nil {
-		archMeta, exists := v.Arch[compatOpts.OSAndArch()]
-		if !exists {
-			archMeta = v.Arch["any"]
-		}
-		return archMeta.SHA256
+	archMeta, _ := systemPackage(v.Arch, compatOpts)
+	return archMeta.SHA256
+}
+
+// systemPackage returns the package of a version for the system, or the package supporting any system.
+func systemPackage(arch map[string]ArchMeta, compatOpts SystemCompatOpts) (ArchMeta, bool) {
+	if archMeta, exists := arch[compatOpts.OSAndArch()]; exists {
+		return archMeta, true
 	}
-	return ""
+	archMeta, exists := arch["any"]
+	return archMeta, exists
 }
 
 func supportsCurrentArch(version Version, compatOpts SystemCompatOpts) bool {
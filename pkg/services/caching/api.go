@@ -0,0 +1,41 @@
+package caching
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *OSSCachingService) registerAPIEndpoints() {
+	if s.routeRegister == nil || s.accessControl == nil {
+		return
+	}
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := datasources.ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	s.routeRegister.Group("/api/datasources/uid/:uid/cache", func(cacheRoute routing.RouteRegister) {
+		cacheRoute.Post("/clean", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.cleanHandler))
+	})
+}
+
+// swagger:route POST /datasources/uid/{uid}/cache/clean datasources cleanDataSourceCache
+//
+// Purge the query and resource cache of a data source.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *OSSCachingService) cleanHandler(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if err := s.PurgeDataSource(c.Req.Context(), c.SignedInUser.GetOrgID(), uid); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to purge the data source cache", err)
+	}
+	return response.Success("Data source cache purged")
+}
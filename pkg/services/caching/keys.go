@@ -0,0 +1,134 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	// maxTTL is the longest time an entry can be cached for.
+	maxTTL = 24 * time.Hour
+	// generationExpiration must be longer than maxTTL so that purged entries
+	// cannot be read again when the generation of a data source expires.
+	generationExpiration = 7 * 24 * time.Hour
+
+	keyPrefix = "query-cache"
+)
+
+// volatileQueryKeys are properties of a query that change between requests
+// without changing its result.
+var volatileQueryKeys = []string{"requestId", "queryCachingTTL", "key"}
+
+type normalizedQuery struct {
+	RefID         string         `json:"refId"`
+	QueryType     string         `json:"queryType"`
+	MaxDataPoints int64          `json:"maxDataPoints"`
+	Interval      time.Duration  `json:"interval"`
+	From          int64          `json:"from"`
+	To            int64          `json:"to"`
+	JSON          map[string]any `json:"json"`
+}
+
+type normalizedRequest struct {
+	OrgID             int64             `json:"orgId"`
+	DataSourceUID     string            `json:"dataSourceUid"`
+	DataSourceUpdated int64             `json:"dataSourceUpdated"`
+	Generation        string            `json:"generation"`
+	User              string            `json:"user,omitempty"`
+	Queries           []normalizedQuery `json:"queries"`
+}
+
+// queryCacheKey returns the cache key for a query request. Queries are normalized so that
+// requests that only differ by the order of their queries or by properties that do not
+// affect the result share the same key. A relative time range, such as now-1h to now, is
+// truncated to the TTL so it keeps hitting the same entry until it expires, while an
+// absolute time range is part of the key as is.
+func queryCacheKey(req *backend.QueryDataRequest, generation string, ttl time.Duration, relativeTimeRange bool) (string, error) {
+	ds := req.PluginContext.DataSourceInstanceSettings
+	nr := normalizedRequest{
+		OrgID:             req.PluginContext.OrgID,
+		DataSourceUID:     ds.UID,
+		DataSourceUpdated: ds.Updated.UnixNano(),
+		Generation:        generation,
+		Queries:           make([]normalizedQuery, 0, len(req.Queries)),
+	}
+
+	// Data sources that forward the identity of the user can return different results per user.
+	if forwardsIdentity(ds) && req.PluginContext.User != nil {
+		nr.User = req.PluginContext.User.Login
+	}
+
+	for _, q := range req.Queries {
+		model := map[string]any{}
+		if len(q.JSON) > 0 {
+			if err := json.Unmarshal(q.JSON, &model); err != nil {
+				return "", fmt.Errorf("failed to parse query %s: %w", q.RefID, err)
+			}
+		}
+		for _, k := range volatileQueryKeys {
+			delete(model, k)
+		}
+		from, to := q.TimeRange.From, q.TimeRange.To
+		if relativeTimeRange {
+			from, to = from.Truncate(ttl), to.Truncate(ttl)
+		}
+		nr.Queries = append(nr.Queries, normalizedQuery{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          from.UnixMilli(),
+			To:            to.UnixMilli(),
+			JSON:          model,
+		})
+	}
+	sort.Slice(nr.Queries, func(i, j int) bool {
+		return nr.Queries[i].RefID < nr.Queries[j].RefID
+	})
+
+	// map keys are sorted by the encoder, which makes the encoding stable.
+	b, err := json.Marshal(nr)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:q:%d:%s:%s", keyPrefix, nr.OrgID, ds.UID, hash(b)), nil
+}
+
+// resourceCacheKey returns the cache key for a resource request.
+func resourceCacheKey(req *backend.CallResourceRequest, generation string) string {
+	ds := req.PluginContext.DataSourceInstanceSettings
+	user := ""
+	if forwardsIdentity(ds) && req.PluginContext.User != nil {
+		user = req.PluginContext.User.Login
+	}
+	h := hash([]byte(fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%s\x00%s", ds.Updated.UnixNano(), generation, user, req.Method, req.URL, req.Body)))
+	return fmt.Sprintf("%s:r:%d:%s:%s", keyPrefix, req.PluginContext.OrgID, ds.UID, h)
+}
+
+func generationKey(orgID int64, dsUID string) string {
+	return fmt.Sprintf("%s:generation:%d:%s", keyPrefix, orgID, dsUID)
+}
+
+func forwardsIdentity(ds *backend.DataSourceInstanceSettings) bool {
+	if ds == nil || len(ds.JSONData) == 0 {
+		return false
+	}
+	var jsonData struct {
+		OauthPassThru bool `json:"oauthPassThru"`
+	}
+	if err := json.Unmarshal(ds.JSONData, &jsonData); err != nil {
+		return false
+	}
+	return jsonData.OauthPassThru
+}
+
+func hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -2,12 +2,32 @@ package caching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	XCacheHeader = "X-Cache"
+	// XRelativeTimeRangeHeader is sent by the frontend when the time range of a query request
+	// is relative to the current time.
+	XRelativeTimeRangeHeader = "X-Relative-Time-Range"
 )
 
 const (
-	XCacheHeader   = "X-Cache"
 	StatusHit      = "HIT"
 	StatusMiss     = "MISS"
 	StatusBypass   = "BYPASS"
@@ -35,10 +55,6 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
-}
-
 type CachingService interface {
 	// HandleQueryRequest uses a QueryDataRequest to check the cache for any existing results for that query.
 	// If none are found, it should return false and a CachedQueryDataResponse with an UpdateCacheFn which can be used to update the results cache after the fact.
@@ -49,16 +65,241 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+func ProvideCachingService(cfg *setting.Cfg, cache remotecache.CacheStorage, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl, features featuremgmt.FeatureToggles) *OSSCachingService {
+	s := &OSSCachingService{
+		cfg:           cfg.QueryCaching,
+		cache:         cache,
+		routeRegister: routeRegister,
+		accessControl: accessControl,
+		log:           log.New("caching"),
+	}
+
+	// the cache is only used by the caching middleware, which is behind the feature toggle
+	if s.cfg.Enabled && features.IsEnabledGlobally(featuremgmt.FlagUseCachingService) {
+		s.registerAPIEndpoints()
+	}
+
+	return s
+}
+
+// OSSCachingService caches query results and resource responses in the remote cache.
+// The zero value does not cache anything.
 type OSSCachingService struct {
+	cfg           setting.QueryCachingSettings
+	cache         remotecache.CacheStorage
+	routeRegister routing.RouteRegister
+	accessControl accesscontrol.AccessControl
+	log           log.Logger
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if s.cache == nil || req == nil {
+		return false, CachedQueryDataResponse{}
+	}
+	if !s.cfg.Enabled {
+		setCacheHeader(ctx, StatusDisabled)
+		return false, CachedQueryDataResponse{}
+	}
+
+	ds := req.PluginContext.DataSourceInstanceSettings
+	ttl := s.queryTTL(ds)
+	if ds == nil || ttl <= 0 || skipCache(ctx) {
+		setCacheHeader(ctx, StatusBypass)
+		return false, CachedQueryDataResponse{}
+	}
+
+	generation, err := s.generation(ctx, req.PluginContext.OrgID, ds.UID)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to read cache generation", "datasourceUid", ds.UID, "error", err)
+		setCacheHeader(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req, generation, ttl, relativeTimeRange(ctx))
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to compute query cache key", "datasourceUid", ds.UID, "error", err)
+		setCacheHeader(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	updateFn := func(ctx context.Context, resp *backend.QueryDataResponse) {
+		if resp == nil || hasErrors(resp) {
+			return
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			s.log.FromContext(ctx).Warn("Failed to encode query response for the cache", "datasourceUid", ds.UID, "error", err)
+			return
+		}
+		s.set(ctx, key, b, ttl)
+	}
+
+	b, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			s.log.FromContext(ctx).Warn("Failed to read query cache", "datasourceUid", ds.UID, "error", err)
+		}
+		setCacheHeader(ctx, StatusMiss)
+		return false, CachedQueryDataResponse{UpdateCacheFn: updateFn}
+	}
+
+	resp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to decode cached query response", "datasourceUid", ds.UID, "error", err)
+		setCacheHeader(ctx, StatusMiss)
+		return false, CachedQueryDataResponse{UpdateCacheFn: updateFn}
+	}
+
+	setCacheHeader(ctx, StatusHit)
+	return true, CachedQueryDataResponse{Response: resp}
 }
 
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if s.cache == nil || req == nil {
+		return false, CachedResourceDataResponse{}
+	}
+	if !s.cfg.Enabled {
+		setCacheHeader(ctx, StatusDisabled)
+		return false, CachedResourceDataResponse{}
+	}
+
+	ds := req.PluginContext.DataSourceInstanceSettings
+	ttl := s.cfg.ResourcesTTL
+	if ds == nil || ttl <= 0 || req.Method != http.MethodGet || skipCache(ctx) {
+		setCacheHeader(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	generation, err := s.generation(ctx, req.PluginContext.OrgID, ds.UID)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to read cache generation", "datasourceUid", ds.UID, "error", err)
+		setCacheHeader(ctx, StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key := resourceCacheKey(req, generation)
+
+	// Plugins can stream a resource response in several parts, only responses
+	// sent at once are cached.
+	var mu sync.Mutex
+	sent := 0
+	updateFn := func(ctx context.Context, resp *backend.CallResourceResponse) {
+		mu.Lock()
+		sent++
+		n := sent
+		mu.Unlock()
+
+		if n > 1 {
+			if err := s.cache.Delete(ctx, key); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+				s.log.FromContext(ctx).Warn("Failed to delete streamed resource response from the cache", "datasourceUid", ds.UID, "error", err)
+			}
+			return
+		}
+		if resp == nil || resp.Status < http.StatusOK || resp.Status >= http.StatusMultipleChoices {
+			return
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			s.log.FromContext(ctx).Warn("Failed to encode resource response for the cache", "datasourceUid", ds.UID, "error", err)
+			return
+		}
+		s.set(ctx, key, b, ttl)
+	}
+
+	b, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			s.log.FromContext(ctx).Warn("Failed to read resource cache", "datasourceUid", ds.UID, "error", err)
+		}
+		setCacheHeader(ctx, StatusMiss)
+		return false, CachedResourceDataResponse{UpdateCacheFn: updateFn}
+	}
+
+	resp := &backend.CallResourceResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to decode cached resource response", "datasourceUid", ds.UID, "error", err)
+		setCacheHeader(ctx, StatusMiss)
+		return false, CachedResourceDataResponse{UpdateCacheFn: updateFn}
+	}
+
+	setCacheHeader(ctx, StatusHit)
+	return true, CachedResourceDataResponse{Response: resp}
+}
+
+// PurgeDataSource removes all the cached query results and resource responses of a data source.
+// Entries are not deleted one by one: the cache generation of the data source is changed so
+// that existing entries are never read again and eventually expire.
+func (s *OSSCachingService) PurgeDataSource(ctx context.Context, orgID int64, dsUID string) error {
+	if s.cache == nil {
+		return nil
+	}
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return s.cache.Set(ctx, generationKey(orgID, dsUID), []byte(generation), generationExpiration)
+}
+
+// queryTTL returns the TTL configured for the data source, or the default one.
+func (s *OSSCachingService) queryTTL(ds *backend.DataSourceInstanceSettings) time.Duration {
+	ttl := s.cfg.TTL
+	if ds != nil && len(ds.JSONData) > 0 {
+		var jsonData struct {
+			QueryCachingTTL *int64 `json:"queryCachingTTL"`
+		}
+		if err := json.Unmarshal(ds.JSONData, &jsonData); err == nil && jsonData.QueryCachingTTL != nil {
+			ttl = time.Duration(*jsonData.QueryCachingTTL) * time.Millisecond
+		}
+	}
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl
+}
+
+func (s *OSSCachingService) generation(ctx context.Context, orgID int64, dsUID string) (string, error) {
+	b, err := s.cache.Get(ctx, generationKey(orgID, dsUID))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return "0", nil
+		}
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (s *OSSCachingService) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if s.cfg.MaxValueSize > 0 && len(value) > s.cfg.MaxValueSize {
+		s.log.FromContext(ctx).Debug("Response is too large to be cached", "size", len(value), "maxSize", s.cfg.MaxValueSize)
+		return
+	}
+	if err := s.cache.Set(ctx, key, value, ttl); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to write to the cache", "error", err)
+	}
+}
+
+func hasErrors(resp *backend.QueryDataResponse) bool {
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func skipCache(ctx context.Context) bool {
+	reqCtx := contexthandler.FromContext(ctx)
+	return reqCtx != nil && reqCtx.SkipQueryCache
+}
+
+func relativeTimeRange(ctx context.Context) bool {
+	reqCtx := contexthandler.FromContext(ctx)
+	return reqCtx != nil && reqCtx.Req != nil && reqCtx.Req.Header.Get(XRelativeTimeRangeHeader) == "true"
+}
+
+func setCacheHeader(ctx context.Context, status string) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Resp == nil {
+		return
+	}
+	reqCtx.Resp.Header().Set(XCacheHeader, status)
 }
 
 var _ CachingService = &OSSCachingService{}
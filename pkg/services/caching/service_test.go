@@ -0,0 +1,327 @@
+package caching
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestHandleQueryRequest(t *testing.T) {
+	now := time.Date(2023, 10, 10, 10, 10, 0, 0, time.UTC)
+	newRequest := func(jsonData string, query string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID: 1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:      "ds",
+					JSONData: []byte(jsonData),
+				},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(query),
+				TimeRange: backend.TimeRange{From: now.Add(-time.Hour), To: now},
+			}},
+		}
+	}
+	response := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, []float64{1}))}},
+	}}
+
+	t.Run("should miss, then hit once the response is cached", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, header := newTestContext(false)
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up","requestId":"1"}`))
+		require.False(t, hit)
+		require.Equal(t, StatusMiss, header.Get(XCacheHeader))
+		require.NotNil(t, cr.UpdateCacheFn)
+		cr.UpdateCacheFn(ctx, response)
+
+		// the request id changes on every refresh and must not be part of the key
+		ctx, header = newTestContext(false)
+		hit, cr = s.HandleQueryRequest(ctx, newRequest(`{}`, `{"requestId":"2","expr":"up"}`))
+		require.True(t, hit)
+		require.Equal(t, StatusHit, header.Get(XCacheHeader))
+		require.Len(t, cr.Response.Responses["A"].Frames, 1)
+	})
+
+	t.Run("should not cache responses with errors", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, _ := newTestContext(false)
+		_, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Error: http.ErrAbortHandler},
+		}})
+
+		hit, _ := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		require.False(t, hit)
+	})
+
+	t.Run("should bypass the cache when requested", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, header := newTestContext(true)
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Equal(t, StatusBypass, header.Get(XCacheHeader))
+	})
+
+	t.Run("should use the TTL of the data source", func(t *testing.T) {
+		s := newTestService(t)
+		require.Equal(t, time.Minute, s.queryTTL(&backend.DataSourceInstanceSettings{JSONData: []byte(`{"queryCachingTTL":60000}`)}))
+		require.Equal(t, 5*time.Minute, s.queryTTL(&backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)}))
+
+		ctx, header := newTestContext(false)
+		hit, _ := s.HandleQueryRequest(ctx, newRequest(`{"queryCachingTTL":0}`, `{"expr":"up"}`))
+		require.False(t, hit)
+		require.Equal(t, StatusBypass, header.Get(XCacheHeader))
+	})
+
+	t.Run("should report disabled caching", func(t *testing.T) {
+		s := newTestService(t)
+		s.cfg.Enabled = false
+
+		ctx, header := newTestContext(false)
+		hit, _ := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		require.False(t, hit)
+		require.Equal(t, StatusDisabled, header.Get(XCacheHeader))
+	})
+
+	t.Run("should not return entries of a purged data source", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, _ := newTestContext(false)
+		_, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		cr.UpdateCacheFn(ctx, response)
+		hit, _ := s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		require.True(t, hit)
+
+		require.NoError(t, s.PurgeDataSource(ctx, 1, "ds"))
+		hit, _ = s.HandleQueryRequest(ctx, newRequest(`{}`, `{"expr":"up"}`))
+		require.False(t, hit)
+	})
+}
+
+func TestHandleResourceRequest(t *testing.T) {
+	newRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds"},
+			},
+			Method: method,
+			URL:    "/labels",
+		}
+	}
+
+	t.Run("should cache successful GET responses", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, _ := newTestContext(false)
+		hit, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte("ok")})
+
+		ctx, header := newTestContext(false)
+		hit, cr = s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.True(t, hit)
+		require.Equal(t, StatusHit, header.Get(XCacheHeader))
+		require.Equal(t, []byte("ok"), cr.Response.Body)
+	})
+
+	t.Run("should not cache streamed responses", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, _ := newTestContext(false)
+		_, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte("part 1")})
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte("part 2")})
+
+		hit, _ := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.False(t, hit)
+	})
+
+	t.Run("should bypass other methods", func(t *testing.T) {
+		s := newTestService(t)
+
+		ctx, header := newTestContext(false)
+		hit, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodPost))
+		require.False(t, hit)
+		require.Nil(t, cr.UpdateCacheFn)
+		require.Equal(t, StatusBypass, header.Get(XCacheHeader))
+	})
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	now := time.Date(2023, 10, 10, 10, 10, 0, 0, time.UTC)
+	newRequest := func(to time.Time, queries ...backend.DataQuery) *backend.QueryDataRequest {
+		for i := range queries {
+			queries[i].TimeRange = backend.TimeRange{From: to.Add(-time.Hour), To: to}
+		}
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds"},
+			},
+			Queries: queries,
+		}
+	}
+	a := backend.DataQuery{RefID: "A", JSON: []byte(`{"expr":"up","legend":"x"}`)}
+	b := backend.DataQuery{RefID: "B", JSON: []byte(`{"expr":"down"}`)}
+
+	key := func(req *backend.QueryDataRequest, generation string) string {
+		k, err := queryCacheKey(req, generation, 5*time.Minute, true)
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key(newRequest(now, a, b), "0")
+	require.Equal(t, base, key(newRequest(now, b, a), "0"), "order of queries")
+	require.Equal(t, base, key(newRequest(now.Add(time.Minute), a, b), "0"), "time range within the TTL")
+	require.Equal(t, base, key(newRequest(now, backend.DataQuery{RefID: "A", JSON: []byte(`{"legend":"x","expr":"up"}`)}, b), "0"), "order of properties")
+	require.NotEqual(t, base, key(newRequest(now.Add(10*time.Minute), a, b), "0"), "time range after the TTL")
+	require.NotEqual(t, base, key(newRequest(now, a), "0"), "different queries")
+	require.NotEqual(t, base, key(newRequest(now, a, b), "1"), "different generation")
+
+	t.Run("Absolute time ranges within the TTL do not share a key", func(t *testing.T) {
+		absoluteKey := func(req *backend.QueryDataRequest) string {
+			k, err := queryCacheKey(req, "0", 5*time.Minute, false)
+			require.NoError(t, err)
+			return k
+		}
+
+		require.NotEqual(t, absoluteKey(newRequest(now, a, b)), absoluteKey(newRequest(now.Add(time.Minute), a, b)))
+		require.Equal(t, absoluteKey(newRequest(now, a, b)), absoluteKey(newRequest(now, b, a)))
+	})
+}
+
+func TestRelativeTimeRange(t *testing.T) {
+	require.False(t, relativeTimeRange(context.Background()))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ds/query", nil)
+	ctx := ctxkey.Set(context.Background(), &contextmodel.ReqContext{Context: &web.Context{Req: req}})
+	require.False(t, relativeTimeRange(ctx))
+
+	req.Header.Set(XRelativeTimeRangeHeader, "true")
+	require.True(t, relativeTimeRange(ctx))
+}
+
+func TestProvideCachingService(t *testing.T) {
+	for name, tc := range map[string]struct {
+		enabled   bool
+		features  featuremgmt.FeatureToggles
+		endpoints int
+	}{
+		"Purge API is registered with the feature toggle":        {enabled: true, features: featuremgmt.WithFeatures(featuremgmt.FlagUseCachingService), endpoints: 1},
+		"Purge API is not registered without the feature toggle": {enabled: true, features: featuremgmt.WithFeatures()},
+		"Purge API is not registered when caching is disabled":   {features: featuremgmt.WithFeatures(featuremgmt.FlagUseCachingService)},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.QueryCaching.Enabled = tc.enabled
+			routes := routing.NewRouteRegister()
+			ProvideCachingService(cfg, newFakeCacheStorage(), routes, actest.FakeAccessControl{}, tc.features)
+
+			router := &fakeRouter{}
+			routes.Register(router)
+			require.Len(t, router.patterns, tc.endpoints)
+		})
+	}
+}
+
+type fakeRouter struct {
+	patterns []string
+}
+
+func (r *fakeRouter) Handle(_, pattern string, _ []web.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func (r *fakeRouter) Get(pattern string, _ ...web.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func newTestService(t *testing.T) *OSSCachingService {
+	t.Helper()
+	return &OSSCachingService{
+		cfg: setting.QueryCachingSettings{
+			Enabled:      true,
+			TTL:          5 * time.Minute,
+			ResourcesTTL: time.Minute,
+			MaxValueSize: 1024 * 1024,
+		},
+		cache: newFakeCacheStorage(),
+		log:   log.NewNopLogger(),
+	}
+}
+
+func newTestContext(skipCache bool) (context.Context, http.Header) {
+	rec := httptest.NewRecorder()
+	reqCtx := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Resp: web.NewResponseWriter(http.MethodPost, rec),
+		},
+		SkipQueryCache: skipCache,
+	}
+	return ctxkey.Set(context.Background(), reqCtx), reqCtx.Resp.Header()
+}
+
+type fakeCacheStorage struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func newFakeCacheStorage() *fakeCacheStorage {
+	return &fakeCacheStorage{items: map[string][]byte{}}
+}
+
+func (f *fakeCacheStorage) Get(_ context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeCacheStorage) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeCacheStorage) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, key)
+	return nil
+}
+
+func (f *fakeCacheStorage) Count(_ context.Context, _ string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int64(len(f.items)), nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
@@ -29,7 +30,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +45,7 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -60,6 +63,7 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -97,6 +101,7 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"clean up temporary files", srv.cleanUpTmpFiles},
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
+		{"delete expired deleted dashboards", srv.deleteExpiredDeletedDashboards},
 		{"delete expired images", srv.deleteExpiredImages},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
@@ -210,6 +215,16 @@ func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredDeletedDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	cmd := dashboards.DeleteExpiredDeletedDashboardsCommand{}
+	if err := srv.dashboardService.DeleteExpiredDeletedDashboards(ctx, &cmd); err != nil {
+		logger.Error("Failed to delete expired deleted dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired dashboards from recently deleted", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
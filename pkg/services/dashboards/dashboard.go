@@ -24,6 +24,9 @@ type DashboardService interface {
 	SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error)
 	SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) (model.HitList, error)
 	CountInFolder(ctx context.Context, orgID int64, folderUID string, user identity.Requester) (int64, error)
+	GetDeletedDashboards(ctx context.Context, query *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error)
+	RestoreDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error)
+	DeleteExpiredDeletedDashboards(ctx context.Context, cmd *DeleteExpiredDeletedDashboardsCommand) error
 }
 
 // PluginService is a service for operating on plugin dashboards.
@@ -74,4 +77,11 @@ type Store interface {
 	// the given parent folder ID.
 	CountDashboardsInFolder(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
 	DeleteDashboardsInFolder(ctx context.Context, request *DeleteDashboardsInFolderRequest) error
+	// GetDeletedDashboards returns the recently deleted bin of an organization.
+	GetDeletedDashboards(ctx context.Context, query *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error)
+	// RestoreDashboard restores a dashboard or folder from the recently deleted bin. For folders,
+	// the dashboards and subfolders deleted with it are restored too.
+	RestoreDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) ([]*Dashboard, error)
+	// DeleteExpiredDeletedDashboards removes entries of the recently deleted bin older than the retention.
+	DeleteExpiredDeletedDashboards(ctx context.Context, cmd *DeleteExpiredDeletedDashboardsCommand) error
 }
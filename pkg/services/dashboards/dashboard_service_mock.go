@@ -78,6 +78,20 @@ func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId
 	return r0
 }
 
+// DeleteExpiredDeletedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) DeleteExpiredDeletedDashboards(ctx context.Context, cmd *DeleteExpiredDeletedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredDeletedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -208,6 +222,32 @@ func (_m *FakeDashboardService) GetDashboards(ctx context.Context, query *GetDas
 	return r0, r1
 }
 
+// GetDeletedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetDeletedDashboards(ctx context.Context, query *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardsQuery) []*DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDeletedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImportDashboard provides a mock function with given fields: ctx, dto
 func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto)
@@ -234,6 +274,32 @@ func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDa
 	return r0, r1
 }
 
+// RestoreDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) (*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
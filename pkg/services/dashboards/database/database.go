@@ -658,6 +658,22 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		return dashboards.ErrDashboardNotFound
 	}
 
+	if cmd.SoftDelete {
+		folderUID, err := getFolderUID(sess, &dashboard)
+		if err != nil {
+			return err
+		}
+		deleted := time.Now()
+		if err := archiveDashboard(sess, &dashboard, folderUID, false, deleted); err != nil {
+			return err
+		}
+		if dashboard.IsFolder {
+			if err := archiveFolderChildren(sess, &dashboard, deleted); err != nil {
+				return err
+			}
+		}
+	}
+
 	deletes := []string{
 		"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
 		"DELETE FROM star WHERE dashboard_id = ? ",
@@ -666,6 +682,7 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		"DELETE FROM dashboard_version WHERE dashboard_id = ?",
 		"DELETE FROM dashboard_provisioning WHERE dashboard_id = ?",
 		"DELETE FROM dashboard_acl WHERE dashboard_id = ?",
+		"DELETE FROM library_element_connection WHERE kind = 1 AND connection_id = ?",
 	}
 
 	if dashboard.IsFolder {
@@ -761,6 +778,7 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 			"DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			"DELETE FROM dashboard_acl WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM library_element_connection WHERE kind = 1 AND connection_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 		}
 
 		_, err = sess.Exec("DELETE FROM annotation WHERE org_id = ? AND dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)", dashboard.OrgID, dashboard.OrgID, dashboard.ID)
@@ -1057,6 +1075,12 @@ func (d *dashboardStore) DeleteDashboardsInFolder(
 			return dashboards.ErrFolderNotFound
 		}
 
+		if req.SoftDelete {
+			if err := archiveFolderChildren(sess, &dashboard, time.Now()); err != nil {
+				return err
+			}
+		}
+
 		if err := d.deleteChildrenDashboardAssociations(sess, &dashboard); err != nil {
 			return err
 		}
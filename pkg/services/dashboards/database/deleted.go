@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// deletedDashboardData is stored with an entry of the recently deleted bin. It holds
+// everything needed to restore the dashboard: the model, its history, permissions and
+// library panel connections. Stars, playlist items and annotations are not kept.
+type deletedDashboardData struct {
+	Dashboard               *dashboards.Dashboard            `json:"dashboard"`
+	Versions                []*dashver.DashboardVersion      `json:"versions"`
+	ACL                     []*dashboards.DashboardACL       `json:"acl"`
+	Permissions             []deletedPermission              `json:"permissions"`
+	LibraryPanelConnections []model.LibraryElementConnection `json:"libraryPanelConnections"`
+	// DeletedWithFolder is set when the dashboard was deleted as part of its folder,
+	// restoring the folder will then restore the dashboard as well.
+	DeletedWithFolder bool `json:"deletedWithFolder"`
+}
+
+type deletedPermission struct {
+	RoleID int64  `xorm:"role_id" json:"roleId"`
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+}
+
+func resourceScope(dash *dashboards.Dashboard) string {
+	if dash.IsFolder {
+		return dashboards.ScopeFoldersProvider.GetResourceScopeUID(dash.UID)
+	}
+	return ac.GetResourceScopeUID("dashboards", dash.UID)
+}
+
+// archiveDashboard copies the dashboard and its associations into the recently deleted bin.
+// It must be called before the dashboard is deleted.
+func archiveDashboard(sess *db.Session, dash *dashboards.Dashboard, folderUID string, deletedWithFolder bool, deleted time.Time) error {
+	data := deletedDashboardData{Dashboard: dash, DeletedWithFolder: deletedWithFolder}
+	if err := sess.Where("dashboard_id = ?", dash.ID).OrderBy("version").Find(&data.Versions); err != nil {
+		return err
+	}
+	if err := sess.Where("dashboard_id = ?", dash.ID).Find(&data.ACL); err != nil {
+		return err
+	}
+	err := sess.SQL("SELECT permission.role_id, permission.action, permission.scope FROM permission INNER JOIN role ON permission.role_id = role.id WHERE permission.scope = ? AND role.org_id = ?",
+		resourceScope(dash), dash.OrgID).Find(&data.Permissions)
+	if err != nil {
+		return err
+	}
+	if err := sess.Where("kind = ? AND connection_id = ?", int64(model.Dashboard), dash.ID).Find(&data.LibraryPanelConnections); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// a dashboard deleted, restored under the same uid and deleted again replaces its previous entry
+	if _, err := sess.Exec("DELETE FROM deleted_dashboard WHERE org_id = ? AND uid = ?", dash.OrgID, dash.UID); err != nil {
+		return err
+	}
+	_, err = sess.Nullable("folder_uid").Insert(&dashboards.DeletedDashboard{
+		OrgID:     dash.OrgID,
+		UID:       dash.UID,
+		FolderUID: folderUID,
+		Title:     dash.Title,
+		IsFolder:  dash.IsFolder,
+		Deleted:   deleted,
+		Data:      string(raw),
+	})
+	return err
+}
+
+// archiveFolderChildren archives the dashboards of the folder, which are deleted along with it.
+func archiveFolderChildren(sess *db.Session, folder *dashboards.Dashboard, deleted time.Time) error {
+	var children []*dashboards.Dashboard
+	// nolint:staticcheck
+	err := sess.Where("org_id = ? AND folder_id = ? AND is_folder = ?", folder.OrgID, folder.ID, false).Find(&children)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := archiveDashboard(sess, child, folder.UID, true, deleted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getFolderUID returns the uid of the folder of a dashboard, dashboards saved before
+// folder uids were introduced only reference their folder by id.
+func getFolderUID(sess *db.Session, dash *dashboards.Dashboard) (string, error) {
+	// nolint:staticcheck
+	if dash.IsFolder || dash.FolderUID != "" || dash.FolderID == 0 {
+		return dash.FolderUID, nil
+	}
+	var uid string
+	// nolint:staticcheck
+	if _, err := sess.Table("dashboard").Where("id = ?", dash.FolderID).Cols("uid").Get(&uid); err != nil {
+		return "", err
+	}
+	return uid, nil
+}
+
+func (d *dashboardStore) GetDeletedDashboards(ctx context.Context, query *dashboards.GetDeletedDashboardsQuery) ([]*dashboards.DeletedDashboard, error) {
+	result := make([]*dashboards.DeletedDashboard, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.UID != "" {
+			sess.And("uid = ?", query.UID)
+		} else {
+			// the data is only needed to restore a single dashboard or folder
+			sess.Cols("id", "org_id", "uid", "folder_uid", "title", "is_folder", "deleted")
+		}
+		if query.Limit > 0 {
+			var offset int64
+			if query.Page > 0 {
+				offset = query.Limit * (query.Page - 1)
+			}
+			sess.Limit(int(query.Limit), int(offset))
+		}
+		return sess.OrderBy("deleted DESC, id DESC").Find(&result)
+	})
+	return result, err
+}
+
+// RestoreDashboard moves a dashboard or folder out of the recently deleted bin. Restoring
+// a folder restores the dashboards and subfolders deleted with it, all restored items are
+// returned with the requested one first.
+func (d *dashboardStore) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) ([]*dashboards.Dashboard, error) {
+	var restored []*dashboards.Dashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		entry := dashboards.DeletedDashboard{}
+		has, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID).Get(&entry)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrDeletedDashboardNotFound
+		}
+
+		folderUID := entry.FolderUID
+		if cmd.FolderUID != nil {
+			folderUID = *cmd.FolderUID
+		}
+		restored, err = d.restoreDashboard(sess, &entry, folderUID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (d *dashboardStore) restoreDashboard(sess *db.Session, entry *dashboards.DeletedDashboard, folderUID string) ([]*dashboards.Dashboard, error) {
+	var data deletedDashboardData
+	if err := json.Unmarshal([]byte(entry.Data), &data); err != nil {
+		return nil, fmt.Errorf("failed to read deleted dashboard: %w", err)
+	}
+	if data.Dashboard == nil {
+		return nil, dashboards.ErrDashboardCorrupt
+	}
+	dash := data.Dashboard
+	oldID := dash.ID
+
+	var folderID int64
+	if folderUID != "" {
+		parent := dashboards.Dashboard{}
+		has, err := sess.Where("org_id = ? AND uid = ? AND is_folder = ?", entry.OrgID, folderUID, d.store.GetDialect().BooleanStr(true)).Get(&parent)
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			return nil, dashboards.ErrFolderNotFound
+		}
+		folderID = parent.ID
+	}
+
+	exists, err := sess.Table("dashboard").Where("org_id = ? AND uid = ?", entry.OrgID, entry.UID).Exist()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, dashboards.ErrDashboardWithSameUIDExists
+	}
+
+	dash.ID = 0
+	dash.FolderUID = folderUID
+	// nolint:staticcheck
+	dash.FolderID = folderID
+	if dash.IsFolder {
+		// nested folders are linked to their parent by uid only
+		// nolint:staticcheck
+		dash.FolderID = 0
+	}
+	if _, err := getExistingDashboardByTitleAndFolder(sess, dash, d.store.GetDialect(), false, false); err != nil {
+		return nil, err
+	}
+	if _, err := sess.Nullable("folder_uid").Insert(dash); err != nil {
+		return nil, err
+	}
+	dash.SetID(dash.ID)
+
+	for _, version := range data.Versions {
+		version.ID = 0
+		version.DashboardID = dash.ID
+		if _, err := sess.Insert(version); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tag := range dash.GetTags() {
+		if _, err := sess.Insert(dashboardTag{DashboardId: dash.ID, Term: tag}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, acl := range data.ACL {
+		acl.ID = 0
+		acl.DashboardID = dash.ID
+		if _, err := sess.Insert(acl); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for _, p := range data.Permissions {
+		// the role may have been removed while the dashboard was deleted
+		roleExists, err := sess.Table("role").Where("id = ?", p.RoleID).Exist()
+		if err != nil {
+			return nil, err
+		}
+		if !roleExists {
+			continue
+		}
+		permission := ac.Permission{RoleID: p.RoleID, Action: p.Action, Scope: p.Scope, Created: now, Updated: now}
+		permission.Kind, permission.Attribute, permission.Identifier = permission.SplitScope()
+		if _, err := sess.Insert(&permission); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range data.LibraryPanelConnections {
+		elementExists, err := sess.Table("library_element").Where("id = ?", c.ElementID).Exist()
+		if err != nil {
+			return nil, err
+		}
+		if !elementExists {
+			continue
+		}
+		c.ID = 0
+		c.ConnectionID = dash.ID
+		if _, err := sess.Insert(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := sess.Exec("DELETE FROM deleted_dashboard WHERE id = ?", entry.ID); err != nil {
+		return nil, err
+	}
+
+	if d.emitEntityEvent() {
+		if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeCreate)); err != nil {
+			return nil, err
+		}
+	}
+
+	d.log.Debug("Restored deleted dashboard", "uid", dash.UID, "orgId", dash.OrgID, "previousId", oldID)
+	restored := []*dashboards.Dashboard{dash}
+	if !dash.IsFolder {
+		return restored, nil
+	}
+
+	var children []*dashboards.DeletedDashboard
+	if err := sess.Where("org_id = ? AND folder_uid = ?", entry.OrgID, entry.UID).OrderBy("deleted").Find(&children); err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if !child.IsFolder {
+			var childData struct {
+				DeletedWithFolder bool `json:"deletedWithFolder"`
+			}
+			if err := json.Unmarshal([]byte(child.Data), &childData); err != nil {
+				return nil, fmt.Errorf("failed to read deleted dashboard: %w", err)
+			}
+			if !childData.DeletedWithFolder {
+				continue
+			}
+		} else if child.Deleted.Before(entry.Deleted) {
+			// subfolders are deleted after their parent, earlier entries were deleted on their own
+			continue
+		}
+
+		childRestored, err := d.restoreDashboard(sess, child, entry.UID)
+		if err != nil {
+			var dashErr dashboards.DashboardErr
+			if errors.As(err, &dashErr) {
+				// conflicts with dashboards created since the deletion leave the child in the bin
+				d.log.Warn("Skipped restoring deleted dashboard with its folder", "uid", child.UID, "folderUid", entry.UID, "error", err)
+				continue
+			}
+			return nil, err
+		}
+		restored = append(restored, childRestored...)
+	}
+	return restored, nil
+}
+
+// DeleteExpiredDeletedDashboards permanently removes entries of the recently deleted bin
+// older than the configured retention.
+func (d *dashboardStore) DeleteExpiredDeletedDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredDeletedDashboardsCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM deleted_dashboard WHERE deleted < ?", time.Now().Add(-d.cfg.DashboardRecentlyDeletedRetention))
+		if err != nil {
+			return err
+		}
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
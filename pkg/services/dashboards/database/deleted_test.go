@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+)
+
+func TestIntegrationDeletedDashboards(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	var sqlStore db.DB
+	var dashStore *dashboardStore
+
+	setup := func() {
+		store, cfg := db.InitTestDBwithCfg(t)
+		cfg.DashboardRecentlyDeletedRetention = time.Hour
+		sqlStore = store
+		s, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+		require.NoError(t, err)
+		dashStore = s.(*dashboardStore)
+	}
+
+	softDelete := func(t *testing.T, dash *dashboards.Dashboard) {
+		t.Helper()
+		err := dashStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{
+			ID:                     dash.ID,
+			OrgID:                  dash.OrgID,
+			ForceDeleteFolderRules: true,
+			SoftDelete:             true,
+		})
+		require.NoError(t, err)
+	}
+
+	restore := func(uid string) ([]*dashboards.Dashboard, error) {
+		return dashStore.RestoreDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: uid, OrgID: 1})
+	}
+
+	t.Run("Should not keep hard deleted dashboards", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, dashStore, "dash", 1, 0, "", false)
+
+		err := dashStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: dash.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	t.Run("Should restore soft deleted dashboard with its associations", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashStore, "folder", 1, 0, "", true)
+		dash := insertTestDashboard(t, dashStore, "dash", 1, folder.ID, folder.UID, false, "prod", "webapp")
+		dash.Data.Set("description", "second version")
+		_, err := dashStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID:     1,
+			FolderUID: folder.UID,
+			FolderID:  folder.ID, // nolint:staticcheck
+			Dashboard: dash.Data,
+			Overwrite: true,
+		})
+		require.NoError(t, err)
+
+		var elementID int64
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			if _, err := sess.Insert(&dashboards.DashboardACL{
+				OrgID: 1, DashboardID: dash.ID, UserID: 42, Permission: dashboardaccess.PERMISSION_EDIT, Created: time.Now(), Updated: time.Now(),
+			}); err != nil {
+				return err
+			}
+
+			role := ac.Role{OrgID: 1, UID: "managed_1_users_42", Name: "managed:users:42:permissions", Created: time.Now(), Updated: time.Now()}
+			if _, err := sess.Insert(&role); err != nil {
+				return err
+			}
+			permission := ac.Permission{RoleID: role.ID, Action: dashboards.ActionDashboardsWrite, Scope: ac.GetResourceScopeUID("dashboards", dash.UID), Created: time.Now(), Updated: time.Now()}
+			permission.Kind, permission.Attribute, permission.Identifier = permission.SplitScope()
+			if _, err := sess.Insert(&permission); err != nil {
+				return err
+			}
+
+			element := model.LibraryElement{OrgID: 1, UID: "lib", Name: "lib", Kind: 1, Model: []byte("{}"), Created: time.Now(), Updated: time.Now()}
+			if _, err := sess.Insert(&element); err != nil {
+				return err
+			}
+			elementID = element.ID
+			_, err := sess.Insert(&model.LibraryElementConnection{ElementID: element.ID, Kind: int64(model.Dashboard), ConnectionID: dash.ID, Created: time.Now()})
+			return err
+		})
+		require.NoError(t, err)
+
+		softDelete(t, dash)
+
+		_, err = dashStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: dash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, dash.UID, deleted[0].UID)
+		require.Equal(t, folder.UID, deleted[0].FolderUID)
+		require.Equal(t, "dash", deleted[0].Title)
+		require.False(t, deleted[0].IsFolder)
+
+		restored, err := restore(dash.UID)
+		require.NoError(t, err)
+		require.Len(t, restored, 1)
+		require.NotEqual(t, dash.ID, restored[0].ID)
+
+		got, err := dashStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: dash.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, restored[0].ID, got.ID)
+		require.Equal(t, folder.UID, got.FolderUID)
+		// nolint:staticcheck
+		require.Equal(t, folder.ID, got.FolderID)
+		require.Equal(t, "second version", got.Data.Get("description").MustString())
+
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var versions []*dashver.DashboardVersion
+			require.NoError(t, sess.Where("dashboard_id = ?", got.ID).OrderBy("version").Find(&versions))
+			require.Len(t, versions, 2)
+			require.Equal(t, 2, versions[1].Version)
+
+			tags, err := sess.Table("dashboard_tag").Where("dashboard_id = ?", got.ID).Count()
+			require.NoError(t, err)
+			require.EqualValues(t, 2, tags)
+
+			var acl []*dashboards.DashboardACL
+			require.NoError(t, sess.Where("dashboard_id = ?", got.ID).Find(&acl))
+			require.Len(t, acl, 1)
+			require.EqualValues(t, 42, acl[0].UserID)
+
+			permissions, err := sess.Table("permission").Where("scope = ?", ac.GetResourceScopeUID("dashboards", dash.UID)).Count()
+			require.NoError(t, err)
+			require.EqualValues(t, 1, permissions)
+
+			var connections []model.LibraryElementConnection
+			require.NoError(t, sess.Where("element_id = ?", elementID).Find(&connections))
+			require.Len(t, connections, 1)
+			require.Equal(t, got.ID, connections[0].ConnectionID)
+			return nil
+		})
+		require.NoError(t, err)
+
+		deleted, err = dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	t.Run("Should restore folder with the dashboards deleted with it", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashStore, "folder", 1, 0, "", true)
+		dash := insertTestDashboard(t, dashStore, "dash", 1, folder.ID, folder.UID, false)
+		deletedBefore := insertTestDashboard(t, dashStore, "deleted before", 1, folder.ID, folder.UID, false)
+		softDelete(t, deletedBefore)
+		softDelete(t, folder)
+
+		deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 3)
+
+		restored, err := restore(folder.UID)
+		require.NoError(t, err)
+		require.Len(t, restored, 2)
+		require.Equal(t, folder.UID, restored[0].UID)
+		require.True(t, restored[0].IsFolder)
+		require.Equal(t, dash.UID, restored[1].UID)
+		// nolint:staticcheck
+		require.Equal(t, restored[0].ID, restored[1].FolderID)
+
+		deleted, err = dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, deletedBefore.UID, deleted[0].UID)
+	})
+
+	t.Run("Should list deleted dashboards by page without their data", func(t *testing.T) {
+		setup()
+		first := insertTestDashboard(t, dashStore, "first", 1, 0, "", false)
+		softDelete(t, first)
+		softDelete(t, insertTestDashboard(t, dashStore, "second", 1, 0, "", false))
+		softDelete(t, insertTestDashboard(t, dashStore, "third", 1, 0, "", false))
+
+		var titles []string
+		for page := int64(1); page <= 3; page++ {
+			deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1, Limit: 2, Page: page})
+			require.NoError(t, err)
+			for _, d := range deleted {
+				require.Empty(t, d.Data)
+				titles = append(titles, d.Title)
+			}
+		}
+		require.Equal(t, []string{"third", "second", "first"}, titles)
+
+		deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1, UID: first.UID})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.NotEmpty(t, deleted[0].Data)
+	})
+
+	t.Run("Should archive dashboards deleted in folder", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashStore, "folder", 1, 0, "", true)
+		insertTestDashboard(t, dashStore, "dash", 1, folder.ID, folder.UID, false)
+
+		err := dashStore.DeleteDashboardsInFolder(context.Background(), &dashboards.DeleteDashboardsInFolderRequest{FolderUID: folder.UID, OrgID: 1, SoftDelete: true})
+		require.NoError(t, err)
+		softDelete(t, folder)
+
+		restored, err := restore(folder.UID)
+		require.NoError(t, err)
+		require.Len(t, restored, 2)
+	})
+
+	t.Run("Should fail to restore when the folder or uid is not available", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashStore, "folder", 1, 0, "", true)
+		dash := insertTestDashboard(t, dashStore, "dash", 1, folder.ID, folder.UID, false)
+		softDelete(t, dash)
+
+		_, err := restore("unknown")
+		require.ErrorIs(t, err, dashboards.ErrDeletedDashboardNotFound)
+
+		missing := "missing"
+		_, err = dashStore.RestoreDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: dash.UID, OrgID: 1, FolderUID: &missing})
+		require.ErrorIs(t, err, dashboards.ErrFolderNotFound)
+
+		newDash := insertTestDashboard(t, dashStore, "new dash", 1, 0, "", false)
+		newDash.SetUID(dash.UID)
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.ID(newDash.ID).Cols("uid").Update(newDash)
+			return err
+		})
+		require.NoError(t, err)
+		_, err = restore(dash.UID)
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameUIDExists)
+	})
+
+	t.Run("Should delete expired dashboards", func(t *testing.T) {
+		setup()
+		expired := insertTestDashboard(t, dashStore, "expired", 1, 0, "", false)
+		recent := insertTestDashboard(t, dashStore, "recent", 1, 0, "", false)
+		softDelete(t, expired)
+		softDelete(t, recent)
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE deleted_dashboard SET deleted = ? WHERE uid = ?", time.Now().Add(-2*time.Hour), expired.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		cmd := dashboards.DeleteExpiredDeletedDashboardsCommand{}
+		require.NoError(t, dashStore.DeleteExpiredDeletedDashboards(context.Background(), &cmd))
+		require.EqualValues(t, 1, cmd.DeletedRows)
+
+		deleted, err := dashStore.GetDeletedDashboards(context.Background(), &dashboards.GetDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, recent.UID, deleted[0].UID)
+	})
+}
@@ -282,12 +282,30 @@ type DeleteDashboardCommand struct {
 	ID                     int64
 	OrgID                  int64
 	ForceDeleteFolderRules bool
+	// SoftDelete keeps the dashboard in the recently deleted bin, so that it can be restored.
+	SoftDelete bool
 }
 
 type DeleteOrphanedProvisionedDashboardsCommand struct {
 	ReaderNames []string
 }
 
+// RestoreDeletedDashboardCommand restores a dashboard or folder from the recently deleted bin.
+type RestoreDeletedDashboardCommand struct {
+	UID   string
+	OrgID int64
+	// FolderUID is the folder to restore to, defaults to the folder the dashboard was deleted from.
+	FolderUID *string
+
+	SignedInUser identity.Requester
+}
+
+// DeleteExpiredDeletedDashboardsCommand permanently removes dashboards and folders
+// deleted before the recently deleted retention period.
+type DeleteExpiredDeletedDashboardsCommand struct {
+	DeletedRows int64
+}
+
 //
 // QUERIES
 //
@@ -403,8 +421,37 @@ func FromDashboard(dash *Dashboard) *folder.Folder {
 }
 
 type DeleteDashboardsInFolderRequest struct {
-	FolderUID string
-	OrgID     int64
+	FolderUID  string
+	OrgID      int64
+	SoftDelete bool
+}
+
+//
+// RECENTLY DELETED
+//
+
+// DeletedDashboard is a dashboard or folder in the recently deleted bin. Data holds
+// what is needed to restore it, including versions and permissions.
+type DeletedDashboard struct {
+	ID        int64     `xorm:"pk autoincr 'id'" json:"-"`
+	OrgID     int64     `xorm:"org_id" json:"-"`
+	UID       string    `xorm:"uid" json:"uid"`
+	FolderUID string    `xorm:"folder_uid" json:"folderUid"`
+	Title     string    `json:"title"`
+	IsFolder  bool      `json:"isFolder"`
+	Deleted   time.Time `json:"deleted"`
+	Data      string    `json:"-"`
+}
+
+// GetDeletedDashboardsQuery lists the recently deleted bin of an organization.
+type GetDeletedDashboardsQuery struct {
+	OrgID int64
+	// UID limits the result to a single dashboard or folder when set.
+	UID string
+
+	// Pagination options
+	Limit int64
+	Page  int64
 }
 
 //
@@ -452,10 +452,61 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 			return dashboards.ErrDashboardCannotDeleteProvisionedDashboard
 		}
 	}
-	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}
+	// provisioned dashboards are not kept in the recently deleted bin, they would be recreated by the provisioner
+	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId, SoftDelete: validateProvisionedDashboard && dr.features.IsEnabled(ctx, featuremgmt.FlagDashboardRestore)}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
+func (dr *DashboardServiceImpl) GetDeletedDashboards(ctx context.Context, query *dashboards.GetDeletedDashboardsQuery) ([]*dashboards.DeletedDashboard, error) {
+	return dr.dashboardStore.GetDeletedDashboards(ctx, query)
+}
+
+// RestoreDashboard restores a dashboard from the recently deleted bin. Folders are restored
+// by the folder service, which also restores them in the folder table.
+func (dr *DashboardServiceImpl) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) (*dashboards.Dashboard, error) {
+	deleted, err := dr.dashboardStore.GetDeletedDashboards(ctx, &dashboards.GetDeletedDashboardsQuery{OrgID: cmd.OrgID, UID: cmd.UID})
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, dashboards.ErrDeletedDashboardNotFound
+	}
+
+	if deleted[0].IsFolder {
+		f, err := dr.folderService.Restore(ctx, &folder.RestoreFolderCommand{UID: cmd.UID, OrgID: cmd.OrgID, ParentUID: cmd.FolderUID, SignedInUser: cmd.SignedInUser})
+		if err != nil {
+			return nil, err
+		}
+		return dr.dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: f.UID, OrgID: f.OrgID})
+	}
+
+	folderUID := deleted[0].FolderUID
+	if cmd.FolderUID != nil {
+		folderUID = *cmd.FolderUID
+	}
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(accesscontrol.GeneralFolderUID)
+	if folderUID != "" {
+		scope = dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+	}
+	canCreate, err := dr.ac.Evaluate(ctx, cmd.SignedInUser, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate, scope))
+	if err != nil {
+		return nil, err
+	}
+	if !canCreate {
+		return nil, dashboards.ErrDashboardUpdateAccessDenied
+	}
+
+	restored, err := dr.dashboardStore.RestoreDashboard(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return restored[0], nil
+}
+
+func (dr *DashboardServiceImpl) DeleteExpiredDeletedDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredDeletedDashboardsCommand) error {
+	return dr.dashboardStore.DeleteExpiredDeletedDashboards(ctx, cmd)
+}
+
 func (dr *DashboardServiceImpl) ImportDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO) (
 	*dashboards.Dashboard, error) {
 	if err := validateDashboardRefreshInterval(dto.Dashboard); err != nil {
@@ -618,7 +669,11 @@ func (dr DashboardServiceImpl) CountInFolder(ctx context.Context, orgID int64, f
 }
 
 func (dr *DashboardServiceImpl) DeleteInFolder(ctx context.Context, orgID int64, folderUID string, u identity.Requester) error {
-	return dr.dashboardStore.DeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{FolderUID: folderUID, OrgID: orgID})
+	return dr.dashboardStore.DeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{
+		FolderUID:  folderUID,
+		OrgID:      orgID,
+		SoftDelete: dr.features.IsEnabled(ctx, featuremgmt.FlagDashboardRestore),
+	})
 }
 
 func (dr *DashboardServiceImpl) Kind() string { return entity.StandardKindDashboard }
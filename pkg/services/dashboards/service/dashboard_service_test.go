@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/guardian"
@@ -33,6 +34,7 @@ func TestDashboardService(t *testing.T) {
 			dashboardStore:     &fakeStore,
 			folderService:      folderSvc,
 			dashAlertExtractor: &dummyDashAlertExtractor{},
+			features:           featuremgmt.WithFeatures(),
 		}
 
 		origNewDashboardGuardian := guardian.New
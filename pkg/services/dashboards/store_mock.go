@@ -94,6 +94,20 @@ func (_m *FakeDashboardStore) DeleteDashboardsInFolder(ctx context.Context, requ
 	return r0
 }
 
+// DeleteExpiredDeletedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) DeleteExpiredDeletedDashboards(ctx context.Context, cmd *DeleteExpiredDeletedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredDeletedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -264,6 +278,32 @@ func (_m *FakeDashboardStore) GetDashboardsByPluginID(ctx context.Context, query
 	return r0, r1
 }
 
+// GetDeletedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDeletedDashboards(ctx context.Context, query *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardsQuery) ([]*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardsQuery) []*DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDeletedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProvisionedDashboardData provides a mock function with given fields: ctx, name
 func (_m *FakeDashboardStore) GetProvisionedDashboardData(ctx context.Context, name string) ([]*DashboardProvisioning, error) {
 	ret := _m.Called(ctx, name)
@@ -342,6 +382,32 @@ func (_m *FakeDashboardStore) GetProvisionedDataByDashboardUID(ctx context.Conte
 	return r0, r1
 }
 
+// RestoreDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) ([]*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 []*Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) ([]*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) []*Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -1082,6 +1082,51 @@ var (
 			FrontendOnly: true,
 			Owner:        grafanaBiSquad,
 		},
+		{
+			Name:         "sqlExpressions",
+			Description:  "Enables using SQL as a server side expression to join and transform query results",
+			Stage:        FeatureStageExperimental,
+			FrontendOnly: false,
+			Owner:        grafanaAppPlatformSquad,
+		},
+		{
+			Name:            "jitterAlertRules",
+			Description:     "Distributes alert rule evaluations more evenly over time, by rule UID",
+			Stage:           FeatureStageExperimental,
+			FrontendOnly:    false,
+			Owner:           grafanaAlertingSquad,
+			RequiresRestart: true,
+		},
+		{
+			Name:            "alertingRuleSharding",
+			Description:     "Partitions the evaluation of alert rules between the members of a high availability cluster",
+			Stage:           FeatureStageExperimental,
+			FrontendOnly:    false,
+			Owner:           grafanaAlertingSquad,
+			RequiresRestart: true,
+		},
+		{
+			Name:            "livePipeline",
+			Description:     "Enables the Grafana Live pipeline that processes channel data with user-defined rules",
+			Stage:           FeatureStageExperimental,
+			FrontendOnly:    false,
+			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
+		},
+		{
+			Name:         "dashboardRestore",
+			Description:  "Keeps deleted dashboards and folders in a recently deleted bin, from where they can be restored",
+			Stage:        FeatureStageExperimental,
+			FrontendOnly: false,
+			Owner:        grafanaFrontendPlatformSquad,
+		},
+		{
+			Name:         "forecastExpressions",
+			Description:  "Enables the forecast server side expression that computes a seasonal baseline and outlier bands of time series",
+			Stage:        FeatureStageExperimental,
+			FrontendOnly: false,
+			Owner:        grafanaAlertingSquad,
+		},
 	}
 )
 
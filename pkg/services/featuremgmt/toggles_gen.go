@@ -594,4 +594,28 @@ const (
 	// FlagRegressionTransformation
 	// Enables regression analysis transformation
 	FlagRegressionTransformation = "regressionTransformation"
+
+	// FlagSqlExpressions
+	// Enables using SQL as a server side expression to join and transform query results
+	FlagSqlExpressions = "sqlExpressions"
+
+	// FlagJitterAlertRules
+	// Distributes alert rule evaluations more evenly over time, by rule UID
+	FlagJitterAlertRules = "jitterAlertRules"
+
+	// FlagAlertingRuleSharding
+	// Partitions the evaluation of alert rules between the members of a high availability cluster
+	FlagAlertingRuleSharding = "alertingRuleSharding"
+
+	// FlagLivePipeline
+	// Enables the Grafana Live pipeline that processes channel data with user-defined rules
+	FlagLivePipeline = "livePipeline"
+
+	// FlagDashboardRestore
+	// Keeps deleted dashboards and folders in a recently deleted bin, from where they can be restored
+	FlagDashboardRestore = "dashboardRestore"
+
+	// FlagForecastExpressions
+	// Enables the forecast server side expression that computes a seasonal baseline and outlier bands of time series
+	FlagForecastExpressions = "forecastExpressions"
 )
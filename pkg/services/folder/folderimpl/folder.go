@@ -665,7 +665,12 @@ func (s *Service) deleteChildrenInFolder(ctx context.Context, orgID int64, folde
 
 func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder) error {
 	// nolint:staticcheck
-	deleteCmd := dashboards.DeleteDashboardCommand{OrgID: cmd.OrgID, ID: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules}
+	deleteCmd := dashboards.DeleteDashboardCommand{
+		OrgID:                  cmd.OrgID,
+		ID:                     dashFolder.ID,
+		ForceDeleteFolderRules: cmd.ForceDeleteRules,
+		SoftDelete:             s.features.IsEnabled(ctx, featuremgmt.FlagDashboardRestore),
+	}
 
 	if err := s.dashboardStore.DeleteDashboard(ctx, &deleteCmd); err != nil {
 		return toFolderError(err)
@@ -673,6 +678,84 @@ func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderComm
 	return nil
 }
 
+func (s *Service) Restore(ctx context.Context, cmd *folder.RestoreFolderCommand) (*folder.Folder, error) {
+	logger := s.log.FromContext(ctx)
+	if cmd.SignedInUser == nil {
+		return nil, folder.ErrBadRequest.Errorf("missing signed in user")
+	}
+	if cmd.UID == "" {
+		return nil, folder.ErrBadRequest.Errorf("missing UID")
+	}
+	if cmd.OrgID < 1 {
+		return nil, folder.ErrBadRequest.Errorf("invalid orgID")
+	}
+
+	deleted, err := s.dashboardStore.GetDeletedDashboards(ctx, &dashboards.GetDeletedDashboardsQuery{OrgID: cmd.OrgID, UID: cmd.UID})
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 || !deleted[0].IsFolder {
+		return nil, dashboards.ErrFolderNotFound
+	}
+	parentUID := deleted[0].FolderUID
+	if cmd.ParentUID != nil {
+		parentUID = *cmd.ParentUID
+	}
+
+	// Check that the user is allowed to create the folder where it is restored to
+	var evaluator accesscontrol.Evaluator
+	if parentUID != "" {
+		evaluator = accesscontrol.EvalPermission(dashboards.ActionFoldersWrite, dashboards.ScopeFoldersProvider.GetResourceScopeUID(parentUID))
+	} else {
+		evaluator = accesscontrol.EvalPermission(dashboards.ActionFoldersCreate)
+	}
+	hasAccess, evalErr := s.accessControl.Evaluate(ctx, cmd.SignedInUser, evaluator)
+	if evalErr != nil {
+		return nil, evalErr
+	}
+	if !hasAccess {
+		return nil, dashboards.ErrFolderAccessDenied
+	}
+
+	var restored []*dashboards.Dashboard
+	err = s.db.InTransaction(ctx, func(ctx context.Context) error {
+		restored, err = s.dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{
+			UID:          cmd.UID,
+			OrgID:        cmd.OrgID,
+			FolderUID:    &parentUID,
+			SignedInUser: cmd.SignedInUser,
+		})
+		if err != nil {
+			return toFolderError(err)
+		}
+
+		// the folder table does not keep deleted folders, restored folders are created again
+		// parents first. Descriptions are not kept in the recently deleted bin.
+		for _, d := range restored {
+			if !d.IsFolder {
+				continue
+			}
+			if _, err := s.nestedFolderCreate(ctx, &folder.CreateFolderCommand{
+				UID:       d.UID,
+				OrgID:     d.OrgID,
+				Title:     d.Title,
+				ParentUID: d.FolderUID,
+			}); err != nil {
+				logger.Error("error restoring folder to nested folder store", "uid", d.UID, "error", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f := dashboards.FromDashboard(restored[0])
+	f.ParentUID = restored[0].FolderUID
+	return f, nil
+}
+
 func (s *Service) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
 	if cmd.SignedInUser == nil {
 		return nil, folder.ErrBadRequest.Errorf("missing signed in user")
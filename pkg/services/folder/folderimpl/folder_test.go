@@ -710,6 +710,74 @@ func TestIntegrationNestedFolderService(t *testing.T) {
 	})
 }
 
+func TestIntegrationNestedFolderServiceRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	db := sqlstore.InitTestDB(t)
+	cfg := setting.NewCfg()
+	features := featuremgmt.WithFeatures("nestedFolders", "dashboardRestore")
+	dashStore, err := database.ProvideDashboardStore(db, db.Cfg, features, tagimpl.ProvideService(db), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	folderService := &Service{
+		cfg:                  cfg,
+		log:                  log.New("test-folder-service"),
+		dashboardStore:       dashStore,
+		dashboardFolderStore: ProvideDashboardFolderStore(db),
+		store:                ProvideStore(db, db.Cfg, features),
+		features:             features,
+		bus:                  bus.ProvideBus(tracing.InitializeTracerForTest()),
+		db:                   db,
+		accessControl:        acimpl.ProvideAccessControl(cfg),
+		registry:             make(map[string]folder.RegistryService),
+	}
+
+	origNewGuardian := guardian.New
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true, CanViewValue: true})
+	t.Cleanup(func() {
+		guardian.New = origNewGuardian
+	})
+
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: orgID, Permissions: map[int64]map[string][]string{
+		orgID: {
+			dashboards.ActionFoldersCreate: {},
+			dashboards.ActionFoldersWrite:  {dashboards.ScopeFoldersAll},
+		},
+	}}
+
+	parent, err := folderService.Create(context.Background(), &folder.CreateFolderCommand{OrgID: orgID, Title: "parent", SignedInUser: signedInUser})
+	require.NoError(t, err)
+	subfolder, err := folderService.Create(context.Background(), &folder.CreateFolderCommand{OrgID: orgID, Title: "subfolder", ParentUID: parent.UID, SignedInUser: signedInUser})
+	require.NoError(t, err)
+	dash := insertTestDashboard(t, dashStore, "dashboard in subfolder", orgID, subfolder.ID, subfolder.UID)
+
+	err = folderService.Delete(context.Background(), &folder.DeleteFolderCommand{UID: parent.UID, OrgID: orgID, SignedInUser: signedInUser})
+	require.NoError(t, err)
+	_, err = folderService.Get(context.Background(), &folder.GetFolderQuery{UID: &subfolder.UID, OrgID: orgID, SignedInUser: signedInUser})
+	require.ErrorIs(t, err, dashboards.ErrFolderNotFound)
+
+	t.Run("Should not restore without permission to create folders", func(t *testing.T) {
+		_, err := folderService.Restore(context.Background(), &folder.RestoreFolderCommand{UID: parent.UID, OrgID: orgID, SignedInUser: &user.SignedInUser{UserID: 2, OrgID: orgID}})
+		require.ErrorIs(t, err, dashboards.ErrFolderAccessDenied)
+	})
+
+	t.Run("Should restore folder with its subfolders and dashboards", func(t *testing.T) {
+		restored, err := folderService.Restore(context.Background(), &folder.RestoreFolderCommand{UID: parent.UID, OrgID: orgID, SignedInUser: signedInUser})
+		require.NoError(t, err)
+		require.Equal(t, parent.UID, restored.UID)
+		require.Equal(t, "parent", restored.Title)
+
+		f, err := folderService.Get(context.Background(), &folder.GetFolderQuery{UID: &subfolder.UID, OrgID: orgID, SignedInUser: signedInUser})
+		require.NoError(t, err)
+		require.Equal(t, parent.UID, f.ParentUID)
+
+		d, err := dashStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: dash.UID, OrgID: orgID})
+		require.NoError(t, err)
+		require.Equal(t, subfolder.UID, d.FolderUID)
+	})
+}
+
 func TestNestedFolderServiceFeatureToggle(t *testing.T) {
 	g := guardian.New
 	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
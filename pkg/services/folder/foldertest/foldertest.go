@@ -40,6 +40,10 @@ func (s *FakeService) Delete(ctx context.Context, cmd *folder.DeleteFolderComman
 	return s.ExpectedError
 }
 
+func (s *FakeService) Restore(ctx context.Context, cmd *folder.RestoreFolderCommand) (*folder.Folder, error) {
+	return s.ExpectedFolder, s.ExpectedError
+}
+
 func (s *FakeService) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
 	return s.ExpectedFolder, s.ExpectedError
 }
@@ -125,6 +125,18 @@ type DeleteFolderCommand struct {
 	SignedInUser identity.Requester `json:"-"`
 }
 
+// RestoreFolderCommand captures the information required by the folder service
+// to restore a deleted folder together with the dashboards and subfolders
+// deleted with it.
+type RestoreFolderCommand struct {
+	UID   string `json:"-"`
+	OrgID int64  `json:"-"`
+	// ParentUID is the folder to restore to, defaults to the parent folder at the time of deletion.
+	ParentUID *string `json:"parentUid"`
+
+	SignedInUser identity.Requester `json:"-"`
+}
+
 // GetFolderQuery is used for all folder Get requests. Only one of UID, ID, or
 // Title should be set; if multiple fields are set by the caller the dashboard
 // service will select the field with the most specificity, in order: ID, UID,
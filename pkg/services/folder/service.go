@@ -22,6 +22,8 @@ type Service interface {
 	// a folder's parent folder, use Move.
 	Update(ctx context.Context, cmd *UpdateFolderCommand) (*Folder, error)
 	Delete(ctx context.Context, cmd *DeleteFolderCommand) error
+	// Restore brings back a folder from the recently deleted bin.
+	Restore(ctx context.Context, cmd *RestoreFolderCommand) (*Folder, error)
 	// Move changes a folder's parent folder to the requested new parent.
 	Move(ctx context.Context, cmd *MoveFolderCommand) (*Folder, error)
 	RegisterService(service RegistryService) error
@@ -5,35 +5,73 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/prometheus"
 	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
 )
 
+// Supported input formats.
+const (
+	InputFormatInflux     = "influx"
+	InputFormatPrometheus = "prometheus"
+	InputFormatOTLP       = "otlp"
+)
+
 type Converter struct {
-	telegrafConverterWide         *telegraf.Converter
-	telegrafConverterLabelsColumn *telegraf.Converter
+	telegrafConverterWide           *telegraf.Converter
+	telegrafConverterLabelsColumn   *telegraf.Converter
+	prometheusConverterWide         *prometheus.Converter
+	prometheusConverterLabelsColumn *prometheus.Converter
+	otlpConverterWide               *otlp.Converter
+	otlpConverterLabelsColumn       *otlp.Converter
 }
 
 func NewConverter() *Converter {
+	wideOpts := []telegraf.ConverterOption{
+		telegraf.WithFloat64Numbers(true),
+	}
+	labelsColumnOpts := []telegraf.ConverterOption{
+		telegraf.WithUseLabelsColumn(true),
+		telegraf.WithFloat64Numbers(true),
+	}
 	return &Converter{
-		telegrafConverterWide: telegraf.NewConverter(
-			telegraf.WithFloat64Numbers(true),
-		),
-		telegrafConverterLabelsColumn: telegraf.NewConverter(
-			telegraf.WithUseLabelsColumn(true),
-			telegraf.WithFloat64Numbers(true),
-		),
+		telegrafConverterWide:           telegraf.NewConverter(wideOpts...),
+		telegrafConverterLabelsColumn:   telegraf.NewConverter(labelsColumnOpts...),
+		prometheusConverterWide:         prometheus.NewConverter(wideOpts...),
+		prometheusConverterLabelsColumn: prometheus.NewConverter(labelsColumnOpts...),
+		otlpConverterWide:               otlp.NewConverter(wideOpts...),
+		otlpConverterLabelsColumn:       otlp.NewConverter(labelsColumnOpts...),
 	}
 }
 
 var ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
+var ErrUnsupportedInputFormat = errors.New("unsupported input format")
 
-func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
+func (c *Converter) Convert(data []byte, inputFormat string, frameFormat string) ([]telemetry.FrameWrapper, error) {
 	var converter telemetry.Converter
 	switch frameFormat {
 	case "wide":
-		converter = c.telegrafConverterWide
+		switch inputFormat {
+		case InputFormatInflux:
+			converter = c.telegrafConverterWide
+		case InputFormatPrometheus:
+			converter = c.prometheusConverterWide
+		case InputFormatOTLP:
+			converter = c.otlpConverterWide
+		default:
+			return nil, ErrUnsupportedInputFormat
+		}
 	case "labels_column":
-		converter = c.telegrafConverterLabelsColumn
+		switch inputFormat {
+		case InputFormatInflux:
+			converter = c.telegrafConverterLabelsColumn
+		case InputFormatPrometheus:
+			converter = c.prometheusConverterLabelsColumn
+		case InputFormatOTLP:
+			converter = c.otlpConverterLabelsColumn
+		default:
+			return nil, ErrUnsupportedInputFormat
+		}
 	default:
 		return nil, ErrUnsupportedFrameFormat
 	}
@@ -138,7 +138,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	var managedStreamRunner *managedstream.Runner
 	var redisClient *redis.Client
 	if g.IsHA() && redisHealthy {
-		redisClient := redis.NewClient(&redis.Options{
+		redisClient = redis.NewClient(&redis.Options{
 			Addr:     g.Cfg.LiveHAEngineAddress,
 			Password: g.Cfg.LiveHAEnginePassword,
 		})
@@ -149,22 +149,64 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		}
 	}
 
+	historyWindow, historyMaxFrames := cfg.LiveManagedStreamHistoryWindow, cfg.LiveManagedStreamHistoryMaxFrames
 	if redisClient != nil {
+		var frameHistory managedstream.FrameHistory
+		if historyWindow > 0 {
+			frameHistory = managedstream.NewRedisFrameHistory(redisClient, historyWindow, historyMaxFrames)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewRedisFrameCache(redisClient),
+			frameHistory,
 		)
 	} else {
+		var frameHistory managedstream.FrameHistory
+		if historyWindow > 0 {
+			frameHistory = managedstream.NewMemoryFrameHistory(historyWindow, historyMaxFrames)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewMemoryFrameCache(),
+			frameHistory,
 		)
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	if toggles.IsEnabledGlobally(featuremgmt.FlagLivePipeline) {
+		var storage pipeline.Storage
+		if cfg.LivePipelineStorage == "file" {
+			storage = &pipeline.FileStorage{
+				DataPath:       cfg.DataPath,
+				SecretsService: g.SecretsService,
+			}
+		} else {
+			storage = &pipeline.SQLStorage{
+				SQLStore:       g.SQLStore,
+				SecretsService: g.SecretsService,
+				OnChange:       g.notifyPipelineRulesChanged,
+			}
+		}
+		g.pipelineStorage = storage
+		builder := &pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			Storage:              storage,
+			ChannelHandlerGetter: g,
+			SecretsService:       g.SecretsService,
+		}
+		g.pipelineRuleCache = pipeline.NewCacheSegmentedTree(builder)
+		g.Pipeline, err = pipeline.New(g.pipelineRuleCache)
+		if err != nil {
+			return nil, err
+		}
+		node.OnNotification(g.handleNotification)
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -327,6 +369,40 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	return g, nil
 }
 
+const notificationOpPipelineRulesChanged = "pipeline_rules_changed"
+
+type pipelineRulesChangedNotification struct {
+	OrgID int64 `json:"orgId"`
+}
+
+// notifyPipelineRulesChanged lets all Grafana instances (including this one)
+// know that pipeline entities of an organization were modified. In HA setup
+// notifications are delivered over the HA engine.
+func (g *GrafanaLive) notifyPipelineRulesChanged(orgID int64) {
+	data, err := json.Marshal(pipelineRulesChangedNotification{OrgID: orgID})
+	if err != nil {
+		logger.Error("Error marshaling pipeline rules notification", "error", err)
+		return
+	}
+	if err := g.node.Notify(notificationOpPipelineRulesChanged, data, ""); err != nil {
+		logger.Error("Error sending pipeline rules notification", "error", err, "orgId", orgID)
+	}
+}
+
+func (g *GrafanaLive) handleNotification(e centrifuge.NotificationEvent) {
+	switch e.Op {
+	case notificationOpPipelineRulesChanged:
+		var n pipelineRulesChangedNotification
+		if err := json.Unmarshal(e.Data, &n); err != nil {
+			logger.Error("Error unmarshaling pipeline rules notification", "error", err)
+			return
+		}
+		if err := g.pipelineRuleCache.Reload(n.OrgID); err != nil {
+			logger.Error("Error reloading pipeline rules", "error", err, "orgId", n.OrgID)
+		}
+	}
+}
+
 func setupRedisLiveEngine(g *GrafanaLive, node *centrifuge.Node) error {
 	redisAddress := g.Cfg.LiveHAEngineAddress
 	redisPassword := g.Cfg.LiveHAEnginePassword
@@ -399,6 +475,7 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	pipelineRuleCache   *pipeline.CacheSegmentedTree
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -0,0 +1,63 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FrameHistory keeps recent frames pushed to managed stream channels, so
+// that new subscribers can receive them on subscribe.
+type FrameHistory interface {
+	// Add saves full JSON frame pushed to a channel in org at time t.
+	Add(ctx context.Context, orgID int64, channel string, frameJSON []byte, t time.Time) error
+	// Get returns frames pushed to a channel in org within the history window, oldest first.
+	Get(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error)
+}
+
+// mergeFrames combines history frames into a single frame with the schema
+// of the most recent one. Older frames with a different schema are skipped.
+func mergeFrames(frames []json.RawMessage) (json.RawMessage, error) {
+	decoded := make([]*data.Frame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		var frame data.Frame
+		if err := json.Unmarshal(frames[i], &frame); err != nil {
+			return nil, fmt.Errorf("error unmarshaling history frame: %w", err)
+		}
+		if len(decoded) > 0 && !sameSchema(decoded[0], &frame) {
+			break
+		}
+		decoded = append(decoded, &frame)
+	}
+	if len(decoded) == 0 {
+		return nil, nil
+	}
+
+	merged := decoded[0].EmptyCopy()
+	for i := len(decoded) - 1; i >= 0; i-- {
+		for fieldIdx, field := range decoded[i].Fields {
+			for rowIdx := 0; rowIdx < field.Len(); rowIdx++ {
+				merged.Fields[fieldIdx].Append(field.At(rowIdx))
+			}
+		}
+	}
+	return data.FrameToJSON(merged, data.IncludeAll)
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+		if a.Fields[i].Labels.String() != b.Fields[i].Labels.String() {
+			return false
+		}
+	}
+	return true
+}
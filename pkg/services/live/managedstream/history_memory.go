@@ -0,0 +1,90 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// MemoryFrameHistory keeps recent frames of each channel in a fixed size
+// ring buffer. Frames older than the window are dropped.
+type MemoryFrameHistory struct {
+	mu        sync.Mutex
+	window    time.Duration
+	maxFrames int
+	rings     map[int64]map[string]*frameRing
+}
+
+// NewMemoryFrameHistory creates MemoryFrameHistory keeping at most maxFrames
+// frames of each channel pushed within the window.
+func NewMemoryFrameHistory(window time.Duration, maxFrames int) *MemoryFrameHistory {
+	return &MemoryFrameHistory{
+		window:    window,
+		maxFrames: maxFrames,
+		rings:     map[int64]map[string]*frameRing{},
+	}
+}
+
+type historyEntry struct {
+	time  time.Time
+	frame []byte
+}
+
+type frameRing struct {
+	entries []historyEntry
+	// start is an index of the oldest entry.
+	start int
+	size  int
+}
+
+func (r *frameRing) add(e historyEntry) {
+	if r.size < len(r.entries) {
+		r.entries[(r.start+r.size)%len(r.entries)] = e
+		r.size++
+		return
+	}
+	// Full, overwrite the oldest entry.
+	r.entries[r.start] = e
+	r.start = (r.start + 1) % len(r.entries)
+}
+
+// expire drops entries older than the given time.
+func (r *frameRing) expire(before time.Time) {
+	for r.size > 0 && r.entries[r.start].time.Before(before) {
+		r.entries[r.start] = historyEntry{}
+		r.start = (r.start + 1) % len(r.entries)
+		r.size--
+	}
+}
+
+func (c *MemoryFrameHistory) Add(_ context.Context, orgID int64, channel string, frameJSON []byte, t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.rings[orgID]; !ok {
+		c.rings[orgID] = map[string]*frameRing{}
+	}
+	ring, ok := c.rings[orgID][channel]
+	if !ok {
+		ring = &frameRing{entries: make([]historyEntry, c.maxFrames)}
+		c.rings[orgID][channel] = ring
+	}
+	ring.expire(t.Add(-c.window))
+	ring.add(historyEntry{time: t, frame: frameJSON})
+	return nil
+}
+
+func (c *MemoryFrameHistory) Get(_ context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ring, ok := c.rings[orgID][channel]
+	if !ok {
+		return nil, nil
+	}
+	ring.expire(time.Now().Add(-c.window))
+	frames := make([]json.RawMessage, 0, ring.size)
+	for i := 0; i < ring.size; i++ {
+		frames = append(frames, ring.entries[(ring.start+i)%len(ring.entries)].frame)
+	}
+	return frames, nil
+}
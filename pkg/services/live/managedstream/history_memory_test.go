@@ -0,0 +1,53 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFrameHistory(t *testing.T, c FrameHistory) {
+	ctx := context.Background()
+	now := time.Now()
+
+	frames, err := c.Get(ctx, 1, "test")
+	require.NoError(t, err)
+	require.Empty(t, frames)
+
+	// Outside of the window.
+	require.NoError(t, c.Add(ctx, 1, "test", []byte(`{"v":0}`), now.Add(-2*time.Minute)))
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, c.Add(ctx, 1, "test", []byte(fmt.Sprintf(`{"v":%d}`, i)), now))
+	}
+	require.NoError(t, c.Add(ctx, 2, "test", []byte(`{"v":5}`), now))
+
+	// Only last 3 frames are kept.
+	frames, err = c.Get(ctx, 1, "test")
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"v":2}`),
+		json.RawMessage(`{"v":3}`),
+		json.RawMessage(`{"v":4}`),
+	}, frames)
+
+	frames, err = c.Get(ctx, 2, "test")
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{json.RawMessage(`{"v":5}`)}, frames)
+}
+
+func TestMemoryFrameHistory(t *testing.T) {
+	c := NewMemoryFrameHistory(time.Minute, 3)
+	testFrameHistory(t, c)
+
+	t.Run("expires frames outside of the window", func(t *testing.T) {
+		c := NewMemoryFrameHistory(time.Minute, 3)
+		require.NoError(t, c.Add(context.Background(), 1, "test", []byte(`{"v":1}`), time.Now().Add(-2*time.Minute)))
+		frames, err := c.Get(context.Background(), 1, "test")
+		require.NoError(t, err)
+		require.Empty(t, frames)
+	})
+}
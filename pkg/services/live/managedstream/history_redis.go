@@ -0,0 +1,80 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// RedisFrameHistory keeps recent frames of each channel in a capped Redis
+// list, so that history is shared between Grafana instances in HA setup.
+type RedisFrameHistory struct {
+	redisClient *redis.Client
+	window      time.Duration
+	maxFrames   int
+}
+
+// NewRedisFrameHistory creates RedisFrameHistory keeping at most maxFrames
+// frames of each channel pushed within the window.
+func NewRedisFrameHistory(redisClient *redis.Client, window time.Duration, maxFrames int) *RedisFrameHistory {
+	return &RedisFrameHistory{
+		redisClient: redisClient,
+		window:      window,
+		maxFrames:   maxFrames,
+	}
+}
+
+type redisHistoryEntry struct {
+	// Time is a Unix time in milliseconds when frame was pushed.
+	Time  int64           `json:"t"`
+	Frame json.RawMessage `json:"frame"`
+}
+
+func (c *RedisFrameHistory) Add(ctx context.Context, orgID int64, channel string, frameJSON []byte, t time.Time) error {
+	entry, err := json.Marshal(redisHistoryEntry{Time: t.UnixMilli(), Frame: frameJSON})
+	if err != nil {
+		return err
+	}
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+
+	pipe := c.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+
+	pipe.RPush(ctx, key, entry)
+	pipe.LTrim(ctx, key, int64(-c.maxFrames), -1)
+	// The whole list expires when nothing is pushed to a channel during the window.
+	pipe.PExpire(ctx, key, c.window)
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisFrameHistory) Get(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	result, err := c.redisClient.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	minTime := time.Now().Add(-c.window).UnixMilli()
+	frames := make([]json.RawMessage, 0, len(result))
+	for _, item := range result {
+		var entry redisHistoryEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			return nil, fmt.Errorf("error unmarshaling history entry: %w", err)
+		}
+		if entry.Time < minTime {
+			continue
+		}
+		frames = append(frames, entry.Frame)
+	}
+	return frames, nil
+}
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
@@ -0,0 +1,38 @@
+package managedstream
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationRedisFrameHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	u, ok := os.LookupEnv("REDIS_URL")
+	if !ok || u == "" {
+		t.Skip("No redis URL supplied")
+	}
+
+	addr := u
+	db := 0
+	parsed, err := redis.ParseURL(u)
+	if err == nil {
+		addr = parsed.Addr
+		db = parsed.DB
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: addr,
+		DB:   db,
+	})
+	require.NoError(t, redisClient.Del(context.Background(), getHistoryKey("1/test"), getHistoryKey("2/test")).Err())
+	c := NewRedisFrameHistory(redisClient, time.Minute, 3)
+	testFrameHistory(t, c)
+}
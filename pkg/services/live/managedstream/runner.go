@@ -42,19 +42,22 @@ type Runner struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 }
 
 type LocalPublisher interface {
 	PublishLocal(channel string, data []byte) error
 }
 
-// NewRunner creates new Runner.
-func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache) *Runner {
+// NewRunner creates new Runner. frameHistory is optional, when set
+// subscribers receive recent frames of a channel on subscribe.
+func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache, frameHistory FrameHistory) *Runner {
 	return &Runner{
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		streams:        map[int64]map[string]*NamespaceStream{},
 		frameCache:     frameCache,
+		frameHistory:   frameHistory,
 	}
 }
 
@@ -125,7 +128,7 @@ func (r *Runner) GetOrCreateStream(orgID int64, scope string, namespace string)
 	prefix := scope + "/" + namespace
 	s, ok := r.streams[orgID][prefix]
 	if !ok {
-		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache)
+		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache, r.frameHistory)
 		r.streams[orgID][prefix] = s
 	}
 	return s, nil
@@ -139,6 +142,7 @@ type NamespaceStream struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 	rateMu         sync.RWMutex
 	rates          map[string][60]rateEntry
 }
@@ -156,7 +160,7 @@ type ManagedChannel struct {
 }
 
 // NewNamespaceStream creates new NamespaceStream.
-func NewNamespaceStream(orgID int64, scope string, namespace string, publisher model.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache) *NamespaceStream {
+func NewNamespaceStream(orgID int64, scope string, namespace string, publisher model.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache, frameHistory FrameHistory) *NamespaceStream {
 	return &NamespaceStream{
 		orgID:          orgID,
 		scope:          scope,
@@ -164,12 +168,13 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		frameCache:     schemaUpdater,
+		frameHistory:   frameHistory,
 		rates:          map[string][60]rateEntry{},
 	}
 }
 
 // Push sends frame to the stream and saves it for later retrieval by subscribers.
-// * Saves the entire frame to cache.
+// * Saves the entire frame to cache and history.
 // * If schema has been changed sends entire frame to channel, otherwise only data.
 func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Frame) error {
 	jsonFrameCache, err := data.FrameToJSONCache(frame)
@@ -186,6 +191,14 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 		return err
 	}
 
+	if s.frameHistory != nil {
+		// History is only replayed to new subscribers, the frame is still delivered to current ones.
+		err = s.frameHistory.Add(ctx, s.orgID, channel, jsonFrameCache.Bytes(data.IncludeAll), time.Now())
+		if err != nil {
+			logger.Error("Error adding frame to managed stream history", "error", err, "channel", channel)
+		}
+	}
+
 	// When the schema has not changed, just send the data.
 	include := data.IncludeDataOnly
 	if isUpdated {
@@ -241,6 +254,20 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (model.ChannelHandler, err
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := model.SubscribeReply{}
+	if s.frameHistory != nil {
+		frames, err := s.frameHistory.Get(ctx, u.GetOrgID(), e.Channel)
+		if err != nil {
+			return reply, 0, err
+		}
+		if len(frames) > 0 {
+			frameJSON, err := mergeFrames(frames)
+			if err != nil {
+				return reply, 0, err
+			}
+			reply.Data = frameJSON
+			return reply, backend.SubscribeStreamStatusOK, nil
+		}
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.GetOrgID(), e.Channel)
 	if err != nil {
 		return reply, 0, err
@@ -2,11 +2,17 @@ package managedstream
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type testPublisher struct {
@@ -19,13 +25,13 @@ func (p *testPublisher) publish(_ int64, _ string, _ []byte) error {
 
 func TestNewManagedStream(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(), nil)
 	require.NotNil(t, c)
 }
 
 func TestManagedStreamMinuteRate(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(), nil)
 	require.NotNil(t, c)
 
 	c.incRate("test1", time.Now().Unix())
@@ -48,7 +54,7 @@ func TestManagedStreamMinuteRate(t *testing.T) {
 func TestGetManagedStreams(t *testing.T) {
 	publisher := &testPublisher{t: t}
 	frameCache := NewMemoryFrameCache()
-	runner := NewRunner(publisher.publish, nil, frameCache)
+	runner := NewRunner(publisher.publish, nil, frameCache, nil)
 	s1, err := runner.GetOrCreateStream(1, "stream", "test1")
 	require.NoError(t, err)
 	s2, err := runner.GetOrCreateStream(1, "stream", "test2")
@@ -83,3 +89,50 @@ func TestGetManagedStreams(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, managedChannels, 7) // Not affected by other org.
 }
+
+func TestNamespaceStream_OnSubscribeHistory(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	s := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(), NewMemoryFrameHistory(time.Minute, 10))
+
+	push := func(frame *data.Frame) {
+		t.Helper()
+		require.NoError(t, s.Push(context.Background(), "cpu", frame))
+	}
+	// Frame with another schema is not included.
+	push(data.NewFrame("cpu", data.NewField("value", nil, []string{"old"})))
+	push(data.NewFrame("cpu", data.NewField("value", nil, []float64{1, 2})))
+	push(data.NewFrame("cpu", data.NewField("value", nil, []float64{3})))
+
+	reply, status, err := s.OnSubscribe(context.Background(), &user.SignedInUser{OrgID: 1}, model.SubscribeEvent{Channel: "stream/a/cpu"})
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusOK, status)
+
+	var frame data.Frame
+	require.NoError(t, json.Unmarshal(reply.Data, &frame))
+	require.Equal(t, 3, frame.Rows())
+	for i, v := range []float64{1, 2, 3} {
+		require.Equal(t, v, frame.Fields[0].At(i))
+	}
+}
+
+type failingFrameHistory struct{}
+
+func (h failingFrameHistory) Add(context.Context, int64, string, []byte, time.Time) error {
+	return errors.New("history unavailable")
+}
+
+func (h failingFrameHistory) Get(context.Context, int64, string) ([]json.RawMessage, error) {
+	return nil, nil
+}
+
+func TestNamespaceStream_PushHistoryError(t *testing.T) {
+	published := 0
+	publish := func(_ int64, _ string, _ []byte) error {
+		published++
+		return nil
+	}
+	s := NewNamespaceStream(1, "stream", "a", publish, nil, NewMemoryFrameCache(), failingFrameHistory{})
+
+	require.NoError(t, s.Push(context.Background(), "cpu", data.NewFrame("cpu", data.NewField("value", nil, []float64{1}))))
+	require.Equal(t, 1, published)
+}
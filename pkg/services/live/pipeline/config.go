@@ -35,11 +35,13 @@ type ChannelRule struct {
 }
 
 type ConverterConfig struct {
-	Type                      string                     `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
-	AutoJsonConverterConfig   *AutoJsonConverterConfig   `json:"jsonAuto,omitempty"`
-	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
-	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
-	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	Type                          string                         `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
+	AutoJsonConverterConfig       *AutoJsonConverterConfig       `json:"jsonAuto,omitempty"`
+	ExactJsonConverterConfig      *ExactJsonConverterConfig      `json:"jsonExact,omitempty"`
+	AutoInfluxConverterConfig     *AutoInfluxConverterConfig     `json:"influxAuto,omitempty"`
+	AutoPrometheusConverterConfig *AutoPrometheusConverterConfig `json:"prometheusAuto,omitempty"`
+	AutoOTLPConverterConfig       *AutoOTLPConverterConfig       `json:"otlpAuto,omitempty"`
+	JsonFrameConverterConfig      *JsonFrameConverterConfig      `json:"jsonFrame,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -150,6 +152,16 @@ type AutoInfluxConverterConfig struct {
 	FrameFormat string `json:"frameFormat"`
 }
 
+// AutoPrometheusConverterConfig ...
+type AutoPrometheusConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
+// AutoOTLPConverterConfig ...
+type AutoOTLPConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
 type JsonFrameConverterConfig struct{}
 
 type ManagedStreamOutputConfig struct{}
@@ -26,7 +26,7 @@ func (c *AutoInfluxConverter) Type() string {
 }
 
 func (c *AutoInfluxConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
-	frameWrappers, err := c.converter.Convert(body, c.config.FrameFormat)
+	frameWrappers, err := c.converter.Convert(body, convert.InputFormatInflux, c.config.FrameFormat)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/convert"
+)
+
+// AutoOTLPConverter decodes OTLP/HTTP metrics (protobuf or JSON) input and transforms it
+// to several ChannelFrame objects where Channel is constructed from original
+// channel + / + <metric_name>.
+type AutoOTLPConverter struct {
+	config    AutoOTLPConverterConfig
+	converter *convert.Converter
+}
+
+// NewAutoOTLPConverter creates new AutoOTLPConverter.
+func NewAutoOTLPConverter(config AutoOTLPConverterConfig) *AutoOTLPConverter {
+	return &AutoOTLPConverter{config: config, converter: convert.NewConverter()}
+}
+
+const ConverterTypeOTLPAuto = "otlpAuto"
+
+func (c *AutoOTLPConverter) Type() string {
+	return ConverterTypeOTLPAuto
+}
+
+func (c *AutoOTLPConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	frameWrappers, err := c.converter.Convert(body, convert.InputFormatOTLP, c.config.FrameFormat)
+	if err != nil {
+		return nil, err
+	}
+	channelFrames := make([]*ChannelFrame, 0, len(frameWrappers))
+	for _, fw := range frameWrappers {
+		channelFrames = append(channelFrames, &ChannelFrame{
+			Channel: vars.Channel + "/" + fw.Key(),
+			Frame:   fw.Frame(),
+		})
+	}
+	return channelFrames, nil
+}
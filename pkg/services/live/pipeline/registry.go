@@ -62,6 +62,20 @@ var ConvertersRegistry = []EntityInfo{
 			FrameFormat: "labels_column",
 		},
 	},
+	{
+		Type:        ConverterTypePrometheusAuto,
+		Description: "accept prometheus text exposition format",
+		Example: AutoPrometheusConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
+	{
+		Type:        ConverterTypeOTLPAuto,
+		Description: "accept OTLP/HTTP metrics in protobuf or JSON encoding",
+		Example: AutoOTLPConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
 	{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
@@ -69,6 +69,16 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheusAuto:
+		if config.AutoPrometheusConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAutoPrometheusConverter(*config.AutoPrometheusConverterConfig), nil
+	case ConverterTypeOTLPAuto:
+		if config.AutoOTLPConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAutoOTLPConverter(*config.AutoOTLPConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
@@ -59,6 +59,18 @@ func (s *CacheSegmentedTree) fillOrg(orgID int64) error {
 	return nil
 }
 
+// Reload rebuilds the cached rules of an organization after they were changed.
+// Organizations which were not requested yet are loaded lazily by Get.
+func (s *CacheSegmentedTree) Reload(orgID int64) error {
+	s.radixMu.RLock()
+	_, ok := s.radix[orgID]
+	s.radixMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return s.fillOrg(orgID)
+}
+
 func (s *CacheSegmentedTree) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	s.radixMu.RLock()
 	_, ok := s.radix[orgID]
@@ -0,0 +1,344 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules and write configs in the Grafana database,
+// so that every Grafana instance sharing the database sees the same entities.
+// Unlike FileStorage entities are strictly isolated by organization.
+type SQLStorage struct {
+	SQLStore       db.DB
+	SecretsService secrets.Service
+	// OnChange is called after channel rules or write configs of an
+	// organization were modified, it may be nil.
+	OnChange func(orgID int64)
+}
+
+type liveChannelRule struct {
+	Id       int64
+	OrgId    int64
+	Pattern  string
+	Settings string
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (liveChannelRule) TableName() string {
+	return "live_channel_rule"
+}
+
+func (r liveChannelRule) toChannelRule() (ChannelRule, error) {
+	rule := ChannelRule{OrgId: r.OrgId, Pattern: r.Pattern}
+	if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+		return ChannelRule{}, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", r.Pattern, err)
+	}
+	return rule, nil
+}
+
+type liveWriteConfig struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Settings       string
+	SecureSettings string
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (liveWriteConfig) TableName() string {
+	return "live_write_config"
+}
+
+func (c liveWriteConfig) toWriteConfig() (WriteConfig, error) {
+	writeConfig := WriteConfig{OrgId: c.OrgId, UID: c.Uid}
+	if err := json.Unmarshal([]byte(c.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", c.Uid, err)
+	}
+	if c.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(c.SecureSettings), &writeConfig.SecureSettings); err != nil {
+			return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", c.Uid, err)
+		}
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) notify(orgID int64) {
+	if s.OnChange != nil {
+		s.OnChange(orgID)
+	}
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var rows []liveWriteConfig
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	writeConfigs := make([]WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		writeConfig, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		writeConfigs = append(writeConfigs, writeConfig)
+	}
+	return writeConfigs, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var row liveWriteConfig
+	var exists bool
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !exists {
+		return WriteConfig{}, false, nil
+	}
+	writeConfig, err := row.toWriteConfig()
+	return writeConfig, err == nil, err
+}
+
+func (s *SQLStorage) newWriteConfig(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (WriteConfig, liveWriteConfig, error) {
+	encrypted, err := s.SecretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encrypted,
+	}
+	if ok, reason := writeConfig.Valid(); !ok {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+
+	settingsJSON, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("can't marshal write config settings: %w", err)
+	}
+	secureSettingsJSON, err := json.Marshal(writeConfig.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("can't marshal write config secure settings: %w", err)
+	}
+	return writeConfig, liveWriteConfig{
+		OrgId:          orgID,
+		Uid:            uid,
+		Settings:       string(settingsJSON),
+		SecureSettings: string(secureSettingsJSON),
+	}, nil
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	writeConfig, row, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Exist(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("backend already exists in org: %s", cmd.UID)
+		}
+		row.Created = time.Now()
+		row.Updated = row.Created
+		_, err = sess.Insert(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	s.notify(orgID)
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	writeConfig, row, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	// Affected rows can't be used to detect missing write configs, MySQL
+	// doesn't count rows which were not changed.
+	var exists bool
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Exist(&liveWriteConfig{})
+		if err != nil || !exists {
+			return err
+		}
+		row.Updated = time.Now()
+		_, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).
+			Cols("settings", "secure_settings", "updated").
+			Update(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	if !exists {
+		return s.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd(cmd))
+	}
+	s.notify(orgID)
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	var deleted int64
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&liveWriteConfig{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("write config not found")
+	}
+	s.notify(orgID)
+	return nil
+}
+
+func listChannelRules(sess *db.Session, orgID int64) ([]ChannelRule, error) {
+	var rows []liveChannelRule
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows); err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := row.toChannelRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rules []ChannelRule
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		rules, err = listChannelRules(sess, orgID)
+		return err
+	})
+	return rules, err
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	if ok, reason := rule.Valid(); !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return rule, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rules, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		for _, existingRule := range rules {
+			if existingRule.Pattern == rule.Pattern {
+				return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+			}
+		}
+		if ok, reason := checkRulesValid(orgID, append(rules, rule)); !ok {
+			return errors.New(reason)
+		}
+		now := time.Now()
+		_, err = sess.Insert(&liveChannelRule{
+			OrgId:    orgID,
+			Pattern:  rule.Pattern,
+			Settings: string(settings),
+			Created:  now,
+			Updated:  now,
+		})
+		return err
+	})
+	if err != nil {
+		return rule, err
+	}
+	s.notify(orgID)
+	return rule, nil
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	if ok, reason := rule.Valid(); !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return rule, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+
+	var exists bool
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err = sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).Exist(&liveChannelRule{})
+		if err != nil || !exists {
+			return err
+		}
+		_, err = sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).
+			Cols("settings", "updated").
+			Update(&liveChannelRule{Settings: string(settings), Updated: time.Now()})
+		return err
+	})
+	if err != nil {
+		return rule, err
+	}
+	if !exists {
+		return s.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+	}
+	s.notify(orgID)
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	var deleted int64
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&liveChannelRule{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("rule not found")
+	}
+	s.notify(orgID)
+	return nil
+}
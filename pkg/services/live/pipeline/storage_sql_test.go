@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func setupTestSQLStorage(t *testing.T) (*SQLStorage, *[]int64) {
+	t.Helper()
+	var changes []int64
+	return &SQLStorage{
+		SQLStore:       db.InitTestDB(t),
+		SecretsService: fakes.NewFakeSecretsService(),
+		OnChange: func(orgID int64) {
+			changes = append(changes, orgID)
+		},
+	}, &changes
+}
+
+func TestIntegrationSQLStorage_ChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, changes := setupTestSQLStorage(t)
+
+	rules, err := s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	settings := ChannelRuleSettings{Converter: &ConverterConfig{Type: ConverterTypeJsonAuto}}
+	rule, err := s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:metric", Settings: settings})
+	require.NoError(t, err)
+	require.Equal(t, ChannelRule{OrgId: 1, Pattern: "stream/telegraf/:metric", Settings: settings}, rule)
+
+	_, err = s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:metric"})
+	require.ErrorContains(t, err, "pattern already exists in org")
+
+	_, err = s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:name"})
+	require.Error(t, err, "conflicting pattern must not be saved")
+
+	_, err = s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf", Settings: ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: "unknown"},
+	}})
+	require.ErrorContains(t, err, "invalid channel rule")
+
+	// Same pattern in another org.
+	_, err = s.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:metric"})
+	require.NoError(t, err)
+
+	updatedSettings := ChannelRuleSettings{Converter: &ConverterConfig{Type: ConverterTypeInfluxAuto}}
+	_, err = s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/telegraf/:metric", Settings: updatedSettings})
+	require.NoError(t, err)
+	// Update creates missing rules.
+	_, err = s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/json"})
+	require.NoError(t, err)
+
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []ChannelRule{
+		{OrgId: 1, Pattern: "stream/json"},
+		{OrgId: 1, Pattern: "stream/telegraf/:metric", Settings: updatedSettings},
+	}, rules)
+
+	rules, err = s.ListChannelRules(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, []ChannelRule{{OrgId: 2, Pattern: "stream/telegraf/:metric"}}, rules)
+
+	require.NoError(t, s.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/json"}))
+	require.ErrorContains(t, s.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/json"}), "rule not found")
+
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	require.Equal(t, []int64{1, 2, 1, 1, 1}, *changes)
+}
+
+func TestIntegrationSQLStorage_WriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, changes := setupTestSQLStorage(t)
+
+	writeConfig, err := s.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		Settings:       WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, writeConfig.UID)
+
+	_, err = s.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		UID:      writeConfig.UID,
+		Settings: WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+	})
+	require.ErrorContains(t, err, "backend already exists in org")
+
+	_, err = s.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "no-endpoint"})
+	require.ErrorContains(t, err, "endpoint required")
+
+	stored, ok, err := s.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: writeConfig.UID})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, writeConfig, stored)
+	decrypted, err := s.SecretsService.DecryptJsonData(ctx, stored.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"basicAuthPassword": "secret"}, decrypted)
+
+	// Write configs are not visible in other orgs.
+	_, ok, err = s.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: writeConfig.UID})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.ErrorContains(t, s.DeleteWriteConfig(ctx, 2, WriteConfigDeleteCmd{UID: writeConfig.UID}), "write config not found")
+
+	updated, err := s.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+		UID:      writeConfig.UID,
+		Settings: WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+	})
+	require.NoError(t, err)
+
+	writeConfigs, err := s.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []WriteConfig{updated}, writeConfigs)
+
+	require.NoError(t, s.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: writeConfig.UID}))
+	writeConfigs, err = s.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, writeConfigs)
+
+	require.Equal(t, []int64{1, 1, 1}, *changes)
+}
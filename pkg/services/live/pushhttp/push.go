@@ -1,10 +1,13 @@
 package pushhttp
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	liveDto "github.com/grafana/grafana-plugin-sdk-go/live"
 
@@ -58,11 +61,16 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 	// TODO Grafana 8: decide which formats to use or keep all.
 	urlValues := ctx.Req.URL.Query()
 	frameFormat := pushurl.FrameFormatFromValues(urlValues)
+	inputFormat := pushurl.InputFormatFromRequest(ctx.Req)
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := readBody(ctx.Req)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
-		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		if errors.Is(err, errInvalidGzip) {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		}
 		return
 	}
 	logger.Debug("Live Push request",
@@ -70,12 +78,13 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 		"streamId", streamID,
 		"bodyLength", len(body),
 		"frameFormat", frameFormat,
+		"inputFormat", inputFormat,
 	)
 
-	metricFrames, err := g.converter.Convert(body, frameFormat)
+	metricFrames, err := g.converter.Convert(body, inputFormat, frameFormat)
 	if err != nil {
-		logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat)
-		if errors.Is(err, convert.ErrUnsupportedFrameFormat) {
+		logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat, "inputFormat", inputFormat)
+		if errors.Is(err, convert.ErrUnsupportedFrameFormat) || errors.Is(err, convert.ErrUnsupportedInputFormat) {
 			ctx.Resp.WriteHeader(http.StatusBadRequest)
 		} else {
 			ctx.Resp.WriteHeader(http.StatusInternalServerError)
@@ -98,13 +107,33 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
 
+var errInvalidGzip = errors.New("invalid gzip body")
+
+// readBody reads the body of the request, which exporters such as the OpenTelemetry
+// collector and Telegraf can compress with gzip.
+func readBody(req *http.Request) ([]byte, error) {
+	if !strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		return io.ReadAll(req.Body)
+	}
+	r, err := gzip.NewReader(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidGzip, err)
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
 func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 	channelID := web.Params(ctx.Req)["*"]
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := readBody(ctx.Req)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
-		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		if errors.Is(err, errInvalidGzip) {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		}
 		return
 	}
 	logger.Debug("Live channel push request",
@@ -0,0 +1,44 @@
+package pushhttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBody(t *testing.T) {
+	const line = "cpu,host=a usage=1 1\n"
+
+	t.Run("reads plain body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/live/push/test", strings.NewReader(line))
+		body, err := readBody(req)
+		require.NoError(t, err)
+		require.Equal(t, line, string(body))
+	})
+
+	t.Run("decompresses gzip body", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/live/push/test", &buf)
+		req.Header.Set("Content-Encoding", "gzip")
+		body, err := readBody(req)
+		require.NoError(t, err)
+		require.Equal(t, line, string(body))
+	})
+
+	t.Run("rejects invalid gzip body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/live/push/test", strings.NewReader(line))
+		req.Header.Set("Content-Encoding", "gzip")
+		_, err := readBody(req)
+		require.ErrorIs(t, err, errInvalidGzip)
+	})
+}
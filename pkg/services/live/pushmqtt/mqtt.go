@@ -0,0 +1,236 @@
+package pushmqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	logger = log.New("live.push_mqtt")
+)
+
+// Config is the configuration of the MQTT input, read from the [live.mqtt] section.
+type Config struct {
+	Enabled   bool
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	QoS       byte
+	// OrgID is the organization of the channels the messages are processed in.
+	OrgID  int64
+	Routes []Route
+
+	TLSCACertPath     string
+	TLSClientCertPath string
+	TLSClientKeyPath  string
+	TLSSkipVerify     bool
+}
+
+func ReadConfig(cfg *setting.Cfg) (Config, error) {
+	section := cfg.SectionWithEnvOverrides("live.mqtt")
+	c := Config{
+		Enabled:           section.Key("enabled").MustBool(false),
+		BrokerURL:         section.Key("broker_url").MustString("tcp://127.0.0.1:1883"),
+		ClientID:          section.Key("client_id").MustString(""),
+		Username:          section.Key("username").MustString(""),
+		Password:          section.Key("password").MustString(""),
+		OrgID:             section.Key("org_id").MustInt64(1),
+		TLSCACertPath:     section.Key("tls_ca_cert_path").MustString(""),
+		TLSClientCertPath: section.Key("tls_client_cert_path").MustString(""),
+		TLSClientKeyPath:  section.Key("tls_client_key_path").MustString(""),
+		TLSSkipVerify:     section.Key("tls_skip_verify").MustBool(false),
+	}
+	if !c.Enabled {
+		return c, nil
+	}
+
+	qos := section.Key("qos").MustInt(0)
+	if qos < 0 || qos > 2 {
+		return c, fmt.Errorf("unexpected value %d for [live.mqtt] qos, must be 0, 1 or 2", qos)
+	}
+	c.QoS = byte(qos)
+
+	routes, err := ParseRoutes(section.Key("routes").MustString(""))
+	if err != nil {
+		return c, fmt.Errorf("invalid [live.mqtt] routes: %w", err)
+	}
+	if len(routes) == 0 {
+		return c, errors.New("[live.mqtt] routes must be set when the MQTT input is enabled")
+	}
+	c.Routes = routes
+	return c, nil
+}
+
+// Processor processes the data received for a channel.
+type Processor interface {
+	ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error)
+}
+
+func ProvideService(cfg *setting.Cfg, live *live.GrafanaLive) (*Input, error) {
+	c, err := ReadConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	input := &Input{config: c}
+	if !c.Enabled {
+		return input, nil
+	}
+	if live.Pipeline == nil {
+		logger.Warn("MQTT input is enabled but Live pipeline is not, enable the livePipeline feature toggle")
+		input.config.Enabled = false
+		return input, nil
+	}
+
+	logger.Info("Live MQTT input initialization", "broker", c.BrokerURL)
+	input.router, err = NewRouter(c.Routes)
+	if err != nil {
+		return nil, err
+	}
+	input.processor = live.Pipeline
+	return input, nil
+}
+
+// Input subscribes to topics of an MQTT broker and processes the received
+// messages with the Live pipeline rules of the channels the topics are routed to.
+type Input struct {
+	config    Config
+	router    *Router
+	processor Processor
+}
+
+func (i *Input) IsDisabled() bool {
+	return !i.config.Enabled
+}
+
+// Run Input.
+func (i *Input) Run(ctx context.Context) error {
+	opts, err := i.clientOptions(ctx)
+	if err != nil {
+		return err
+	}
+	client := mqtt.NewClient(opts)
+	// With ConnectRetry the token is only done once the client is connected.
+	token := client.Connect()
+	go func() {
+		<-token.Done()
+		if err := token.Error(); err != nil {
+			logger.Error("Error connecting to MQTT broker", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	client.Disconnect(250)
+	return ctx.Err()
+}
+
+func (i *Input) clientOptions(ctx context.Context) (*mqtt.ClientOptions, error) {
+	clientID := i.config.ClientID
+	if clientID == "" {
+		clientID = "grafana-live-" + util.GenerateShortUID()
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(i.config.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(i.config.Username).
+		SetPassword(i.config.Password).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(10 * time.Second).
+		SetAutoReconnect(true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("Connection to MQTT broker lost", "error", err)
+		}).
+		// The subscriptions are not kept with a clean session, so they are
+		// renewed every time the client connects.
+		SetOnConnectHandler(func(c mqtt.Client) {
+			i.subscribe(ctx, c)
+		})
+
+	tlsConfig, err := i.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	return opts, nil
+}
+
+func (i *Input) subscribe(ctx context.Context, c mqtt.Client) {
+	for _, route := range i.router.Routes() {
+		filter := route.Filter()
+		token := c.Subscribe(filter, i.config.QoS, func(_ mqtt.Client, msg mqtt.Message) {
+			i.handleMessage(ctx, msg.Topic(), msg.Payload())
+		})
+		go func() {
+			<-token.Done()
+			if err := token.Error(); err != nil {
+				logger.Error("Error subscribing to MQTT topic", "error", err, "topic", filter)
+				return
+			}
+			logger.Debug("Subscribed to MQTT topic", "topic", filter)
+		}()
+	}
+}
+
+func (i *Input) handleMessage(ctx context.Context, topic string, payload []byte) {
+	channelID, ok := i.router.Channel(topic)
+	if !ok {
+		logger.Debug("No channel for MQTT topic", "topic", topic)
+		return
+	}
+	logger.Debug("Live channel push request",
+		"protocol", "mqtt",
+		"topic", topic,
+		"channel", channelID,
+		"bodyLength", len(payload),
+	)
+
+	ruleFound, err := i.processor.ProcessInput(ctx, i.config.OrgID, channelID, payload)
+	if err != nil {
+		logger.Error("Pipeline input processing error", "error", err, "channel", channelID, "body", string(payload))
+		return
+	}
+	if !ruleFound {
+		logger.Warn("No conversion rule for a channel", "channel", channelID)
+	}
+}
+
+func (i *Input) tlsConfig() (*tls.Config, error) {
+	if i.config.TLSCACertPath == "" && i.config.TLSClientCertPath == "" && !i.config.TLSSkipVerify {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: i.config.TLSSkipVerify, // nolint:gosec
+	}
+	if i.config.TLSCACertPath != "" {
+		caCert, err := os.ReadFile(i.config.TLSCACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+	}
+	if i.config.TLSClientCertPath != "" {
+		cert, err := tls.LoadX509KeyPair(i.config.TLSClientCertPath, i.config.TLSClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
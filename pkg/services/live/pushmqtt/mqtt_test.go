@@ -0,0 +1,105 @@
+package pushmqtt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestReadConfig(t *testing.T) {
+	t.Run("should be disabled by default", func(t *testing.T) {
+		c, err := ReadConfig(&setting.Cfg{Raw: ini.Empty()})
+		require.NoError(t, err)
+		require.False(t, c.Enabled)
+	})
+
+	t.Run("should read all settings", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[live.mqtt]
+			enabled = true
+			broker_url = ssl://broker:8883
+			client_id = grafana
+			username = user
+			password = pass
+			qos = 1
+			org_id = 2
+			routes = devices/:device/telemetry=stream/devices/:device
+			tls_skip_verify = true`))
+		require.NoError(t, err)
+
+		c, err := ReadConfig(&setting.Cfg{Raw: raw})
+		require.NoError(t, err)
+		require.Equal(t, Config{
+			Enabled:       true,
+			BrokerURL:     "ssl://broker:8883",
+			ClientID:      "grafana",
+			Username:      "user",
+			Password:      "pass",
+			QoS:           1,
+			OrgID:         2,
+			Routes:        []Route{{Topic: "devices/:device/telemetry", Channel: "stream/devices/:device"}},
+			TLSSkipVerify: true,
+		}, c)
+	})
+
+	t.Run("should fail without routes", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[live.mqtt]
+			enabled = true`))
+		require.NoError(t, err)
+
+		_, err = ReadConfig(&setting.Cfg{Raw: raw})
+		require.ErrorContains(t, err, "routes must be set")
+	})
+
+	t.Run("should fail with invalid qos", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+			[live.mqtt]
+			enabled = true
+			qos = 3
+			routes = a=stream/a`))
+		require.NoError(t, err)
+
+		_, err = ReadConfig(&setting.Cfg{Raw: raw})
+		require.ErrorContains(t, err, "must be 0, 1 or 2")
+	})
+}
+
+type processedInput struct {
+	orgID   int64
+	channel string
+	body    string
+}
+
+type fakeProcessor struct {
+	inputs []processedInput
+	err    error
+}
+
+func (f *fakeProcessor) ProcessInput(_ context.Context, orgID int64, channelID string, body []byte) (bool, error) {
+	f.inputs = append(f.inputs, processedInput{orgID: orgID, channel: channelID, body: string(body)})
+	return true, f.err
+}
+
+func TestInput_handleMessage(t *testing.T) {
+	router, err := NewRouter([]Route{{Topic: "devices/:device/telemetry", Channel: "stream/devices/:device"}})
+	require.NoError(t, err)
+
+	processor := &fakeProcessor{}
+	input := &Input{config: Config{OrgID: 3}, router: router, processor: processor}
+
+	input.handleMessage(context.Background(), "devices/d1/telemetry", []byte(`{"value": 1}`))
+	input.handleMessage(context.Background(), "devices/d1/status", []byte(`{"value": 2}`))
+	processor.err = errors.New("conversion failed")
+	input.handleMessage(context.Background(), "devices/d2/telemetry", []byte(`{"value": 3}`))
+
+	require.Equal(t, []processedInput{
+		{orgID: 3, channel: "stream/devices/d1", body: `{"value": 1}`},
+		{orgID: 3, channel: "stream/devices/d2", body: `{"value": 3}`},
+	}, processor.inputs)
+}
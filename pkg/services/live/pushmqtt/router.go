@@ -0,0 +1,123 @@
+package pushmqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/pattern"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
+)
+
+// Route maps MQTT topics matching Topic to a Grafana Live channel.
+// Topic is a channel rule style pattern where ":name" matches a single
+// topic level and "*name" matches all the remaining levels. The values of
+// these parameters can be used as segments of the Channel.
+type Route struct {
+	Topic   string
+	Channel string
+}
+
+// ParseRoutes parses a comma-separated list of "topic=channel" routes.
+func ParseRoutes(s string) ([]Route, error) {
+	var routes []Route
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		topic, channel, ok := strings.Cut(r, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route %q, expected <topic>=<channel>", r)
+		}
+		routes = append(routes, Route{Topic: strings.TrimSpace(topic), Channel: strings.TrimSpace(channel)})
+	}
+	return routes, nil
+}
+
+// Filter returns the MQTT topic filter to subscribe to receive the messages of the route.
+func (r Route) Filter() string {
+	levels := strings.Split(r.Topic, "/")
+	for i, level := range levels {
+		switch {
+		case strings.HasPrefix(level, ":"):
+			levels[i] = "+"
+		case strings.HasPrefix(level, "*"):
+			levels[i] = "#"
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+func (r Route) validate() error {
+	if ok, reason := pattern.Valid(r.Topic); !ok {
+		return fmt.Errorf("invalid topic pattern %q: %s", r.Topic, reason)
+	}
+	params := map[string]struct{}{}
+	for _, level := range strings.Split(r.Topic, "/") {
+		if strings.HasPrefix(level, ":") || strings.HasPrefix(level, "*") {
+			params[level[1:]] = struct{}{}
+		}
+	}
+	for _, segment := range strings.Split(r.Channel, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			if _, ok := params[segment[1:]]; !ok {
+				return fmt.Errorf("channel %q uses parameter %q which is not in topic pattern %q", r.Channel, segment[1:], r.Topic)
+			}
+		}
+	}
+	return nil
+}
+
+// Router finds the Live channel of the messages received from the broker.
+type Router struct {
+	tree   *tree.Node
+	routes []Route
+}
+
+// NewRouter validates the routes and builds a router.
+func NewRouter(routes []Route) (router *Router, err error) {
+	router = &Router{tree: tree.New(), routes: routes}
+	// The tree panics on conflicting patterns.
+	defer func() {
+		if r := recover(); r != nil {
+			router, err = nil, fmt.Errorf("invalid routes: %v", r)
+		}
+	}()
+	for _, r := range routes {
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+		route := r
+		router.tree.AddRoute("/"+route.Topic, &route)
+	}
+	return router, nil
+}
+
+// Routes returns the routes of the router.
+func (r *Router) Routes() []Route {
+	return r.routes
+}
+
+// Channel returns the Live channel for an MQTT topic.
+func (r *Router) Channel(topic string) (string, bool) {
+	nodeValue := r.tree.GetValue("/"+topic, false)
+	if nodeValue.Handler == nil {
+		return "", false
+	}
+	route := nodeValue.Handler.(*Route)
+
+	segments := strings.Split(route.Channel, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			value, _ := nodeValue.Params.Get(segment[1:])
+			segments[i] = strings.TrimPrefix(value, "/")
+		}
+	}
+	channel := strings.Join(segments, "/")
+	if _, err := live.ParseChannel(channel); err != nil {
+		return "", false
+	}
+	return channel, true
+}
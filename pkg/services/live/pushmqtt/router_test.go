@@ -0,0 +1,66 @@
+package pushmqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(" devices/:device/telemetry=stream/devices/:device, factory/*path=stream/factory/*path ,")
+	require.NoError(t, err)
+	require.Equal(t, []Route{
+		{Topic: "devices/:device/telemetry", Channel: "stream/devices/:device"},
+		{Topic: "factory/*path", Channel: "stream/factory/*path"},
+	}, routes)
+
+	_, err = ParseRoutes("devices/telemetry")
+	require.ErrorContains(t, err, "expected <topic>=<channel>")
+}
+
+func TestRoute_Filter(t *testing.T) {
+	require.Equal(t, "devices/+/telemetry", Route{Topic: "devices/:device/telemetry"}.Filter())
+	require.Equal(t, "factory/#", Route{Topic: "factory/*path"}.Filter())
+	require.Equal(t, "sensors/temperature", Route{Topic: "sensors/temperature"}.Filter())
+}
+
+func TestRouter(t *testing.T) {
+	router, err := NewRouter([]Route{
+		{Topic: "devices/:device/telemetry", Channel: "stream/devices/:device"},
+		{Topic: "factory/*path", Channel: "stream/factory/*path"},
+		{Topic: "sensors/temperature", Channel: "stream/sensors/temperature"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		topic   string
+		channel string
+	}{
+		{topic: "devices/d1/telemetry", channel: "stream/devices/d1"},
+		{topic: "factory/line-1/press", channel: "stream/factory/line-1/press"},
+		{topic: "sensors/temperature", channel: "stream/sensors/temperature"},
+		{topic: "devices/d1/status"},
+		{topic: "unknown"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.topic, func(t *testing.T) {
+			channel, ok := router.Channel(tc.topic)
+			require.Equal(t, tc.channel != "", ok)
+			require.Equal(t, tc.channel, channel)
+		})
+	}
+}
+
+func TestNewRouter_Invalid(t *testing.T) {
+	_, err := NewRouter([]Route{{Topic: "devices/:device", Channel: "stream/devices/:id"}})
+	require.ErrorContains(t, err, `uses parameter "id" which is not in topic pattern`)
+
+	_, err = NewRouter([]Route{{Topic: "/devices", Channel: "stream/devices"}})
+	require.ErrorContains(t, err, "invalid topic pattern")
+
+	_, err = NewRouter([]Route{
+		{Topic: "devices/:device", Channel: "stream/devices/:device"},
+		{Topic: "devices/:id", Channel: "stream/devices/:id"},
+	})
+	require.ErrorContains(t, err, "invalid routes")
+}
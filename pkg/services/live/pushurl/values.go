@@ -1,14 +1,40 @@
 package pushurl
 
 import (
+	"mime"
+	"net/http"
 	"net/url"
 	"strings"
 )
 
 const (
 	frameFormatParam = "gf_live_frame_format"
+	inputFormatParam = "gf_live_input_format"
 )
 
+// InputFormatFromValues extracts format of pushed data from url values.
+func InputFormatFromValues(values url.Values) string {
+	inputFormat := strings.ToLower(values.Get(inputFormatParam))
+	if inputFormat == "" {
+		inputFormat = "influx"
+	}
+	return inputFormat
+}
+
+// InputFormatFromRequest extracts format of pushed data from url values of the request. If
+// the format is not set, protobuf and JSON content types are the ones OTLP exporters send.
+func InputFormatFromRequest(req *http.Request) string {
+	values := req.URL.Query()
+	if values.Get(inputFormatParam) == "" {
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch mediaType {
+		case "application/x-protobuf", "application/json":
+			return "otlp"
+		}
+	}
+	return InputFormatFromValues(values)
+}
+
 // FrameFormatFromValues extracts frame format tip from url values.
 func FrameFormatFromValues(values url.Values) string {
 	frameFormat := strings.ToLower(values.Get(frameFormatParam))
@@ -1,6 +1,8 @@
 package pushurl
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -13,3 +15,26 @@ func TestFrameFormatFromValues(t *testing.T) {
 	values.Set(frameFormatParam, "wide")
 	require.Equal(t, "wide", FrameFormatFromValues(values))
 }
+
+func TestInputFormatFromValues(t *testing.T) {
+	values := url.Values{}
+	require.Equal(t, "influx", InputFormatFromValues(values))
+	values.Set(inputFormatParam, "OTLP")
+	require.Equal(t, "otlp", InputFormatFromValues(values))
+}
+
+func TestInputFormatFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/live/push/test", nil)
+	require.Equal(t, "influx", InputFormatFromRequest(req))
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	require.Equal(t, "otlp", InputFormatFromRequest(req))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	require.Equal(t, "otlp", InputFormatFromRequest(req))
+	req.Header.Set("Content-Type", "text/plain")
+	require.Equal(t, "influx", InputFormatFromRequest(req))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/live/push/test?gf_live_input_format=prometheus", nil)
+	req.Header.Set("Content-Type", "application/json")
+	require.Equal(t, "prometheus", InputFormatFromRequest(req))
+}
@@ -79,18 +79,20 @@ func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		// TODO Grafana 8: decide which formats to use or keep all.
 		urlValues := r.URL.Query()
 		frameFormat := pushurl.FrameFormatFromValues(urlValues)
+		inputFormat := pushurl.InputFormatFromValues(urlValues)
 
 		logger.Debug("Live Push request",
 			"protocol", "ws",
 			"streamId", streamID,
 			"bodyLength", len(body),
 			"frameFormat", frameFormat,
+			"inputFormat", inputFormat,
 			"duration", time.Since(started).String(),
 		)
 
-		metricFrames, err := s.converter.Convert(body, frameFormat)
+		metricFrames, err := s.converter.Convert(body, inputFormat, frameFormat)
 		if err != nil {
-			logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat)
+			logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat, "inputFormat", inputFormat)
 			continue
 		}
 
@@ -0,0 +1,179 @@
+package otlp
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	influx "github.com/influxdata/line-protocol"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts OTLP/HTTP metrics export requests to Grafana frames.
+// Both protobuf and JSON encodings are supported, JSON is detected by the
+// first non-whitespace byte of the body. Data points are mapped to
+// measurements named after the metric, resource and data point attributes
+// become tags and values become fields:
+//   - gauges and non-monotonic sums have "gauge" field, monotonic sums have "counter" field.
+//   - summaries have "count", "sum" and one field per quantile.
+//   - histograms have "count", "sum" and one field per bucket upper bound with cumulative counts.
+//   - exponential histograms have "count" and "sum" fields.
+//
+// Measurements are then grouped to frames like Influx line protocol input.
+type Converter struct {
+	metricsConverter *telegraf.Converter
+}
+
+// NewConverter creates new Converter, options configure resulting frames.
+func NewConverter(opts ...telegraf.ConverterOption) *Converter {
+	return &Converter{
+		metricsConverter: telegraf.NewConverter(opts...),
+	}
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = req.UnmarshalJSON(body)
+	} else {
+		err = req.UnmarshalProto(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	var metrics []influx.Metric
+	resourceMetrics := req.Metrics().ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceTags := attributesToTags(nil, rm.Resource().Attributes())
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			ms := scopeMetrics.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				converted, err := toMetrics(ms.At(k), resourceTags)
+				if err != nil {
+					return nil, err
+				}
+				metrics = append(metrics, converted...)
+			}
+		}
+	}
+	return c.metricsConverter.ConvertMetrics(metrics)
+}
+
+func toMetrics(m pmetric.Metric, resourceTags map[string]string) ([]influx.Metric, error) {
+	var metrics []influx.Metric
+	add := func(attributes pcommon.Map, ts pcommon.Timestamp, fields map[string]any) error {
+		metric, err := influx.New(m.Name(), attributesToTags(resourceTags, attributes), fields, ts.AsTime())
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, metric)
+		return nil
+	}
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		var dataPoints pmetric.NumberDataPointSlice
+		fieldName := "gauge"
+		if m.Type() == pmetric.MetricTypeSum {
+			dataPoints = m.Sum().DataPoints()
+			if m.Sum().IsMonotonic() {
+				fieldName = "counter"
+			}
+		} else {
+			dataPoints = m.Gauge().DataPoints()
+		}
+		for i := 0; i < dataPoints.Len(); i++ {
+			dp := dataPoints.At(i)
+			if err := add(dp.Attributes(), dp.Timestamp(), map[string]any{fieldName: numberValue(dp)}); err != nil {
+				return nil, err
+			}
+		}
+	case pmetric.MetricTypeHistogram:
+		dataPoints := m.Histogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dp := dataPoints.At(i)
+			fields := map[string]any{
+				"count": float64(dp.Count()),
+				"sum":   dp.Sum(),
+			}
+			bounds := dp.ExplicitBounds().AsRaw()
+			var cumulative uint64
+			for j, count := range dp.BucketCounts().AsRaw() {
+				cumulative += count
+				bound := "+Inf"
+				if j < len(bounds) {
+					bound = formatFloat(bounds[j])
+				}
+				fields[bound] = float64(cumulative)
+			}
+			if err := add(dp.Attributes(), dp.Timestamp(), fields); err != nil {
+				return nil, err
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dataPoints := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dp := dataPoints.At(i)
+			fields := map[string]any{
+				"count": float64(dp.Count()),
+				"sum":   dp.Sum(),
+			}
+			if err := add(dp.Attributes(), dp.Timestamp(), fields); err != nil {
+				return nil, err
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		dataPoints := m.Summary().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dp := dataPoints.At(i)
+			fields := map[string]any{
+				"count": float64(dp.Count()),
+				"sum":   dp.Sum(),
+			}
+			quantiles := dp.QuantileValues()
+			for j := 0; j < quantiles.Len(); j++ {
+				fields[formatFloat(quantiles.At(j).Quantile())] = quantiles.At(j).Value()
+			}
+			if err := add(dp.Attributes(), dp.Timestamp(), fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return metrics, nil
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+// attributesToTags returns a copy of tags extended with attributes.
+func attributesToTags(tags map[string]string, attributes pcommon.Map) map[string]string {
+	result := make(map[string]string, len(tags)+attributes.Len())
+	for k, v := range tags {
+		result[k] = v
+	}
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		result[k] = v.AsString()
+		return true
+	})
+	return result
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
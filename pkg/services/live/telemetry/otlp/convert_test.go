@@ -0,0 +1,91 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+func testRequest(ts time.Time) pmetricotlp.ExportRequest {
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	sum := ms.AppendEmpty()
+	sum.SetName("http.server.requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	for i, route := range []string{"/cart", "/pay"} {
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.Attributes().PutStr("http.route", route)
+		dp.SetIntValue(int64(10 * (i + 1)))
+	}
+
+	gauge := ms.AppendEmpty()
+	gauge.SetName("process.memory.usage")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetDoubleValue(512.5)
+
+	histogram := ms.AppendEmpty()
+	histogram.SetName("http.server.duration")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	hdp.SetCount(6)
+	hdp.SetSum(2.5)
+	hdp.ExplicitBounds().FromRaw([]float64{0.1, 1})
+	hdp.BucketCounts().FromRaw([]uint64{3, 2, 1})
+
+	return pmetricotlp.NewExportRequestFromMetrics(metrics)
+}
+
+func TestConverter_Convert(t *testing.T) {
+	ts := time.Unix(1700000000, 0).UTC()
+	req := testRequest(ts)
+	protoBody, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBody, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	c := NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true))
+	for name, body := range map[string][]byte{"protobuf": protoBody, "json": jsonBody} {
+		t.Run(name, func(t *testing.T) {
+			frameWrappers, err := c.Convert(body)
+			require.NoError(t, err)
+			require.Len(t, frameWrappers, 3)
+
+			require.Equal(t, "http.server.requests", frameWrappers[0].Key())
+			frame := frameWrappers[0].Frame()
+			require.Equal(t, 2, frame.Rows())
+			require.Equal(t, `http.route=/cart, service.name=checkout`, frame.Fields[0].At(0))
+			require.True(t, ts.Equal(frame.Fields[1].At(0).(time.Time)))
+			require.Equal(t, "counter", frame.Fields[2].Name)
+			require.Equal(t, 20.0, *frame.Fields[2].At(1).(*float64))
+
+			require.Equal(t, "process.memory.usage", frameWrappers[1].Key())
+			frame = frameWrappers[1].Frame()
+			require.Equal(t, "gauge", frame.Fields[2].Name)
+			require.Equal(t, 512.5, *frame.Fields[2].At(0).(*float64))
+
+			require.Equal(t, "http.server.duration", frameWrappers[2].Key())
+			frame = frameWrappers[2].Frame()
+			values := map[string]float64{}
+			for _, f := range frame.Fields[2:] {
+				values[f.Name] = *f.At(0).(*float64)
+			}
+			require.Equal(t, map[string]float64{"0.1": 3, "1": 5, "+Inf": 6, "count": 6, "sum": 2.5}, values)
+		})
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := c.Convert([]byte(`{"resourceMetrics": 1}`))
+		require.ErrorContains(t, err, "error parsing metrics")
+	})
+}
@@ -0,0 +1,111 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	influx "github.com/influxdata/line-protocol"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts metrics in Prometheus text exposition format to Grafana frames.
+// Metrics are mapped in the same way as Telegraf prometheus input does: each
+// metric family becomes a measurement named after the family, labels become
+// tags and values become fields:
+//   - counter, gauge and untyped metrics have "counter", "gauge" or "value" field.
+//   - summaries have "count", "sum" and one field per quantile.
+//   - histograms have "count", "sum" and one field per bucket upper bound.
+//
+// Measurements are then grouped to frames like Influx line protocol input.
+type Converter struct {
+	metricsConverter *telegraf.Converter
+	now              func() time.Time
+}
+
+// NewConverter creates new Converter, options configure resulting frames.
+func NewConverter(opts ...telegraf.ConverterOption) *Converter {
+	return &Converter{
+		metricsConverter: telegraf.NewConverter(opts...),
+		now:              time.Now,
+	}
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	// Map iteration order is random, sort to get stable order of frames.
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := c.now()
+	var metrics []influx.Metric
+	for _, name := range names {
+		family := families[name]
+		for _, m := range family.GetMetric() {
+			metric, err := toMetric(name, family.GetType(), m, now)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+	return c.metricsConverter.ConvertMetrics(metrics)
+}
+
+func toMetric(name string, metricType dto.MetricType, m *dto.Metric, now time.Time) (influx.Metric, error) {
+	tags := make(map[string]string, len(m.GetLabel()))
+	for _, l := range m.GetLabel() {
+		tags[l.GetName()] = l.GetValue()
+	}
+
+	fields := map[string]any{}
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		fields["counter"] = m.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		fields["gauge"] = m.GetGauge().GetValue()
+	case dto.MetricType_SUMMARY:
+		summary := m.GetSummary()
+		fields["count"] = float64(summary.GetSampleCount())
+		fields["sum"] = summary.GetSampleSum()
+		for _, q := range summary.GetQuantile() {
+			fields[formatFloat(q.GetQuantile())] = q.GetValue()
+		}
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		histogram := m.GetHistogram()
+		fields["count"] = float64(histogram.GetSampleCount())
+		fields["sum"] = histogram.GetSampleSum()
+		for _, b := range histogram.GetBucket() {
+			fields[formatFloat(b.GetUpperBound())] = float64(b.GetCumulativeCount())
+		}
+	default:
+		fields["value"] = m.GetUntyped().GetValue()
+	}
+
+	t := now
+	if m.TimestampMs != nil {
+		t = time.UnixMilli(m.GetTimestampMs())
+	}
+	return influx.New(name, tags, fields, t)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,79 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+const exposition = `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027 1395066363000
+http_requests_total{method="post",code="400"} 3 1395066363000
+# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 42
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 5
+request_duration_seconds_bucket{le="1"} 8
+request_duration_seconds_bucket{le="+Inf"} 10
+request_duration_seconds_sum 7.5
+request_duration_seconds_count 10
+`
+
+func TestConverter_Convert(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("labels column", func(t *testing.T) {
+		c := NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true))
+		c.now = func() time.Time { return now }
+
+		frameWrappers, err := c.Convert([]byte(exposition))
+		require.NoError(t, err)
+		require.Len(t, frameWrappers, 3)
+
+		require.Equal(t, "go_goroutines", frameWrappers[0].Key())
+		frame := frameWrappers[0].Frame()
+		require.Equal(t, now, frame.Fields[1].At(0))
+		require.Equal(t, "gauge", frame.Fields[2].Name)
+		require.Equal(t, 42.0, *frame.Fields[2].At(0).(*float64))
+
+		require.Equal(t, "http_requests_total", frameWrappers[1].Key())
+		frame = frameWrappers[1].Frame()
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, `code=200, method=post`, frame.Fields[0].At(0))
+		require.Equal(t, time.UnixMilli(1395066363000), frame.Fields[1].At(0))
+		require.Equal(t, "counter", frame.Fields[2].Name)
+		require.Equal(t, 3.0, *frame.Fields[2].At(1).(*float64))
+
+		require.Equal(t, "request_duration_seconds", frameWrappers[2].Key())
+		frame = frameWrappers[2].Frame()
+		var names []string
+		for _, f := range frame.Fields[2:] {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{"+Inf", "0.1", "1", "count", "sum"}, names)
+		require.Equal(t, 8.0, *frame.Fields[4].At(0).(*float64))
+	})
+
+	t.Run("wide", func(t *testing.T) {
+		c := NewConverter(telegraf.WithFloat64Numbers(true))
+		frameWrappers, err := c.Convert([]byte(exposition))
+		require.NoError(t, err)
+		require.Len(t, frameWrappers, 3)
+
+		frame := frameWrappers[1].Frame()
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, data.Labels{"method": "post", "code": "200"}, frame.Fields[1].Labels)
+		require.Equal(t, data.Labels{"method": "post", "code": "400"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := NewConverter().Convert([]byte("metric{"))
+		require.ErrorContains(t, err, "error parsing metrics")
+	})
+}
@@ -193,10 +193,21 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	if err != nil {
 		return ErrResp(400, err, "")
 	}
+	execErrState := ngmodels.ErrorErrState
+	if cmd.ExecErrState != "" {
+		execErrState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState))
+		if err != nil {
+			return ErrResp(400, err, "")
+		}
+	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
 		return ErrResp(400, nil, "Bad For interval")
 	}
+	keepFiringFor := time.Duration(cmd.KeepFiringFor)
+	if keepFiringFor < 0 {
+		return ErrResp(400, nil, "Bad Keep Firing For interval")
+	}
 
 	intervalSeconds, err := validateInterval(srv.cfg, time.Duration(cmd.Interval))
 	if err != nil {
@@ -217,7 +228,6 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// PanelID:        nil,
 		// RuleGroup:      "",
 		// RuleGroupIndex: 0,
-		// ExecErrState:   "",
 		Title: cmd.Title,
 		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
 		UID:             "backtesting-" + util.GenerateShortUID(),
@@ -226,12 +236,13 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		Data:            queries,
 		IntervalSeconds: intervalSeconds,
 		NoDataState:     noDataState,
+		ExecErrState:    execErrState,
 		For:             forInterval,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
 	}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, backtesting.Options{KeepFiringFor: keepFiringFor})
 	if err != nil {
 		if errors.Is(err, backtesting.ErrInvalidInputData) {
 			return ErrResp(400, err, "Failed to evaluate")
@@ -239,9 +250,5 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(500, err, "Failed to evaluate")
 	}
 
-	body, err := data.FrameToJSON(result, data.IncludeAll)
-	if err != nil {
-		return ErrResp(500, err, "Failed to convert frame to JSON")
-	}
-	return response.JSON(http.StatusOK, body)
+	return response.JSON(http.StatusOK, data.Frames{result.States, result.Transitions})
 }
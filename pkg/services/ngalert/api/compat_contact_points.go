@@ -56,6 +56,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 			errs = append(errs, err)
 		}
 	}
+	for _, i := range cp.AMQP {
+		el, err := marshallIntegration(j, "amqp", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 	for _, i := range cp.Dingding {
 		el, err := marshallIntegration(j, "dingding", i, i.DisableResolveMessage)
 		integration = append(integration, el)
@@ -84,6 +91,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 			errs = append(errs, err)
 		}
 	}
+	for _, i := range cp.HTTP {
+		el, err := marshallIntegration(j, "http", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 	for _, i := range cp.Kafka {
 		el, err := marshallIntegration(j, "kafka", i, i.DisableResolveMessage)
 		integration = append(integration, el)
@@ -98,6 +112,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 			errs = append(errs, err)
 		}
 	}
+	for _, i := range cp.MQTT {
+		el, err := marshallIntegration(j, "mqtt", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 	for _, i := range cp.Opsgenie {
 		el, err := marshallIntegration(j, "opsgenie", i, i.DisableResolveMessage)
 		integration = append(integration, el)
@@ -229,6 +250,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Alertmanager = append(result.Alertmanager, integration)
 		}
+	case "amqp":
+		integration := definitions.AMQPIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.AMQP = append(result.AMQP, integration)
+		}
 	case "dingding":
 		integration := definitions.DingdingIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -249,6 +275,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Googlechat = append(result.Googlechat, integration)
 		}
+	case "http":
+		integration := definitions.HTTPIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.HTTP = append(result.HTTP, integration)
+		}
 	case "kafka":
 		integration := definitions.KafkaIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -259,6 +290,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Line = append(result.Line, integration)
 		}
+	case "mqtt":
+		integration := definitions.MQTTIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.MQTT = append(result.MQTT, integration)
+		}
 	case "opsgenie":
 		integration := definitions.OpsgenieIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -363,6 +399,18 @@ func (c contactPointsExtension) UpdateStructDescriptor(structDescriptor *jsonite
 		desc.Decoder = codec
 		desc.Encoder = codec
 	}
+	if structDescriptor.Type == reflect2.TypeOf(definitions.HTTPIntegration{}) {
+		codec := &numberAsStringCodec{ignoreError: true}
+		desc := structDescriptor.GetField("MaxAlerts")
+		desc.Decoder = codec
+		desc.Encoder = codec
+	}
+	if structDescriptor.Type == reflect2.TypeOf(definitions.MQTTIntegration{}) {
+		codec := &numberAsStringCodec{}
+		desc := structDescriptor.GetField("QoS")
+		desc.Decoder = codec
+		desc.Encoder = codec
+	}
 	if structDescriptor.Type == reflect2.TypeOf(definitions.OnCallIntegration{}) {
 		codec := &numberAsStringCodec{ignoreError: true}
 		desc := structDescriptor.GetField("MaxAlerts")
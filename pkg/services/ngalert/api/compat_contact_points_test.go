@@ -184,4 +184,61 @@ func TestContactPointFromContactPointExports(t *testing.T) {
 		require.Nil(t, result.OnCall[1].MaxAlerts)
 		require.Nil(t, result.OnCall[2].MaxAlerts)
 	})
+
+	t.Run("http with headers and optional numbers as string", func(t *testing.T) {
+		export := definitions.ContactPointExport{
+			Name: "test",
+			Receivers: []definitions.ReceiverExport{
+				{
+					Type:     "http",
+					Settings: definitions.RawMessage(`{ "url": "http://localhost", "body": "{{ .Status }}", "headers": { "X-Queue": "ops" }, "maxAlerts" : "112" }`),
+				},
+			},
+		}
+		result, err := ContactPointFromContactPointExport(export)
+		require.NoError(t, err)
+		require.Len(t, result.HTTP, 1)
+		require.Equal(t, "{{ .Status }}", result.HTTP[0].Body)
+		require.Equal(t, map[string]string{"X-Queue": "ops"}, *result.HTTP[0].Headers)
+		require.Equal(t, int64(112), *result.HTTP[0].MaxAlerts)
+
+		back, err := ContactPointToContactPointExport(result)
+		require.NoError(t, err)
+		require.Len(t, back.Integrations, 1)
+		require.Equal(t, "http", back.Integrations[0].Type)
+		require.JSONEq(t, `{ "url": "http://localhost", "body": "{{ .Status }}", "headers": { "X-Queue": "ops" }, "maxAlerts" : 112 }`, string(back.Integrations[0].Settings))
+	})
+
+	t.Run("broker integrations", func(t *testing.T) {
+		mqttSettings := `{ "brokerUrl": "ssl://localhost:8883", "topic": "alerts/{{ .CommonLabels.line }}", "qos": "1", "retain": true, "tlsSkipVerify": true }`
+		amqpSettings := `{ "url": "amqp://localhost:5672", "exchange": "alerts", "routingKey": "grafana", "persistent": true, "messageFormat": "text" }`
+		export := definitions.ContactPointExport{
+			Name: "test",
+			Receivers: []definitions.ReceiverExport{
+				{
+					Type:     "mqtt",
+					Settings: definitions.RawMessage(mqttSettings),
+				},
+				{
+					Type:     "amqp",
+					Settings: definitions.RawMessage(amqpSettings),
+				},
+			},
+		}
+		result, err := ContactPointFromContactPointExport(export)
+		require.NoError(t, err)
+		require.Len(t, result.MQTT, 1)
+		require.Equal(t, int64(1), *result.MQTT[0].QoS)
+		require.True(t, *result.MQTT[0].Retain)
+		require.Len(t, result.AMQP, 1)
+		require.Equal(t, "alerts", *result.AMQP[0].Exchange)
+
+		back, err := ContactPointToContactPointExport(result)
+		require.NoError(t, err)
+		require.Len(t, back.Integrations, 2)
+		require.Equal(t, "amqp", back.Integrations[0].Type)
+		require.JSONEq(t, amqpSettings, string(back.Integrations[0].Settings))
+		require.Equal(t, "mqtt", back.Integrations[1].Type)
+		require.JSONEq(t, strings.Replace(mqttSettings, `"1"`, `1`, 1), string(back.Integrations[1].Settings))
+	})
 }
@@ -24,6 +24,25 @@ type AlertmanagerIntegration struct {
 	Password *Secret `json:"basicAuthPassword,omitempty" yaml:"basicAuthPassword,omitempty" hcl:"basic_auth_password"`
 }
 
+type AMQPIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL string `json:"url" yaml:"url" hcl:"url"`
+
+	Exchange             *string `json:"exchange,omitempty" yaml:"exchange,omitempty" hcl:"exchange"`
+	RoutingKey           *string `json:"routingKey,omitempty" yaml:"routingKey,omitempty" hcl:"routing_key"`
+	Persistent           *bool   `json:"persistent,omitempty" yaml:"persistent,omitempty" hcl:"persistent"`
+	MessageFormat        *string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty" hcl:"message_format"`
+	Title                *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message              *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	Username             *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password             *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	TLSCACertificate     *string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	TLSClientCertificate *string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty" hcl:"tls_client_certificate"`
+	TLSClientKey         *Secret `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty" hcl:"tls_client_key"`
+	TLSSkipVerify        *bool   `json:"tlsSkipVerify,omitempty" yaml:"tlsSkipVerify,omitempty" hcl:"tls_skip_verify"`
+}
+
 type DingdingIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -107,6 +126,26 @@ type OpsgenieIntegrationResponder struct {
 	Type     string  `json:"type" yaml:"type" hcl:"type"`
 }
 
+type MQTTIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	BrokerURL string `json:"brokerUrl" yaml:"brokerUrl" hcl:"broker_url"`
+	Topic     string `json:"topic" yaml:"topic" hcl:"topic"`
+
+	ClientID             *string `json:"clientId,omitempty" yaml:"clientId,omitempty" hcl:"client_id"`
+	QoS                  *int64  `json:"qos,omitempty" yaml:"qos,omitempty" hcl:"qos"`
+	Retain               *bool   `json:"retain,omitempty" yaml:"retain,omitempty" hcl:"retain"`
+	MessageFormat        *string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty" hcl:"message_format"`
+	Title                *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message              *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	Username             *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password             *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	TLSCACertificate     *string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	TLSClientCertificate *string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty" hcl:"tls_client_certificate"`
+	TLSClientKey         *Secret `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty" hcl:"tls_client_key"`
+	TLSSkipVerify        *bool   `json:"tlsSkipVerify,omitempty" yaml:"tlsSkipVerify,omitempty" hcl:"tls_skip_verify"`
+}
+
 type OpsgenieIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -239,6 +278,21 @@ type WebexIntegration struct {
 	RoomID  *string `json:"room_id,omitempty" yaml:"room_id,omitempty" hcl:"room_id"`
 }
 
+type HTTPIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL  string `json:"url" yaml:"url" hcl:"url"`
+	Body string `json:"body" yaml:"body" hcl:"body"`
+
+	HTTPMethod               *string            `json:"httpMethod,omitempty" yaml:"httpMethod,omitempty" hcl:"http_method"`
+	Headers                  *map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" hcl:"headers"`
+	MaxAlerts                *int64             `json:"maxAlerts,omitempty" yaml:"maxAlerts,omitempty" hcl:"max_alerts"`
+	AuthorizationScheme      *string            `json:"authorization_scheme,omitempty" yaml:"authorization_scheme,omitempty" hcl:"authorization_scheme"`
+	AuthorizationCredentials *Secret            `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty" hcl:"authorization_credentials"`
+	User                     *string            `json:"username,omitempty" yaml:"username,omitempty" hcl:"basic_auth_user"`
+	Password                 *Secret            `json:"password,omitempty" yaml:"password,omitempty" hcl:"basic_auth_password"`
+}
+
 type WebhookIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -270,12 +324,15 @@ type WecomIntegration struct {
 type ContactPoint struct {
 	Name         string                    `json:"name" yaml:"name" hcl:"name"`
 	Alertmanager []AlertmanagerIntegration `json:"alertmanager" yaml:"alertmanager" hcl:"alertmanager,block"`
+	AMQP         []AMQPIntegration         `json:"amqp" yaml:"amqp" hcl:"amqp,block"`
 	Dingding     []DingdingIntegration     `json:"dingding" yaml:"dingding" hcl:"dingding,block"`
 	Discord      []DiscordIntegration      `json:"discord" yaml:"discord" hcl:"discord,block"`
 	Email        []EmailIntegration        `json:"email" yaml:"email" hcl:"email,block"`
 	Googlechat   []GooglechatIntegration   `json:"googlechat" yaml:"googlechat" hcl:"googlechat,block"`
+	HTTP         []HTTPIntegration         `json:"http" yaml:"http" hcl:"http,block"`
 	Kafka        []KafkaIntegration        `json:"kafka" yaml:"kafka" hcl:"kafka,block"`
 	Line         []LineIntegration         `json:"line" yaml:"line" hcl:"line,block"`
+	MQTT         []MQTTIntegration         `json:"mqtt" yaml:"mqtt" hcl:"mqtt,block"`
 	Opsgenie     []OpsgenieIntegration     `json:"opsgenie" yaml:"opsgenie" hcl:"opsgenie,block"`
 	Pagerduty    []PagerdutyIntegration    `json:"pagerduty" yaml:"pagerduty" hcl:"pagerduty,block"`
 	OnCall       []OnCallIntegration       `json:"oncall" yaml:"oncall" hcl:"oncall,block"`
@@ -228,8 +228,13 @@ type BacktestConfig struct {
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
 
-	NoDataState NoDataState `json:"no_data_state"`
+	NoDataState  NoDataState         `json:"no_data_state"`
+	ExecErrState ExecutionErrorState `json:"exec_err_state,omitempty"`
+	// KeepFiringFor is how long an alert instance keeps firing after its condition is no longer met.
+	KeepFiringFor model.Duration `json:"keep_firing_for,omitempty"`
 }
 
+// BacktestResult contains two frames. The first one is the state of every alert instance at each evaluation.
+// The second one lists the changes of state of the alert instances and the notifications that they send.
 // swagger:model
-type BacktestResult data.Frame
+type BacktestResult data.Frames
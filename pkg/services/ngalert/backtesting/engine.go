@@ -20,6 +20,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
+const (
+	// StateReasonKeepFiring is the reason of a firing state that is kept after the condition of the alert rule is no longer met.
+	StateReasonKeepFiring = "KeepFiring"
+	// MaxEvaluations is the maximum number of evaluations of a single backtesting run.
+	MaxEvaluations = 20000
+)
+
 var (
 	ErrInvalidInputData = errors.New("invalid input data")
 
@@ -62,7 +69,31 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 	}
 }
 
-func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+// Options contains the settings of a backtesting run that are not part of the alert rule.
+type Options struct {
+	// KeepFiringFor is how long an alert instance keeps firing after its condition is no longer met.
+	KeepFiringFor time.Duration
+}
+
+// Result is the outcome of a backtesting run.
+type Result struct {
+	// States is a wide frame that contains the state of every alert instance at each evaluation.
+	States *data.Frame
+	// Transitions is a long frame that contains every change of state of alert instances
+	// and the notifications that the change would send to the Alertmanager.
+	Transitions *data.Frame
+}
+
+// instanceTimeline tracks the simulated state of a single alert instance.
+type instanceTimeline struct {
+	field  *data.Field
+	state  eval.State
+	reason string
+	// lastActive is the last time the condition of the instance was met while it was firing.
+	lastActive time.Time
+}
+
+func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, opts Options) (*Result, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -72,7 +103,13 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
 		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
 	}
+	if opts.KeepFiringFor < 0 {
+		return nil, fmt.Errorf("%w: keep firing for must not be negative", ErrInvalidInputData)
+	}
 	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
+	if length > MaxEvaluations {
+		return nil, fmt.Errorf("%w: backtesting requires %d evaluations but at most %d are allowed. Reduce the time range or increase the evaluation interval", ErrInvalidInputData, length, MaxEvaluations)
+	}
 
 	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition())
 	if err != nil {
@@ -81,12 +118,13 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	stateManager := e.createStateManager()
 
-	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
+	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length, "keepFiringFor", opts.KeepFiringFor)
 
 	start := time.Now()
 
 	tsField := data.NewField("Time", nil, make([]time.Time, length))
-	valueFields := make(map[string]*data.Field)
+	timelines := make(map[string]*instanceTimeline)
+	transitions := newTransitionsFrame()
 
 	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
 		if idx >= length {
@@ -96,36 +134,71 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
-			field, ok := valueFields[s.CacheID]
+			timeline, ok := timelines[s.CacheID]
 			if !ok {
-				field = data.NewField("", s.Labels, make([]*string, length))
-				valueFields[s.CacheID] = field
+				timeline = &instanceTimeline{
+					field:  data.NewField("", s.Labels, make([]*string, length)),
+					state:  s.PreviousState,
+					reason: s.PreviousStateReason,
+				}
+				timelines[s.CacheID] = timeline
 			}
-			if s.State.State != eval.NoData { // set nil if NoData
-				value := s.State.State.String()
-				if s.StateReason != "" {
-					value += " (" + s.StateReason + ")"
+
+			current, reason := timeline.next(currentTime, s, opts.KeepFiringFor)
+			if current != timeline.state || reason != timeline.reason {
+				if err := transitions.appendTransition(currentTime, s.Labels, timeline.state, timeline.reason, current, reason); err != nil {
+					return err
 				}
-				field.Set(idx, &value)
-				continue
+			}
+			timeline.state, timeline.reason = current, reason
+
+			if current != eval.NoData { // set nil if NoData
+				value := state.FormatStateAndReason(current, reason)
+				timeline.field.Set(idx, &value)
 			}
 		}
 		return nil
 	})
-	fields := make([]*data.Field, 0, len(valueFields)+1)
+	fields := make([]*data.Field, 0, len(timelines)+1)
 	fields = append(fields, tsField)
-	for _, f := range valueFields {
-		fields = append(fields, f)
+	for _, t := range timelines {
+		fields = append(fields, t.field)
+	}
+	result := &Result{
+		States:      data.NewFrame("Testing results", fields...),
+		Transitions: transitions.Frame,
 	}
-	result := data.NewFrame("Testing results", fields...)
 
 	if err != nil {
 		return nil, err
 	}
-	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
+	logger.Info("Rule testing finished successfully", "duration", time.Since(start), "transitions", transitions.Rows())
 	return result, nil
 }
 
+// next returns the simulated state of the instance after the state manager processed the evaluation.
+// The state manager does not support keep firing for, which is therefore applied on top of its states:
+// an instance that fires stays firing until its condition is not met for the keepFiringFor duration.
+// Instances that disappear from the results are resolved immediately, as the state manager forgets them.
+func (t *instanceTimeline) next(now time.Time, s state.StateTransition, keepFiringFor time.Duration) (eval.State, string) {
+	current, reason := s.State.State, s.StateReason
+	if keepFiringFor > 0 && t.state == eval.Alerting && reason != models.StateReasonMissingSeries {
+		switch current {
+		case eval.Pending:
+			// the condition is met again while the instance is still firing
+			current, reason = eval.Alerting, ""
+		case eval.Normal:
+			if now.Sub(t.lastActive) < keepFiringFor {
+				current, reason = eval.Alerting, StateReasonKeepFiring
+			}
+		}
+	}
+	if current == eval.Alerting && reason != StateReasonKeepFiring {
+		t.lastActive = now
+	}
+	return current, reason
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
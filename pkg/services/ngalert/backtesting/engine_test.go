@@ -214,7 +214,9 @@ func TestEvaluatorTest(t *testing.T) {
 			return states
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		result, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
+		require.NoError(t, err)
+		frame := result.States
 
 		require.NoError(t, err)
 		require.Len(t, frame.Fields, len(states)+1) // +1 - timestamp
@@ -283,12 +285,15 @@ func TestEvaluatorTest(t *testing.T) {
 			return states
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		result, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
+		require.NoError(t, err)
+		frame := result.States
 		require.NoError(t, err)
 		expectedLen := frame.Rows()
 		for i := 0; i < 100; i++ {
 			jitter := time.Duration(rand.Int63n(ruleInterval.Milliseconds())) * time.Millisecond
-			frame, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter))
+			result, err = engine.Test(context.Background(), nil, rule, from, to.Add(jitter), Options{})
+			frame = result.States
 			require.NoError(t, err)
 			require.Equalf(t, expectedLen, frame.Rows(), "jitter %v caused result to be different that base-line", jitter)
 		}
@@ -319,7 +324,9 @@ func TestEvaluatorTest(t *testing.T) {
 			return stateByTime[now]
 		}
 
-		frame, err := engine.Test(context.Background(), nil, rule, from, to)
+		result, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
+		require.NoError(t, err)
+		frame := result.States
 		require.NoError(t, err)
 
 		var field3 *data.Field
@@ -341,6 +348,71 @@ func TestEvaluatorTest(t *testing.T) {
 		}
 	})
 
+	t.Run("should report transitions and notifications", func(t *testing.T) {
+		from := time.Unix(0, 0)
+		labels := data.Labels{"instance": "a"}
+		// the state manager fires on the second evaluation, resolves on the fourth and is pending again on the fifth
+		sequence := []eval.State{eval.Normal, eval.Alerting, eval.Alerting, eval.Normal, eval.Pending, eval.Normal}
+		to := from.Add(time.Duration(len(sequence)) * ruleInterval)
+		manager.stateCallback = func(now time.Time) []state.StateTransition {
+			idx := int(now.Sub(from) / ruleInterval)
+			previous := eval.Normal
+			if idx > 0 {
+				previous = sequence[idx-1]
+			}
+			return []state.StateTransition{{
+				State:         &state.State{CacheID: "state-a", Labels: labels, State: sequence[idx]},
+				PreviousState: previous,
+			}}
+		}
+
+		type transition struct {
+			at                string
+			previous, current string
+			notification      *string
+		}
+		transitionsOf := func(t *testing.T, frame *data.Frame) []transition {
+			t.Helper()
+			res := make([]transition, 0, frame.Rows())
+			for i := 0; i < frame.Rows(); i++ {
+				require.JSONEq(t, `{"instance":"a"}`, string(frame.Fields[1].At(i).(json.RawMessage)))
+				res = append(res, transition{
+					at:           frame.Fields[0].At(i).(time.Time).Sub(from).String(),
+					previous:     frame.Fields[2].At(i).(string),
+					current:      frame.Fields[3].At(i).(string),
+					notification: frame.Fields[4].At(i).(*string),
+				})
+			}
+			return res
+		}
+		firing, resolved := NotificationFiring, NotificationResolved
+
+		t.Run("without keep firing for", func(t *testing.T) {
+			result, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
+			require.NoError(t, err)
+			require.Equal(t, []transition{
+				{at: "1s", previous: "Normal", current: "Alerting", notification: &firing},
+				{at: "3s", previous: "Alerting", current: "Normal", notification: &resolved},
+				{at: "4s", previous: "Normal", current: "Pending"},
+				{at: "5s", previous: "Pending", current: "Normal"},
+			}, transitionsOf(t, result.Transitions))
+		})
+
+		t.Run("with keep firing for", func(t *testing.T) {
+			result, err := engine.Test(context.Background(), nil, rule, from, to, Options{KeepFiringFor: 2 * ruleInterval})
+			require.NoError(t, err)
+			require.Equal(t, []transition{
+				{at: "1s", previous: "Normal", current: "Alerting", notification: &firing},
+				{at: "3s", previous: "Alerting", current: "Alerting (KeepFiring)"},
+				{at: "4s", previous: "Alerting (KeepFiring)", current: "Alerting"},
+				{at: "5s", previous: "Alerting", current: "Alerting (KeepFiring)"},
+			}, transitionsOf(t, result.Transitions))
+
+			field := result.States.Fields[1]
+			require.Equal(t, "Alerting (KeepFiring)", *field.At(5).(*string))
+		})
+	})
+
 	t.Run("should fail", func(t *testing.T) {
 		manager.stateCallback = func(now time.Time) []state.StateTransition {
 			return nil
@@ -350,21 +422,32 @@ func TestEvaluatorTest(t *testing.T) {
 			from := time.Now()
 			t.Run("when from=to", func(t *testing.T) {
 				to := from
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 			t.Run("when from > to", func(t *testing.T) {
 				to := from.Add(-ruleInterval)
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 			t.Run("when to-from < interval", func(t *testing.T) {
 				to := from.Add(ruleInterval).Add(-time.Millisecond)
-				_, err := engine.Test(context.Background(), nil, rule, from, to)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
+				require.ErrorIs(t, err, ErrInvalidInputData)
+			})
+			t.Run("when there are too many evaluations", func(t *testing.T) {
+				to := from.Add(time.Duration(MaxEvaluations+1) * ruleInterval)
+				_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 				require.ErrorIs(t, err, ErrInvalidInputData)
 			})
 		})
 
+		t.Run("when keep firing for is negative", func(t *testing.T) {
+			from := time.Now()
+			_, err := engine.Test(context.Background(), nil, rule, from, from.Add(ruleInterval), Options{KeepFiringFor: -time.Second})
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+
 		t.Run("when evaluation fails", func(t *testing.T) {
 			expectedError := errors.New("test-error")
 			evaluator.evalCallback = func(now time.Time) (eval.Results, error) {
@@ -372,7 +455,7 @@ func TestEvaluatorTest(t *testing.T) {
 			}
 			from := time.Now()
 			to := from.Add(ruleInterval)
-			_, err := engine.Test(context.Background(), nil, rule, from, to)
+			_, err := engine.Test(context.Background(), nil, rule, from, to, Options{})
 			require.ErrorIs(t, err, expectedError)
 		})
 	})
@@ -14,9 +14,14 @@ type queryEvaluator struct {
 
 func (d *queryEvaluator) Eval(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
 	for idx, now := 0, from; idx < evaluations; idx, now = idx+1, now.Add(interval) {
+		start := time.Now()
 		results, err := d.eval.Evaluate(ctx, now)
 		if err != nil {
-			return err
+			if ctx.Err() != nil {
+				return err
+			}
+			// like the scheduler, report a failed evaluation as an error result so that the error handling of the rule applies to it.
+			results = eval.Results{eval.NewResultFromError(err, now, time.Since(start))}
 		}
 		err = callback(idx, now, results)
 		if err != nil {
@@ -49,13 +49,38 @@ func TestQueryEvaluator_Eval(t *testing.T) {
 		}
 	})
 
+	t.Run("should report failed evaluation as error result", func(t *testing.T) {
+		m := &eval_mocks.ConditionEvaluatorMock{}
+		expectedError := errors.New("test")
+		m.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(nil, expectedError)
+		evaluator := queryEvaluator{
+			eval: m,
+		}
+
+		var results []eval.Results
+		err := evaluator.Eval(ctx, from, interval, times, func(idx int, now time.Time, r eval.Results) error {
+			results = append(results, r)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, results, times)
+		for idx, r := range results {
+			require.Len(t, r, 1)
+			require.Equal(t, eval.Error, r[0].State)
+			require.ErrorIs(t, r[0].Error, expectedError)
+			require.Equal(t, from.Add(time.Duration(idx)*interval), r[0].EvaluatedAt)
+		}
+	})
+
 	t.Run("should stop evaluation if error", func(t *testing.T) {
-		t.Run("when evaluation fails", func(t *testing.T) {
+		t.Run("when context is cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
 			m := &eval_mocks.ConditionEvaluatorMock{}
 			expectedResults := eval.Results{}
-			expectedError := errors.New("test")
 			m.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(expectedResults, nil).Times(3)
-			m.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+			m.EXPECT().Evaluate(mock.Anything, mock.Anything).Run(func(context.Context, time.Time) {
+				cancel()
+			}).Return(nil, context.Canceled).Once()
 			evaluator := queryEvaluator{
 				eval: m,
 			}
@@ -66,7 +91,7 @@ func TestQueryEvaluator_Eval(t *testing.T) {
 				intervals = append(intervals, now)
 				return nil
 			})
-			require.ErrorIs(t, err, expectedError)
+			require.ErrorIs(t, err, context.Canceled)
 			require.Len(t, intervals, 3)
 		})
 
@@ -0,0 +1,72 @@
+package backtesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+const (
+	// NotificationFiring marks a transition that sends a new firing alert to the Alertmanager.
+	NotificationFiring = "firing"
+	// NotificationResolved marks a transition that sends a resolved alert to the Alertmanager.
+	NotificationResolved = "resolved"
+)
+
+// transitionsFrame is a long frame with a row per change of state of an alert instance.
+type transitionsFrame struct {
+	*data.Frame
+}
+
+func newTransitionsFrame() transitionsFrame {
+	return transitionsFrame{
+		Frame: data.NewFrame("Transitions",
+			data.NewField("Time", nil, []time.Time{}),
+			data.NewField("Labels", nil, []json.RawMessage{}),
+			data.NewField("Previous", nil, []string{}),
+			data.NewField("Current", nil, []string{}),
+			data.NewField("Notification", nil, []*string{}),
+		),
+	}
+}
+
+func (f transitionsFrame) appendTransition(at time.Time, labels data.Labels, previous eval.State, previousReason string, current eval.State, currentReason string) error {
+	lbls, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("failed to serialize labels of the alert instance: %w", err)
+	}
+	f.AppendRow(
+		at,
+		json.RawMessage(lbls),
+		state.FormatStateAndReason(previous, previousReason),
+		state.FormatStateAndReason(current, currentReason),
+		notificationOf(previous, current),
+	)
+	return nil
+}
+
+// notificationOf returns the kind of the notification that the transition between the states sends to the Alertmanager, if any.
+// Re-sending of firing alerts is not reported because the notification policy decides whether it produces a notification.
+func notificationOf(previous, current eval.State) *string {
+	var kind string
+	switch current {
+	case eval.Alerting, eval.NoData, eval.Error:
+		if previous == current {
+			return nil
+		}
+		kind = NotificationFiring
+	case eval.Normal:
+		if previous != eval.Alerting {
+			return nil
+		}
+		kind = NotificationResolved
+	default:
+		return nil
+	}
+	return &kind
+}
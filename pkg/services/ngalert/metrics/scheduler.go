@@ -24,6 +24,7 @@ type Scheduler struct {
 	SchedulePeriodicDuration            prometheus.Histogram
 	SchedulableAlertRules               prometheus.Gauge
 	SchedulableAlertRulesHash           prometheus.Gauge
+	OwnedAlertRules                     prometheus.Gauge
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
@@ -124,6 +125,13 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 				Name:      "schedule_alert_rules_hash",
 				Help:      "A hash of the alert rules that could be considered for evaluation at the next tick.",
 			}),
+		OwnedAlertRules: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_owned_alert_rules",
+				Help:      "The number of alert rules that are evaluated by this instance when the evaluation is partitioned between the members of the cluster.",
+			}),
 		UpdateSchedulableAlertRulesDuration: promauto.With(r).NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: Namespace,
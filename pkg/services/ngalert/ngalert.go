@@ -230,6 +230,12 @@ func (ng *AlertNG) init() error {
 		AlertSender:          alertsRouter,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
+		JitterEvaluations:    ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagJitterAlertRules),
+	}
+	if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingRuleSharding) {
+		schedCfg.ClusterMembership = ng.MultiOrgAlertmanager
+		schedCfg.RuleStateSharing = ng.MultiOrgAlertmanager
+		ng.MultiOrgAlertmanager.EnableAlertsSharing()
 	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
@@ -470,6 +476,22 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		return backend, nil
 	}
 
+	if backend == historian.BackendTypePrometheus {
+		pcfg, err := historian.NewPrometheusConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote prometheus configuration: %w", err)
+		}
+		req := historian.NewRequester()
+		backend := historian.NewRemotePrometheusBackend(pcfg, req, met)
+
+		testConnCtx, cancelFunc := context.WithTimeout(ctx, 10*time.Second)
+		defer cancelFunc()
+		if err := backend.TestConnection(testConnCtx); err != nil {
+			l.Error("Failed to communicate with configured remote Prometheus backend, state history may not be persisted", "error", err)
+		}
+		return backend, nil
+	}
+
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
@@ -121,6 +121,36 @@ func TestConfigureHistorianBackend(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("do not fail initialization if pinging Prometheus fails", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled:            true,
+			Backend:            "prometheus",
+			PrometheusQueryURL: "http://gone.invalid",
+			PrometheusWriteURL: "http://gone.invalid/api/v1/write",
+		}
+
+		h, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.NotNil(t, h)
+		require.NoError(t, err)
+	})
+
+	t.Run("fail initialization if Prometheus URLs are missing", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled:            true,
+			Backend:            "prometheus",
+			PrometheusQueryURL: "http://gone.invalid",
+		}
+
+		_, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.ErrorContains(t, err, "remote write URL")
+	})
+
 	t.Run("emit metric describing chosen backend", func(t *testing.T) {
 		reg := prometheus.NewRegistry()
 		met := metrics.NewHistorianMetrics(reg)
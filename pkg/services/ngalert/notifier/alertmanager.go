@@ -21,6 +21,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -354,13 +355,24 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	// Integrations implemented in Grafana are not known to the alerting module and must be built separately.
+	external, local := integrations.Split(receiver.Integrations)
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), &alertingNotify.APIReceiver{
+		ConfigReceiver: receiver.ConfigReceiver,
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: external,
+		},
+	}, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
 	s := &sender{am.NotificationService}
+	localIntegrations, err := integrations.Build(context.Background(), local, am.decryptFn, tmpl, s, LoggerFactory, am.orgID)
+	if err != nil {
+		return nil, err
+	}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
-	integrations, err := alertingNotify.BuildReceiverIntegrations(
+	result, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
 		tmpl,
 		img,
@@ -377,7 +389,7 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+	return append(result, localIntegrations...), nil
 }
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
@@ -2,9 +2,12 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
@@ -50,3 +53,36 @@ func TestAlertmanager_newAlertmanager(t *testing.T) {
 	am := setupAMTest(t)
 	require.False(t, am.Ready())
 }
+
+func TestAlertmanager_buildReceiverIntegrations(t *testing.T) {
+	am := setupAMTest(t)
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "test"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{
+					UID:      "webhook",
+					Name:     "test",
+					Type:     "webhook",
+					Settings: json.RawMessage(`{"url": "http://localhost"}`),
+				},
+				{
+					UID:      "http",
+					Name:     "test",
+					Type:     "http",
+					Settings: json.RawMessage(`{"url": "http://localhost", "body": "{{ .Status }}"}`),
+				},
+			},
+		},
+	}
+
+	integrations, err := am.buildReceiverIntegrations(receiver, alertingTemplates.ForTests(t))
+	require.NoError(t, err)
+	require.Len(t, integrations, 2)
+	require.Equal(t, "webhook", integrations[0].Name())
+	require.Equal(t, "http", integrations[1].Name())
+
+	receiver.Integrations[1].Settings = json.RawMessage(`{"url": "http://localhost"}`)
+	_, err = am.buildReceiverIntegrations(receiver, alertingTemplates.ForTests(t))
+	require.ErrorContains(t, err, "required field 'body' is not specified")
+}
@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/prometheus/alertmanager/cluster"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// alertsSharingKey is the key of the cluster state used to share alerts between the Alertmanagers of the cluster.
+const alertsSharingKey = "alerts"
+
+// ruleStateSharingKey is the key of the cluster state used to share the state of alert rules between the members
+// of the cluster.
+const ruleStateSharingKey = "rulestate"
+
+// sharedAlerts is the message broadcast to the other members of the cluster when alerts are put in the Alertmanager.
+type sharedAlerts struct {
+	From   string                   `json:"from"`
+	OrgID  int64                    `json:"orgId"`
+	Alerts apimodels.PostableAlerts `json:"alerts"`
+}
+
+// sharedRuleState is the message broadcast to the other members of the cluster when a rule has been evaluated.
+type sharedRuleState struct {
+	From      string                 `json:"from"`
+	OrgID     int64                  `json:"orgId"`
+	RuleUID   string                 `json:"ruleUid"`
+	Instances []models.AlertInstance `json:"instances"`
+}
+
+// alertsSharing is the cluster state sharing alerts between the members of the cluster. When rules are sharded,
+// each instance only evaluates some of the rules, and the Alertmanager of every instance must receive the alerts
+// of all the rules to deduplicate notifications and serve the alerts of the whole cluster.
+//
+// Alerts are sent again at every evaluation of their rule, so there is no full state to synchronize and a member
+// joining the cluster receives the alerts with the next evaluations.
+type alertsSharing struct {
+	moa     *MultiOrgAlertmanager
+	name    string
+	channel cluster.ClusterChannel
+}
+
+func (s *alertsSharing) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+func (s *alertsSharing) Merge(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var msg sharedAlerts
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	if msg.From == s.name {
+		return nil
+	}
+
+	am, err := s.moa.AlertmanagerFor(msg.OrgID)
+	if err != nil {
+		if errors.Is(err, ErrNoAlertmanagerForOrg) {
+			return nil
+		}
+		return err
+	}
+	return am.PutAlerts(context.Background(), msg.Alerts)
+}
+
+// ruleStateSharing is the cluster state sharing the state of the alert rules evaluated by every member of the
+// cluster, so that every member serves the state of all the rules without reading it from the database.
+type ruleStateSharing struct {
+	name    string
+	channel cluster.ClusterChannel
+
+	mtx     sync.RWMutex
+	handler func(key models.AlertRuleKey, instances []models.AlertInstance)
+}
+
+func (s *ruleStateSharing) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+func (s *ruleStateSharing) Merge(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var msg sharedRuleState
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	if msg.From == s.name {
+		return nil
+	}
+
+	s.mtx.RLock()
+	handler := s.handler
+	s.mtx.RUnlock()
+	if handler != nil {
+		handler(models.AlertRuleKey{OrgID: msg.OrgID, UID: msg.RuleUID}, msg.Instances)
+	}
+	return nil
+}
+
+// EnableAlertsSharing makes the Alertmanager send the alerts it receives to the other members of the cluster with
+// ShareAlerts, and put the alerts received from them. It also enables sharing the state of rules with ShareRuleState.
+// It does nothing if high availability is not configured.
+func (moa *MultiOrgAlertmanager) EnableAlertsSharing() {
+	name, _ := moa.ClusterMembers()
+	if name == "" {
+		return
+	}
+	moa.enableAlertsSharing(name)
+}
+
+func (moa *MultiOrgAlertmanager) enableAlertsSharing(name string) {
+	s := &alertsSharing{moa: moa, name: name}
+	s.channel = moa.peer.AddState(alertsSharingKey, s, moa.metrics.Registerer)
+	moa.alertsSharing = s
+
+	rs := &ruleStateSharing{name: name}
+	rs.channel = moa.peer.AddState(ruleStateSharingKey, rs, moa.metrics.Registerer)
+	moa.ruleStateSharing = rs
+}
+
+// ShareAlerts sends alerts of the organization to the Alertmanagers of the other members of the cluster.
+// It does nothing unless alerts sharing is enabled.
+func (moa *MultiOrgAlertmanager) ShareAlerts(orgID int64, alerts apimodels.PostableAlerts) {
+	s := moa.alertsSharing
+	if s == nil {
+		return
+	}
+	b, err := json.Marshal(sharedAlerts{From: s.name, OrgID: orgID, Alerts: alerts})
+	if err != nil {
+		moa.logger.Error("Failed to encode alerts shared with the cluster", "org", orgID, "error", err)
+		return
+	}
+	s.channel.Broadcast(b)
+}
+
+// ShareRuleState sends the state of a rule evaluated by this instance to the other members of the cluster.
+// It does nothing unless alerts sharing is enabled.
+func (moa *MultiOrgAlertmanager) ShareRuleState(key models.AlertRuleKey, instances []models.AlertInstance) {
+	s := moa.ruleStateSharing
+	if s == nil {
+		return
+	}
+	b, err := json.Marshal(sharedRuleState{From: s.name, OrgID: key.OrgID, RuleUID: key.UID, Instances: instances})
+	if err != nil {
+		moa.logger.Error("Failed to encode rule state shared with the cluster", append(key.LogContext(), "error", err)...)
+		return
+	}
+	s.channel.Broadcast(b)
+}
+
+// OnRuleState registers the function called with the state of the rules shared by the other members of the cluster.
+// It does nothing unless alerts sharing is enabled.
+func (moa *MultiOrgAlertmanager) OnRuleState(handler func(key models.AlertRuleKey, instances []models.AlertInstance)) {
+	s := moa.ruleStateSharing
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.handler = handler
+}
+
+var _ cluster.State = (*alertsSharing)(nil)
+var _ cluster.State = (*ruleStateSharing)(nil)
@@ -0,0 +1,158 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAlertsSharing(t *testing.T) {
+	alerts := apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{{
+		Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "test"}},
+	}}}
+
+	newMOA := func(name string, peer *fakeSharingPeer) (*MultiOrgAlertmanager, *fakePutAlertsAlertmanager) {
+		am := &fakePutAlertsAlertmanager{}
+		moa := &MultiOrgAlertmanager{
+			alertmanagers: map[int64]Alertmanager{1: am},
+			peer:          peer,
+			metrics:       metrics.NewNGAlert(prometheus.NewRegistry()).GetMultiOrgAlertmanagerMetrics(),
+			logger:        log.NewNopLogger(),
+		}
+		moa.enableAlertsSharing(name)
+		return moa, am
+	}
+
+	t.Run("should not share alerts without high availability", func(t *testing.T) {
+		moa := &MultiOrgAlertmanager{peer: &NilPeer{}}
+		moa.EnableAlertsSharing()
+		require.Nil(t, moa.alertsSharing)
+		moa.ShareAlerts(1, alerts)
+	})
+
+	t.Run("should put alerts shared by other members", func(t *testing.T) {
+		peer := &fakeSharingPeer{}
+		a, amA := newMOA("a", peer)
+		b, amB := newMOA("b", &fakeSharingPeer{})
+
+		a.ShareAlerts(1, alerts)
+		require.Len(t, peer.broadcasts[alertsSharingKey], 1)
+
+		// the broadcast is also received by its sender with some peers
+		require.NoError(t, peer.states[alertsSharingKey].Merge(peer.broadcasts[alertsSharingKey][0]))
+		require.Empty(t, amA.put)
+
+		require.NoError(t, b.peer.(*fakeSharingPeer).states[alertsSharingKey].Merge(peer.broadcasts[alertsSharingKey][0]))
+		require.Equal(t, []apimodels.PostableAlerts{alerts}, amB.put)
+	})
+
+	t.Run("should ignore alerts of organizations without Alertmanager", func(t *testing.T) {
+		peer := &fakeSharingPeer{}
+		a, _ := newMOA("a", peer)
+		b, amB := newMOA("b", &fakeSharingPeer{})
+
+		a.ShareAlerts(2, alerts)
+		require.NoError(t, b.peer.(*fakeSharingPeer).states[alertsSharingKey].Merge(peer.broadcasts[alertsSharingKey][0]))
+		require.Empty(t, amB.put)
+	})
+}
+
+func TestRuleStateSharing(t *testing.T) {
+	key := models.AlertRuleKey{OrgID: 1, UID: "rule"}
+	instances := []models.AlertInstance{{
+		AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: "hash"},
+		Labels:            models.InstanceLabels{"a": "b"},
+		CurrentState:      models.InstanceStateFiring,
+		CurrentStateSince: time.Unix(1700000000, 0).UTC(),
+		LastEvalTime:      time.Unix(1700000060, 0).UTC(),
+	}}
+
+	newMOA := func(name string, peer *fakeSharingPeer) *MultiOrgAlertmanager {
+		moa := &MultiOrgAlertmanager{
+			peer:    peer,
+			metrics: metrics.NewNGAlert(prometheus.NewRegistry()).GetMultiOrgAlertmanagerMetrics(),
+			logger:  log.NewNopLogger(),
+		}
+		moa.enableAlertsSharing(name)
+		return moa
+	}
+
+	t.Run("should not share rule state without high availability", func(t *testing.T) {
+		moa := &MultiOrgAlertmanager{peer: &NilPeer{}}
+		moa.EnableAlertsSharing()
+		require.Nil(t, moa.ruleStateSharing)
+		moa.ShareRuleState(key, instances)
+		moa.OnRuleState(func(models.AlertRuleKey, []models.AlertInstance) {})
+	})
+
+	t.Run("should pass the rule state shared by other members to the handler", func(t *testing.T) {
+		peer := &fakeSharingPeer{}
+		a := newMOA("a", peer)
+		b := newMOA("b", &fakeSharingPeer{})
+		var receivedByA, receivedByB []models.AlertInstance
+		a.OnRuleState(func(k models.AlertRuleKey, i []models.AlertInstance) {
+			receivedByA = i
+		})
+		b.OnRuleState(func(k models.AlertRuleKey, i []models.AlertInstance) {
+			require.Equal(t, key, k)
+			receivedByB = i
+		})
+
+		a.ShareRuleState(key, instances)
+		require.Len(t, peer.broadcasts[ruleStateSharingKey], 1)
+		require.Empty(t, peer.broadcasts[alertsSharingKey])
+
+		require.NoError(t, peer.states[ruleStateSharingKey].Merge(peer.broadcasts[ruleStateSharingKey][0]))
+		require.Nil(t, receivedByA)
+
+		require.NoError(t, b.peer.(*fakeSharingPeer).states[ruleStateSharingKey].Merge(peer.broadcasts[ruleStateSharingKey][0]))
+		require.Equal(t, instances, receivedByB)
+	})
+}
+
+// fakeSharingPeer is a cluster.Peer of a cluster of one member, which records the messages broadcast by every state.
+type fakeSharingPeer struct {
+	NilPeer
+	states     map[string]cluster.State
+	broadcasts map[string][][]byte
+}
+
+func (p *fakeSharingPeer) AddState(key string, s cluster.State, _ prometheus.Registerer) cluster.ClusterChannel {
+	if p.states == nil {
+		p.states = make(map[string]cluster.State)
+		p.broadcasts = make(map[string][][]byte)
+	}
+	p.states[key] = s
+	return &fakeSharingChannel{peer: p, key: key}
+}
+
+type fakeSharingChannel struct {
+	peer *fakeSharingPeer
+	key  string
+}
+
+func (c *fakeSharingChannel) Broadcast(b []byte) {
+	c.peer.broadcasts[c.key] = append(c.peer.broadcasts[c.key], b)
+}
+
+type fakePutAlertsAlertmanager struct {
+	Alertmanager
+	put []apimodels.PostableAlerts
+}
+
+func (am *fakePutAlertsAlertmanager) Ready() bool { return true }
+
+func (am *fakePutAlertsAlertmanager) PutAlerts(_ context.Context, alerts apimodels.PostableAlerts) error {
+	am.put = append(am.put, alerts)
+	return nil
+}
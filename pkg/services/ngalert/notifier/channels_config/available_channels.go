@@ -946,6 +946,316 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "http",
+			Name:        "HTTP",
+			Description: "Sends an HTTP request whose method, headers and body are rendered from templates",
+			Heading:     "HTTP settings",
+			Info:        "The URL, the HTTP method, the header values and the body can use notification templates.",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "HTTP Method",
+					Description:  "One of GET, POST, PUT, PATCH or DELETE. Default is POST.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "POST",
+					PropertyName: "httpMethod",
+				},
+				{
+					Label:        "Headers",
+					Description:  "HTTP headers to send with the request. Values can use template variables.",
+					Element:      ElementTypeKeyValueMap,
+					PropertyName: "headers",
+				},
+				{
+					Label:        "Body",
+					Description:  "Templated body of the request.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "body",
+					Placeholder:  `{"title": "{{ template "default.title" . }}"}`,
+					Required:     true,
+				},
+				{
+					Label:        "HTTP Basic Authentication - Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "HTTP Basic Authentication - Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Authorization Header - Scheme",
+					Description:  "Optionally provide a scheme for the Authorization Request Header. Default is Bearer.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "authorization_scheme",
+					Placeholder:  "Bearer",
+				},
+				{
+					Label:        "Authorization Header - Credentials",
+					Description:  "Credentials for the Authorization Request header. Only one of HTTP Basic Authentication or Authorization Request Header can be set.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "authorization_credentials",
+					Secure:       true,
+				},
+				{
+					Label:        "Max Alerts",
+					Description:  "Max alerts to include in a notification. Remaining alerts in the same batch will be ignored above this number. 0 means no limit.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "maxAlerts",
+				},
+			},
+		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Description:  "URL of the broker, for example tcp://localhost:1883 or ssl://localhost:8883.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Description:  "Templated topic to publish the notifications to.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Description:  "Prefix of the identifier of the client, to which a random suffix is added for every connection. Defaults to grafana.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "clientId",
+				},
+				{
+					Label:   "QoS",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+					Description:  "Quality of service of the published messages.",
+					PropertyName: "qos",
+				},
+				{
+					Label:        "Retain",
+					Description:  "Ask the broker to retain the last message of the topic.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "retain",
+				},
+				{
+					Label:   "Message Format",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+					Description:  "JSON publishes the notification data with the title and the message, Text publishes only the message.",
+					PropertyName: "messageFormat",
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Description:  "PEM encoded certificate of the certificate authority used to verify the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Description:  "PEM encoded client certificate used to authenticate to the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS Client Key",
+					Description:  "PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verification",
+					Description:  "Do not verify the certificate of the broker.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "tlsSkipVerify",
+				},
+			},
+		},
+		{
+			Type:        "amqp",
+			Name:        "AMQP",
+			Description: "Publishes notifications to an exchange of an AMQP 0.9.1 broker, such as RabbitMQ",
+			Heading:     "AMQP settings",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Description:  "URL of the broker, for example amqp://localhost:5672/vhost or amqps://localhost:5671/vhost.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "amqp://localhost:5672/",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Exchange",
+					Description:  "Exchange to publish the notifications to. The default exchange routes the notifications to the queue named by the routing key.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "exchange",
+				},
+				{
+					Label:        "Routing Key",
+					Description:  "Templated routing key of the notifications.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "routingKey",
+				},
+				{
+					Label:        "Persistent",
+					Description:  "Publish persistent messages that survive a restart of the broker.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "persistent",
+				},
+				{
+					Label:   "Message Format",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+					Description:  "JSON publishes the notification data with the title and the message, Text publishes only the message.",
+					PropertyName: "messageFormat",
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Description:  "Overrides the username of the URL.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Description:  "Overrides the password of the URL.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Description:  "PEM encoded certificate of the certificate authority used to verify the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Description:  "PEM encoded client certificate used to authenticate to the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS Client Key",
+					Description:  "PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verification",
+					Description:  "Do not verify the certificate of the broker.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "tlsSkipVerify",
+				},
+			},
+		},
 		{
 			Type:        "wecom",
 			Name:        "WeCom",
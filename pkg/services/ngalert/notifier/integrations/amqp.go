@@ -0,0 +1,203 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPType is the type of the AMQP 0.9.1 integration.
+const AMQPType = "amqp"
+
+// AMQPConfig is the configuration of the AMQP 0.9.1 integration.
+type AMQPConfig struct {
+	URL      string
+	Exchange string
+	// RoutingKey is a template rendered for every notification.
+	RoutingKey string
+	// Persistent marks the messages as persistent so that they survive a restart of the broker.
+	Persistent    bool
+	MessageFormat string
+	Title         string
+	Message       string
+	Username      string
+	Password      string
+	TLS           TLSSettings
+}
+
+func NewAMQPConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (AMQPConfig, error) {
+	settings := AMQPConfig{}
+	rawSettings := struct {
+		URL           string `json:"url,omitempty" yaml:"url,omitempty"`
+		Exchange      string `json:"exchange,omitempty" yaml:"exchange,omitempty"`
+		RoutingKey    string `json:"routingKey,omitempty" yaml:"routingKey,omitempty"`
+		Persistent    bool   `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+		MessageFormat string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title         string `json:"title,omitempty" yaml:"title,omitempty"`
+		Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+		Username      string `json:"username,omitempty" yaml:"username,omitempty"`
+		Password      string `json:"password,omitempty" yaml:"password,omitempty"`
+		rawTLSSettings
+	}{}
+
+	err := json.Unmarshal(jsonData, &rawSettings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if rawSettings.URL == "" {
+		return settings, errors.New("required field 'url' is not specified")
+	}
+	if _, err := amqp.ParseURI(rawSettings.URL); err != nil {
+		return settings, fmt.Errorf("invalid URL: %w", err)
+	}
+	settings.URL = rawSettings.URL
+
+	// Messages published to the default exchange are routed to the queue named by the routing key.
+	if rawSettings.Exchange == "" && rawSettings.RoutingKey == "" {
+		return settings, errors.New("at least one of 'exchange' or 'routingKey' must be specified")
+	}
+	settings.Exchange = rawSettings.Exchange
+	settings.RoutingKey = rawSettings.RoutingKey
+	settings.Persistent = rawSettings.Persistent
+
+	settings.MessageFormat, err = parseMessageFormat(rawSettings.MessageFormat)
+	if err != nil {
+		return settings, err
+	}
+	settings.Title = rawSettings.Title
+	if settings.Title == "" {
+		settings.Title = templates.DefaultMessageTitleEmbed
+	}
+	settings.Message = rawSettings.Message
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+
+	settings.Username = rawSettings.Username
+	settings.Password = decryptFn("password", rawSettings.Password)
+	settings.TLS, err = rawSettings.rawTLSSettings.toTLSSettings(decryptFn)
+	if err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+// AMQPNotifier publishes alert notifications to an exchange of an AMQP 0.9.1 broker.
+type AMQPNotifier struct {
+	*receivers.Base
+	log      logging.Logger
+	tmpl     *templates.Template
+	orgID    int64
+	settings AMQPConfig
+	publish  func(ctx context.Context, cfg AMQPConfig, routingKey string, msg amqp.Publishing) error
+}
+
+// NewAMQPNotifier is the constructor for the AMQP notifier.
+func NewAMQPNotifier(cfg AMQPConfig, meta receivers.Metadata, template *templates.Template, logger logging.Logger, orgID int64) *AMQPNotifier {
+	return &AMQPNotifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		tmpl:     template,
+		orgID:    orgID,
+		settings: cfg,
+		publish:  publishAMQP,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (an *AMQPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, data := templates.TmplText(ctx, an.tmpl, as, an.log, &tmplErr)
+
+	routingKey := tmpl(an.settings.RoutingKey)
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template AMQP routing key: %w", tmplErr)
+	}
+
+	payload, err := newBrokerPayload(ctx, an.settings.MessageFormat, tmpl, data, as, an.orgID, an.settings.Title, an.settings.Message)
+	if err != nil {
+		return false, err
+	}
+	if tmplErr != nil {
+		an.log.Warn("failed to template AMQP message", "error", tmplErr.Error())
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Transient,
+		AppId:        "grafana",
+		Timestamp:    time.Now(),
+		Body:         payload,
+	}
+	if an.settings.MessageFormat == MessageFormatText {
+		msg.ContentType = "text/plain"
+	}
+	if an.settings.Persistent {
+		msg.DeliveryMode = amqp.Persistent
+	}
+
+	if err := an.publish(ctx, an.settings, routingKey, msg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (an *AMQPNotifier) SendResolved() bool {
+	return !an.GetDisableResolveMessage()
+}
+
+// publishAMQP connects to the broker, publishes the message and waits for the broker to confirm it.
+// Notifications are infrequent, so a connection is not kept open between them.
+func publishAMQP(ctx context.Context, cfg AMQPConfig, routingKey string, msg amqp.Publishing) error {
+	amqpCfg := amqp.Config{
+		Dial:       amqp.DefaultDial(brokerConnectTimeout),
+		Properties: amqp.NewConnectionProperties(),
+	}
+	amqpCfg.Properties.SetClientConnectionName("grafana-alerting")
+	if cfg.Username != "" {
+		amqpCfg.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: cfg.Username, Password: cfg.Password}}
+	}
+	if !cfg.TLS.IsZero() {
+		tlsCfg, err := cfg.TLS.ToTLSConfig()
+		if err != nil {
+			return err
+		}
+		amqpCfg.TLSClientConfig = tlsCfg
+	}
+
+	conn, err := amqp.DialConfig(cfg.URL, amqpCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, cfg.Exchange, routingKey, false, false, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish AMQP message: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for AMQP publisher confirmation: %w", err)
+	}
+	if !acked {
+		return errors.New("AMQP message was rejected by the broker")
+	}
+	return nil
+}
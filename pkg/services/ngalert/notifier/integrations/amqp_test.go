@@ -0,0 +1,189 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAMQPConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    AMQPConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if URL is missing",
+			settings:          `{"exchange": "alerts"}`,
+			expectedInitError: `required field 'url' is not specified`,
+		},
+		{
+			name:              "Error if URL is invalid",
+			settings:          `{"url": "http://localhost", "exchange": "alerts"}`,
+			expectedInitError: `invalid URL`,
+		},
+		{
+			name:              "Error if neither exchange nor routing key are set",
+			settings:          `{"url": "amqp://localhost"}`,
+			expectedInitError: `at least one of 'exchange' or 'routingKey' must be specified`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "amqp://localhost", "routingKey": "alerts"}`,
+			expectedConfig: AMQPConfig{
+				URL:           "amqp://localhost",
+				RoutingKey:    "alerts",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name: "Extracts all fields",
+			settings: `{
+				"url": "amqps://localhost",
+				"exchange": "alerts",
+				"routingKey": "{{ .CommonLabels.severity }}",
+				"persistent": true,
+				"messageFormat": "text",
+				"title": "test-title",
+				"message": "test-message",
+				"username": "user",
+				"password": "pass"
+			}`,
+			secretSettings: map[string][]byte{
+				"password": []byte("secret-pass"),
+			},
+			expectedConfig: AMQPConfig{
+				URL:           "amqps://localhost",
+				Exchange:      "alerts",
+				RoutingKey:    "{{ .CommonLabels.severity }}",
+				Persistent:    true,
+				MessageFormat: MessageFormatText,
+				Title:         "test-title",
+				Message:       "test-message",
+				Username:      "user",
+				Password:      "secret-pass",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewAMQPConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestAMQPNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "severity": "critical"},
+				Annotations: model.LabelSet{"summary": "conveyor stopped"},
+			},
+		},
+	}
+
+	newContext := func() context.Context {
+		ctx := notify.WithGroupKey(context.Background(), "alertname")
+		ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+		return notify.WithReceiverName(ctx, "my_receiver")
+	}
+
+	t.Run("should render routing key and publish JSON message", func(t *testing.T) {
+		var routingKey string
+		var msg amqp.Publishing
+		n := NewAMQPNotifier(AMQPConfig{
+			Exchange:      "alerts",
+			RoutingKey:    "alerts.{{ .CommonLabels.severity }}",
+			Persistent:    true,
+			MessageFormat: MessageFormatJSON,
+			Title:         "{{ .CommonLabels.alertname }}",
+			Message:       "{{ .CommonAnnotations.summary }}",
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(_ context.Context, cfg AMQPConfig, key string, m amqp.Publishing) error {
+			require.Equal(t, "alerts", cfg.Exchange)
+			routingKey, msg = key, m
+			return nil
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "alerts.critical", routingKey)
+		require.Equal(t, "application/json", msg.ContentType)
+		require.Equal(t, amqp.Persistent, msg.DeliveryMode)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(msg.Body, &body))
+		require.Equal(t, "alert1", body["title"])
+		require.Equal(t, "conveyor stopped", body["message"])
+	})
+
+	t.Run("should publish only the message in text format", func(t *testing.T) {
+		var msg amqp.Publishing
+		n := NewAMQPNotifier(AMQPConfig{
+			RoutingKey:    "alerts",
+			MessageFormat: MessageFormatText,
+			Message:       "{{ .Status }}",
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(_ context.Context, _ AMQPConfig, _ string, m amqp.Publishing) error {
+			msg = m
+			return nil
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "text/plain", msg.ContentType)
+		require.Equal(t, amqp.Transient, msg.DeliveryMode)
+		require.Equal(t, "firing", string(msg.Body))
+	})
+
+	t.Run("should return publish error", func(t *testing.T) {
+		n := NewAMQPNotifier(AMQPConfig{
+			RoutingKey:    "alerts",
+			MessageFormat: MessageFormatText,
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(context.Context, AMQPConfig, string, amqp.Publishing) error {
+			return errors.New("broker unavailable")
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.False(t, ok)
+		require.ErrorContains(t, err, "broker unavailable")
+	})
+}
@@ -0,0 +1,139 @@
+package integrations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+// Formats of the messages published to message brokers.
+const (
+	// MessageFormatJSON publishes the notification data together with the rendered title and message as JSON.
+	MessageFormatJSON = "json"
+	// MessageFormatText publishes only the rendered message.
+	MessageFormatText = "text"
+)
+
+// brokerConnectTimeout is the maximum time spent connecting to a message broker.
+const brokerConnectTimeout = 10 * time.Second
+
+// brokerMessage defines the JSON object published to message brokers.
+type brokerMessage struct {
+	*templates.ExtendedData
+
+	// The protocol version.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	OrgID    int64  `json:"orgId"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+func parseMessageFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return MessageFormatJSON, nil
+	case MessageFormatJSON, MessageFormatText:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported message format %q, must be one of %q or %q", format, MessageFormatJSON, MessageFormatText)
+	}
+}
+
+// newBrokerPayload renders the title and the message and returns the payload to publish in the given format.
+func newBrokerPayload(ctx context.Context, format string, tmpl func(string) string, data *templates.ExtendedData, as []*types.Alert, orgID int64, title, message string) ([]byte, error) {
+	if format == MessageFormatText {
+		return []byte(tmpl(message)), nil
+	}
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := &brokerMessage{
+		ExtendedData: data,
+		Version:      "1",
+		GroupKey:     groupKey.String(),
+		OrgID:        orgID,
+		Title:        tmpl(title),
+		Message:      tmpl(message),
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(receivers.AlertStateAlerting)
+	} else {
+		msg.State = string(receivers.AlertStateOK)
+	}
+	return json.Marshal(msg)
+}
+
+// TLSSettings configures the TLS connection to a message broker.
+type TLSSettings struct {
+	CACertificate      string
+	ClientCertificate  string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+// IsZero returns true if no TLS setting is configured.
+func (s TLSSettings) IsZero() bool {
+	return s == TLSSettings{}
+}
+
+// ToTLSConfig creates the TLS configuration of the connection.
+func (s TLSSettings) ToTLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// #nosec G402 -- Skipping the verification is an explicit choice of the user.
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+	if s.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(s.CACertificate)) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if s.ClientCertificate != "" || s.ClientKey != "" {
+		if s.ClientCertificate == "" || s.ClientKey == "" {
+			return nil, errors.New("both client certificate and client key must be specified")
+		}
+		cert, err := tls.X509KeyPair([]byte(s.ClientCertificate), []byte(s.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// rawTLSSettings contains the TLS fields shared by the settings of the broker integrations.
+type rawTLSSettings struct {
+	TLSCACertificate     string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+	TLSClientCertificate string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+	TLSClientKey         string `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+	TLSSkipVerify        bool   `json:"tlsSkipVerify,omitempty" yaml:"tlsSkipVerify,omitempty"`
+}
+
+func (r rawTLSSettings) toTLSSettings(decryptFn receivers.DecryptFunc) (TLSSettings, error) {
+	settings := TLSSettings{
+		CACertificate:      r.TLSCACertificate,
+		ClientCertificate:  r.TLSClientCertificate,
+		ClientKey:          decryptFn("tlsClientKey", r.TLSClientKey),
+		InsecureSkipVerify: r.TLSSkipVerify,
+	}
+	if _, err := settings.ToTLSConfig(); err != nil {
+		return TLSSettings{}, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,68 @@
+package integrations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSSettings(t *testing.T) {
+	cert, key := generateTestCertificate(t)
+
+	t.Run("should load CA and client certificates", func(t *testing.T) {
+		cfg, err := TLSSettings{
+			CACertificate:     cert,
+			ClientCertificate: cert,
+			ClientKey:         key,
+		}.ToTLSConfig()
+		require.NoError(t, err)
+		require.NotNil(t, cfg.RootCAs)
+		require.Len(t, cfg.Certificates, 1)
+		require.False(t, cfg.InsecureSkipVerify)
+	})
+
+	t.Run("should fail if client certificate does not match key", func(t *testing.T) {
+		_, otherKey := generateTestCertificate(t)
+		_, err := TLSSettings{ClientCertificate: cert, ClientKey: otherKey}.ToTLSConfig()
+		require.ErrorContains(t, err, "failed to parse client certificate")
+	})
+
+	t.Run("should fail if client certificate is set without key", func(t *testing.T) {
+		_, err := TLSSettings{ClientCertificate: cert}.ToTLSConfig()
+		require.ErrorContains(t, err, "both client certificate and client key must be specified")
+	})
+
+	t.Run("should be zero if nothing is set", func(t *testing.T) {
+		require.True(t, TLSSettings{}.IsZero())
+		require.False(t, TLSSettings{InsecureSkipVerify: true}.IsZero())
+	})
+}
+
+func generateTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
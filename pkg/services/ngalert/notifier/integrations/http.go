@@ -0,0 +1,206 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+	"golang.org/x/net/http/httpguts"
+)
+
+// HTTPType is the type of the templated HTTP integration.
+const HTTPType = "http"
+
+var supportedHTTPMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPost:   {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+}
+
+// HTTPConfig is the configuration of the HTTP integration. The URL, the method, the values of the
+// headers and the body are templates that are rendered for every notification.
+type HTTPConfig struct {
+	URL        string
+	HTTPMethod string
+	Headers    map[string]string
+	Body       string
+	MaxAlerts  int
+	// Authorization Header.
+	AuthorizationScheme      string
+	AuthorizationCredentials string
+	// HTTP Basic Authentication.
+	User     string
+	Password string
+}
+
+func NewHTTPConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (HTTPConfig, error) {
+	settings := HTTPConfig{}
+	rawSettings := struct {
+		URL                      string                   `json:"url,omitempty" yaml:"url,omitempty"`
+		HTTPMethod               string                   `json:"httpMethod,omitempty" yaml:"httpMethod,omitempty"`
+		Headers                  map[string]string        `json:"headers,omitempty" yaml:"headers,omitempty"`
+		Body                     string                   `json:"body,omitempty" yaml:"body,omitempty"`
+		MaxAlerts                receivers.OptionalNumber `json:"maxAlerts,omitempty" yaml:"maxAlerts,omitempty"`
+		AuthorizationScheme      string                   `json:"authorization_scheme,omitempty" yaml:"authorization_scheme,omitempty"`
+		AuthorizationCredentials string                   `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty"`
+		User                     string                   `json:"username,omitempty" yaml:"username,omitempty"`
+		Password                 string                   `json:"password,omitempty" yaml:"password,omitempty"`
+	}{}
+
+	err := json.Unmarshal(jsonData, &rawSettings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if rawSettings.URL == "" {
+		return settings, errors.New("required field 'url' is not specified")
+	}
+	settings.URL = rawSettings.URL
+	if rawSettings.Body == "" {
+		return settings, errors.New("required field 'body' is not specified")
+	}
+	settings.Body = rawSettings.Body
+
+	if rawSettings.HTTPMethod == "" {
+		rawSettings.HTTPMethod = http.MethodPost
+	}
+	// The method can only be validated here if it is not a template.
+	if !isTemplate(rawSettings.HTTPMethod) {
+		rawSettings.HTTPMethod = strings.ToUpper(rawSettings.HTTPMethod)
+		if _, ok := supportedHTTPMethods[rawSettings.HTTPMethod]; !ok {
+			return settings, fmt.Errorf("unsupported HTTP method %q", rawSettings.HTTPMethod)
+		}
+	}
+	settings.HTTPMethod = rawSettings.HTTPMethod
+
+	settings.Headers = make(map[string]string, len(rawSettings.Headers))
+	for k, v := range rawSettings.Headers {
+		if !httpguts.ValidHeaderFieldName(k) {
+			return settings, fmt.Errorf("invalid header name %q", k)
+		}
+		settings.Headers[http.CanonicalHeaderKey(k)] = v
+	}
+
+	if rawSettings.MaxAlerts != "" {
+		settings.MaxAlerts, _ = strconv.Atoi(rawSettings.MaxAlerts.String())
+	}
+
+	settings.AuthorizationScheme = rawSettings.AuthorizationScheme
+	settings.User = decryptFn("username", rawSettings.User)
+	settings.Password = decryptFn("password", rawSettings.Password)
+	settings.AuthorizationCredentials = decryptFn("authorization_credentials", rawSettings.AuthorizationCredentials)
+
+	if settings.AuthorizationCredentials != "" && settings.AuthorizationScheme == "" {
+		settings.AuthorizationScheme = "Bearer"
+	}
+	if settings.User != "" && settings.Password != "" && settings.AuthorizationScheme != "" && settings.AuthorizationCredentials != "" {
+		return settings, errors.New("both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted")
+	}
+	if _, ok := settings.Headers["Authorization"]; ok && settings.AuthorizationCredentials != "" {
+		return settings, errors.New("both the Authorization header and Authorization Header credentials are set, only 1 is permitted")
+	}
+	return settings, nil
+}
+
+// HTTPNotifier sends alert notifications as HTTP requests
+// whose method, headers and body are rendered from templates.
+type HTTPNotifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	orgID    int64
+	settings HTTPConfig
+}
+
+// NewHTTPNotifier is the constructor for the HTTP notifier.
+func NewHTTPNotifier(cfg HTTPConfig, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger, orgID int64) *HTTPNotifier {
+	return &HTTPNotifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		orgID:    orgID,
+		settings: cfg,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (hn *HTTPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	as, numTruncated := truncateAlerts(hn.settings.MaxAlerts, as)
+	if numTruncated > 0 {
+		hn.log.Debug("Truncated alerts", "truncated", numTruncated, "maxAlerts", hn.settings.MaxAlerts)
+	}
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, hn.tmpl, as, hn.log, &tmplErr)
+
+	parsedURL := tmpl(hn.settings.URL)
+	method := strings.ToUpper(strings.TrimSpace(tmpl(hn.settings.HTTPMethod)))
+	body := tmpl(hn.settings.Body)
+	headers := make(map[string]string, len(hn.settings.Headers)+1)
+	for k, v := range hn.settings.Headers {
+		headers[k] = tmpl(v)
+	}
+	// Unlike the webhook integration, the payload is defined entirely by the templates, so
+	// sending a partially rendered request is never useful.
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template HTTP request: %w", tmplErr)
+	}
+
+	if method == "" {
+		method = http.MethodPost
+	}
+	if _, ok := supportedHTTPMethods[method]; !ok {
+		return false, fmt.Errorf("unsupported HTTP method %q", method)
+	}
+
+	// The content type is set by the sender, so it must not be passed as a header.
+	contentType := headers["Content-Type"]
+	delete(headers, "Content-Type")
+
+	if hn.settings.AuthorizationScheme != "" && hn.settings.AuthorizationCredentials != "" {
+		headers["Authorization"] = fmt.Sprintf("%s %s", hn.settings.AuthorizationScheme, hn.settings.AuthorizationCredentials)
+	}
+
+	cmd := &receivers.SendWebhookSettings{
+		URL:         parsedURL,
+		User:        hn.settings.User,
+		Password:    hn.settings.Password,
+		Body:        body,
+		HTTPMethod:  method,
+		HTTPHeader:  headers,
+		ContentType: contentType,
+	}
+
+	if err := hn.ns.SendWebhook(ctx, cmd); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (hn *HTTPNotifier) SendResolved() bool {
+	return !hn.GetDisableResolveMessage()
+}
+
+func truncateAlerts(maxAlerts int, alerts []*types.Alert) ([]*types.Alert, int) {
+	if maxAlerts > 0 && len(alerts) > maxAlerts {
+		return alerts[:maxAlerts], len(alerts) - maxAlerts
+	}
+
+	return alerts, 0
+}
+
+func isTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
@@ -0,0 +1,269 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    HTTPConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if empty JSON object",
+			settings:          `{}`,
+			expectedInitError: `required field 'url' is not specified`,
+		},
+		{
+			name:              "Error if body is missing",
+			settings:          `{"url": "http://localhost"}`,
+			expectedInitError: `required field 'body' is not specified`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "http://localhost", "body": "{{ .Status }}"}`,
+			expectedConfig: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: http.MethodPost,
+				Headers:    map[string]string{},
+				Body:       "{{ .Status }}",
+			},
+		},
+		{
+			name: "Extracts all fields",
+			settings: `{
+				"url": "http://localhost/{{ .CommonLabels.team }}",
+				"httpMethod": "put",
+				"headers": {"x-ticket-queue": "{{ .CommonLabels.queue }}", "Content-Type": "text/plain"},
+				"body": "{{ .Status }}",
+				"maxAlerts": "2",
+				"authorization_scheme": "Token",
+				"authorization_credentials": "test-credentials"
+			}`,
+			expectedConfig: HTTPConfig{
+				URL:        "http://localhost/{{ .CommonLabels.team }}",
+				HTTPMethod: http.MethodPut,
+				Headers: map[string]string{
+					"X-Ticket-Queue": "{{ .CommonLabels.queue }}",
+					"Content-Type":   "text/plain",
+				},
+				Body:                     "{{ .Status }}",
+				MaxAlerts:                2,
+				AuthorizationScheme:      "Token",
+				AuthorizationCredentials: "test-credentials",
+			},
+		},
+		{
+			name:     "Should not validate templated method",
+			settings: `{"url": "http://localhost", "body": "test", "httpMethod": "{{ .CommonLabels.method }}"}`,
+			expectedConfig: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: "{{ .CommonLabels.method }}",
+				Headers:    map[string]string{},
+				Body:       "test",
+			},
+		},
+		{
+			name:     "Should override credentials with secrets",
+			settings: `{"url": "http://localhost", "body": "test", "username": "user", "password": "pass"}`,
+			secretSettings: map[string][]byte{
+				"username": []byte("secret-user"),
+				"password": []byte("secret-pass"),
+			},
+			expectedConfig: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: http.MethodPost,
+				Headers:    map[string]string{},
+				Body:       "test",
+				User:       "secret-user",
+				Password:   "secret-pass",
+			},
+		},
+		{
+			name:              "Error if method is not supported",
+			settings:          `{"url": "http://localhost", "body": "test", "httpMethod": "TRACE"}`,
+			expectedInitError: `unsupported HTTP method "TRACE"`,
+		},
+		{
+			name:              "Error if header name is invalid",
+			settings:          `{"url": "http://localhost", "body": "test", "headers": {"X Ticket": "test"}}`,
+			expectedInitError: `invalid header name "X Ticket"`,
+		},
+		{
+			name:              "Error if both Authorization header and credentials are set",
+			settings:          `{"url": "http://localhost", "body": "test", "headers": {"authorization": "Basic abc"}, "authorization_credentials": "test"}`,
+			expectedInitError: `both the Authorization header and Authorization Header credentials are set, only 1 is permitted`,
+		},
+		{
+			name:              "Error if both basic authentication and authorization header are set",
+			settings:          `{"url": "http://localhost", "body": "test", "username": "user", "password": "pass", "authorization_credentials": "test"}`,
+			expectedInitError: `both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewHTTPConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestHTTPNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "queue": "ops"},
+				Annotations: model.LabelSet{"summary": "disk is full"},
+			},
+		},
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert2", "queue": "ops"},
+				Annotations: model.LabelSet{"summary": "disk is almost full"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		settings HTTPConfig
+
+		expURL         string
+		expHTTPMethod  string
+		expBody        string
+		expHeaders     map[string]string
+		expContentType string
+		expUser        string
+		expPassword    string
+		expMsgError    string
+	}{
+		{
+			name: "Renders URL, method, headers and body",
+			settings: HTTPConfig{
+				URL:        "http://localhost/queues/{{ .CommonLabels.queue }}",
+				HTTPMethod: "{{ if eq .Status \"firing\" }}post{{ else }}patch{{ end }}",
+				Headers: map[string]string{
+					"X-Ticket-Queue": "{{ .CommonLabels.queue }}",
+					"Content-Type":   "text/plain",
+				},
+				Body: "{{ .Status }}:{{ range .Alerts }} {{ .Annotations.summary }};{{ end }}",
+			},
+			expURL:         "http://localhost/queues/ops",
+			expHTTPMethod:  http.MethodPost,
+			expBody:        "firing: disk is full; disk is almost full;",
+			expHeaders:     map[string]string{"X-Ticket-Queue": "ops"},
+			expContentType: "text/plain",
+		},
+		{
+			name: "Truncates alerts and adds authorization header",
+			settings: HTTPConfig{
+				URL:                      "http://localhost",
+				HTTPMethod:               http.MethodPut,
+				Headers:                  map[string]string{},
+				Body:                     "{{ len .Alerts }}",
+				MaxAlerts:                1,
+				AuthorizationScheme:      "Bearer",
+				AuthorizationCredentials: "token",
+			},
+			expURL:        "http://localhost",
+			expHTTPMethod: http.MethodPut,
+			expBody:       "1",
+			expHeaders:    map[string]string{"Authorization": "Bearer token"},
+		},
+		{
+			name: "Sends basic authentication",
+			settings: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: http.MethodPost,
+				Body:       "test",
+				User:       "user",
+				Password:   "pass",
+			},
+			expURL:        "http://localhost",
+			expHTTPMethod: http.MethodPost,
+			expBody:       "test",
+			expHeaders:    map[string]string{},
+			expUser:       "user",
+			expPassword:   "pass",
+		},
+		{
+			name: "Error if body cannot be rendered",
+			settings: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: http.MethodPost,
+				Body:       "{{ .Unknown }",
+			},
+			expMsgError: "failed to template HTTP request",
+		},
+		{
+			name: "Error if rendered method is not supported",
+			settings: HTTPConfig{
+				URL:        "http://localhost",
+				HTTPMethod: "{{ .CommonLabels.queue }}",
+				Body:       "test",
+			},
+			expMsgError: `unsupported HTTP method "OPS"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			webhookSender := receivers.MockNotificationService()
+			n := NewHTTPNotifier(c.settings, receivers.Metadata{}, tmpl, webhookSender, &logging.FakeLogger{}, 1)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ctx = notify.WithReceiverName(ctx, "my_receiver")
+			ok, err := n.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.ErrorContains(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			require.Equal(t, c.expURL, webhookSender.Webhook.URL)
+			require.Equal(t, c.expHTTPMethod, webhookSender.Webhook.HTTPMethod)
+			require.Equal(t, c.expBody, webhookSender.Webhook.Body)
+			require.Equal(t, c.expHeaders, webhookSender.Webhook.HTTPHeader)
+			require.Equal(t, c.expContentType, webhookSender.Webhook.ContentType)
+			require.Equal(t, c.expUser, webhookSender.Webhook.User)
+			require.Equal(t, c.expPassword, webhookSender.Webhook.Password)
+		})
+	}
+}
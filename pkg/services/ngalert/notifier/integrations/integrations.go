@@ -0,0 +1,191 @@
+// Package integrations contains the contact points that are implemented in
+// Grafana rather than in the github.com/grafana/alerting module. They are
+// built next to the integrations of the alerting module and share the same
+// templates, senders and metadata.
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+)
+
+// Notifier is the interface implemented by every integration of this package.
+type Notifier interface {
+	notify.Notifier
+	notify.ResolvedSender
+}
+
+// FactoryConfig contains everything an integration needs to be built.
+type FactoryConfig struct {
+	Metadata      receivers.Metadata
+	Settings      json.RawMessage
+	Decrypt       receivers.DecryptFunc
+	Template      *alertingTemplates.Template
+	WebhookSender receivers.WebhookSender
+	Logger        alertingLogging.Logger
+	OrgID         int64
+}
+
+type factory struct {
+	// validate parses the settings of the integration and returns an error if they are not valid.
+	validate func(settings json.RawMessage, decrypt receivers.DecryptFunc) error
+	// build creates the notifier of the integration.
+	build func(cfg FactoryConfig) (Notifier, error)
+}
+
+var factories = map[string]factory{
+	HTTPType: {
+		validate: func(settings json.RawMessage, decrypt receivers.DecryptFunc) error {
+			_, err := NewHTTPConfig(settings, decrypt)
+			return err
+		},
+		build: func(cfg FactoryConfig) (Notifier, error) {
+			settings, err := NewHTTPConfig(cfg.Settings, cfg.Decrypt)
+			if err != nil {
+				return nil, err
+			}
+			return NewHTTPNotifier(settings, cfg.Metadata, cfg.Template, cfg.WebhookSender, cfg.Logger, cfg.OrgID), nil
+		},
+	},
+	MQTTType: {
+		validate: func(settings json.RawMessage, decrypt receivers.DecryptFunc) error {
+			_, err := NewMQTTConfig(settings, decrypt)
+			return err
+		},
+		build: func(cfg FactoryConfig) (Notifier, error) {
+			settings, err := NewMQTTConfig(cfg.Settings, cfg.Decrypt)
+			if err != nil {
+				return nil, err
+			}
+			return NewMQTTNotifier(settings, cfg.Metadata, cfg.Template, cfg.Logger, cfg.OrgID), nil
+		},
+	},
+	AMQPType: {
+		validate: func(settings json.RawMessage, decrypt receivers.DecryptFunc) error {
+			_, err := NewAMQPConfig(settings, decrypt)
+			return err
+		},
+		build: func(cfg FactoryConfig) (Notifier, error) {
+			settings, err := NewAMQPConfig(cfg.Settings, cfg.Decrypt)
+			if err != nil {
+				return nil, err
+			}
+			return NewAMQPNotifier(settings, cfg.Metadata, cfg.Template, cfg.Logger, cfg.OrgID), nil
+		},
+	},
+}
+
+// IsSupported returns true if the integration type is implemented by this package.
+func IsSupported(integrationType string) bool {
+	_, ok := factories[strings.ToLower(integrationType)]
+	return ok
+}
+
+// Split separates the integrations that are implemented by this package from the ones
+// that must be built by the alerting module.
+func Split(integrations []*alertingNotify.GrafanaIntegrationConfig) (external []*alertingNotify.GrafanaIntegrationConfig, local []*alertingNotify.GrafanaIntegrationConfig) {
+	for _, integration := range integrations {
+		if IsSupported(integration.Type) {
+			local = append(local, integration)
+			continue
+		}
+		external = append(external, integration)
+	}
+	return external, local
+}
+
+// Validate parses, decrypts and validates the settings of the integration.
+func Validate(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	f, ok := factories[strings.ToLower(integration.Type)]
+	if !ok {
+		return alertingNotify.IntegrationValidationError{
+			Integration: integration,
+			Err:         fmt.Errorf("notifier %s is not supported", integration.Type),
+		}
+	}
+	decryptFn, err := newDecryptFunc(ctx, integration, decrypt)
+	if err == nil {
+		err = f.validate(integration.Settings, decryptFn)
+	}
+	if err != nil {
+		return alertingNotify.IntegrationValidationError{
+			Integration: integration,
+			Err:         err,
+		}
+	}
+	return nil
+}
+
+// Build creates an alerting integration for each of the integrations. All integrations must be
+// supported by this package.
+func Build(
+	ctx context.Context,
+	integrations []*alertingNotify.GrafanaIntegrationConfig,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	tmpl *alertingTemplates.Template,
+	sender receivers.WebhookSender,
+	logger alertingLogging.LoggerFactory,
+	orgID int64,
+) ([]*alertingNotify.Integration, error) {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	// The index of an integration is its position among the integrations of the same type.
+	indexes := make(map[string]int, len(integrations))
+	for _, integration := range integrations {
+		integrationType := strings.ToLower(integration.Type)
+		f, ok := factories[integrationType]
+		if !ok {
+			return nil, alertingNotify.IntegrationValidationError{
+				Integration: integration,
+				Err:         fmt.Errorf("notifier %s is not supported", integration.Type),
+			}
+		}
+		decryptFn, err := newDecryptFunc(ctx, integration, decrypt)
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+		}
+		meta := receivers.Metadata{
+			UID:                   integration.UID,
+			Name:                  integration.Name,
+			Type:                  integration.Type,
+			DisableResolveMessage: integration.DisableResolveMessage,
+		}
+		n, err := f.build(FactoryConfig{
+			Metadata:      meta,
+			Settings:      integration.Settings,
+			Decrypt:       decryptFn,
+			Template:      tmpl,
+			WebhookSender: sender,
+			Logger:        logger("ngalert.notifier."+meta.Type, "notifierUID", meta.UID),
+			OrgID:         orgID,
+		})
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, meta.Type, indexes[integrationType], meta.Name))
+		indexes[integrationType]++
+	}
+	return result, nil
+}
+
+func newDecryptFunc(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) (receivers.DecryptFunc, error) {
+	secureSettings := make(map[string][]byte, len(integration.SecureSettings))
+	for k, v := range integration.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secure settings key %s: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	return func(key string, fallback string) string {
+		return decrypt(ctx, secureSettings, key, fallback)
+	}, nil
+}
@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	webhook := &alertingNotify.GrafanaIntegrationConfig{Type: "webhook"}
+	httpIntegration := &alertingNotify.GrafanaIntegrationConfig{Type: "HTTP"}
+
+	external, local := Split([]*alertingNotify.GrafanaIntegrationConfig{webhook, httpIntegration})
+	require.Equal(t, []*alertingNotify.GrafanaIntegrationConfig{webhook}, external)
+	require.Equal(t, []*alertingNotify.GrafanaIntegrationConfig{httpIntegration}, local)
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should return validation error if settings are invalid", func(t *testing.T) {
+		integration := &alertingNotify.GrafanaIntegrationConfig{
+			UID:      "test",
+			Type:     HTTPType,
+			Settings: []byte(`{"url": "http://localhost"}`),
+		}
+		err := Validate(context.Background(), integration, alertingNotify.GetDecryptedValueFnForTesting)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, integration, validationErr.Integration)
+	})
+
+	t.Run("should decode secure settings", func(t *testing.T) {
+		integration := &alertingNotify.GrafanaIntegrationConfig{
+			Type:     HTTPType,
+			Settings: []byte(`{"url": "http://localhost", "body": "test", "username": "user", "authorization_credentials": "token"}`),
+			SecureSettings: map[string]string{
+				"password": base64.StdEncoding.EncodeToString([]byte("pass")),
+			},
+		}
+		err := Validate(context.Background(), integration, alertingNotify.GetDecryptedValueFnForTesting)
+		require.ErrorContains(t, err, "both HTTP Basic Authentication and Authorization Header are set")
+	})
+
+	t.Run("should fail if type is not supported", func(t *testing.T) {
+		err := Validate(context.Background(), &alertingNotify.GrafanaIntegrationConfig{Type: "webhook"}, alertingNotify.GetDecryptedValueFnForTesting)
+		require.ErrorContains(t, err, "notifier webhook is not supported")
+	})
+}
+
+func TestBuild(t *testing.T) {
+	newIntegration := func(name string) *alertingNotify.GrafanaIntegrationConfig {
+		return &alertingNotify.GrafanaIntegrationConfig{
+			UID:      name,
+			Name:     name,
+			Type:     HTTPType,
+			Settings: []byte(`{"url": "http://localhost", "body": "test"}`),
+		}
+	}
+	loggerFactory := func(_ string, _ ...any) logging.Logger {
+		return &logging.FakeLogger{}
+	}
+
+	result, err := Build(
+		context.Background(),
+		[]*alertingNotify.GrafanaIntegrationConfig{newIntegration("first"), newIntegration("second")},
+		alertingNotify.GetDecryptedValueFnForTesting,
+		templates.ForTests(t),
+		receivers.MockNotificationService(),
+		loggerFactory,
+		1,
+	)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	for i, integration := range result {
+		require.Equal(t, HTTPType, integration.Name())
+		require.Equal(t, i, integration.Index())
+	}
+
+	invalid := newIntegration("invalid")
+	invalid.Settings = []byte(`{}`)
+	_, err = Build(
+		context.Background(),
+		[]*alertingNotify.GrafanaIntegrationConfig{newIntegration("first"), invalid},
+		alertingNotify.GetDecryptedValueFnForTesting,
+		templates.ForTests(t),
+		receivers.MockNotificationService(),
+		loggerFactory,
+		1,
+	)
+	var validationErr alertingNotify.IntegrationValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, invalid, validationErr.Integration)
+}
@@ -0,0 +1,220 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MQTTType is the type of the MQTT integration.
+const MQTTType = "mqtt"
+
+var supportedMQTTSchemes = map[string]struct{}{
+	"tcp":   {},
+	"mqtt":  {},
+	"ssl":   {},
+	"tls":   {},
+	"mqtts": {},
+	"ws":    {},
+	"wss":   {},
+}
+
+// MQTTConfig is the configuration of the MQTT integration.
+type MQTTConfig struct {
+	BrokerURL string
+	// ClientID is the prefix of the identifier of the client, to which a random suffix is added for every
+	// connection. The default prefix is grafana.
+	ClientID      string
+	Topic         string
+	QoS           byte
+	Retain        bool
+	MessageFormat string
+	Title         string
+	Message       string
+	Username      string
+	Password      string
+	TLS           TLSSettings
+}
+
+func NewMQTTConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (MQTTConfig, error) {
+	settings := MQTTConfig{}
+	rawSettings := struct {
+		BrokerURL     string                   `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+		ClientID      string                   `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+		Topic         string                   `json:"topic,omitempty" yaml:"topic,omitempty"`
+		QoS           receivers.OptionalNumber `json:"qos,omitempty" yaml:"qos,omitempty"`
+		Retain        bool                     `json:"retain,omitempty" yaml:"retain,omitempty"`
+		MessageFormat string                   `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title         string                   `json:"title,omitempty" yaml:"title,omitempty"`
+		Message       string                   `json:"message,omitempty" yaml:"message,omitempty"`
+		Username      string                   `json:"username,omitempty" yaml:"username,omitempty"`
+		Password      string                   `json:"password,omitempty" yaml:"password,omitempty"`
+		rawTLSSettings
+	}{}
+
+	err := json.Unmarshal(jsonData, &rawSettings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if rawSettings.BrokerURL == "" {
+		return settings, errors.New("required field 'brokerUrl' is not specified")
+	}
+	u, err := url.Parse(rawSettings.BrokerURL)
+	if err != nil {
+		return settings, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	if _, ok := supportedMQTTSchemes[u.Scheme]; !ok {
+		return settings, fmt.Errorf("unsupported broker URL scheme %q", u.Scheme)
+	}
+	settings.BrokerURL = rawSettings.BrokerURL
+	settings.ClientID = rawSettings.ClientID
+
+	if rawSettings.Topic == "" {
+		return settings, errors.New("required field 'topic' is not specified")
+	}
+	settings.Topic = rawSettings.Topic
+
+	if rawSettings.QoS != "" {
+		qos, err := strconv.Atoi(rawSettings.QoS.String())
+		if err != nil || qos < 0 || qos > 2 {
+			return settings, fmt.Errorf("invalid QoS %q, must be 0, 1 or 2", rawSettings.QoS.String())
+		}
+		settings.QoS = byte(qos)
+	}
+	settings.Retain = rawSettings.Retain
+
+	settings.MessageFormat, err = parseMessageFormat(rawSettings.MessageFormat)
+	if err != nil {
+		return settings, err
+	}
+	settings.Title = rawSettings.Title
+	if settings.Title == "" {
+		settings.Title = templates.DefaultMessageTitleEmbed
+	}
+	settings.Message = rawSettings.Message
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+
+	settings.Username = rawSettings.Username
+	settings.Password = decryptFn("password", rawSettings.Password)
+	settings.TLS, err = rawSettings.rawTLSSettings.toTLSSettings(decryptFn)
+	if err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+// MQTTNotifier publishes alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*receivers.Base
+	log      logging.Logger
+	tmpl     *templates.Template
+	orgID    int64
+	settings MQTTConfig
+	publish  func(ctx context.Context, cfg MQTTConfig, topic string, payload []byte) error
+}
+
+// NewMQTTNotifier is the constructor for the MQTT notifier.
+func NewMQTTNotifier(cfg MQTTConfig, meta receivers.Metadata, template *templates.Template, logger logging.Logger, orgID int64) *MQTTNotifier {
+	return &MQTTNotifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		tmpl:     template,
+		orgID:    orgID,
+		settings: cfg,
+		publish:  publishMQTT,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (mn *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, data := templates.TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	topic := tmpl(mn.settings.Topic)
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template MQTT topic: %w", tmplErr)
+	}
+	if topic == "" {
+		return false, errors.New("rendered MQTT topic is empty")
+	}
+
+	payload, err := newBrokerPayload(ctx, mn.settings.MessageFormat, tmpl, data, as, mn.orgID, mn.settings.Title, mn.settings.Message)
+	if err != nil {
+		return false, err
+	}
+	if tmplErr != nil {
+		mn.log.Warn("failed to template MQTT message", "error", tmplErr.Error())
+	}
+
+	if err := mn.publish(ctx, mn.settings, topic, payload); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (mn *MQTTNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
+
+// publishMQTT connects to the broker, publishes the payload and disconnects. Notifications are
+// infrequent, so a connection is not kept open between them.
+func publishMQTT(ctx context.Context, cfg MQTTConfig, topic string, payload []byte) error {
+	// A broker disconnects the client with the same identifier when another one connects, so every connection
+	// must have its own identifier, e.g. for notifications sent at the same time or by several Grafana instances.
+	prefix := cfg.ClientID
+	if prefix == "" {
+		prefix = "grafana"
+	}
+	clientID := prefix + "-" + util.GenerateShortUID()
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(clientID).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(brokerConnectTimeout)
+	if cfg.Username != "" {
+		opts.SetUsername(cfg.Username)
+		opts.SetPassword(cfg.Password)
+	}
+	if !cfg.TLS.IsZero() {
+		tlsCfg, err := cfg.TLS.ToTLSConfig()
+		if err != nil {
+			return err
+		}
+		opts.SetTLSConfig(tlsCfg)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	// Give the client some time to finish in-flight work before closing the connection.
+	defer client.Disconnect(250)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, cfg.QoS, cfg.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish MQTT message: %w", err)
+	}
+	return nil
+}
+
+func waitMQTTToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-token.Done():
+		return token.Error()
+	}
+}
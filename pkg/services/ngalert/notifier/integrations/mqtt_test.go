@@ -0,0 +1,305 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMQTTConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    MQTTConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if broker URL is missing",
+			settings:          `{"topic": "alerts"}`,
+			expectedInitError: `required field 'brokerUrl' is not specified`,
+		},
+		{
+			name:              "Error if topic is missing",
+			settings:          `{"brokerUrl": "tcp://localhost:1883"}`,
+			expectedInitError: `required field 'topic' is not specified`,
+		},
+		{
+			name:              "Error if scheme is not supported",
+			settings:          `{"brokerUrl": "http://localhost:1883", "topic": "alerts"}`,
+			expectedInitError: `unsupported broker URL scheme "http"`,
+		},
+		{
+			name:              "Error if QoS is invalid",
+			settings:          `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 3}`,
+			expectedInitError: `invalid QoS "3", must be 0, 1 or 2`,
+		},
+		{
+			name:              "Error if message format is invalid",
+			settings:          `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "messageFormat": "xml"}`,
+			expectedInitError: `unsupported message format "xml"`,
+		},
+		{
+			name:              "Error if client key is set without client certificate",
+			settings:          `{"brokerUrl": "ssl://localhost:8883", "topic": "alerts", "tlsClientKey": "key"}`,
+			expectedInitError: `both client certificate and client key must be specified`,
+		},
+		{
+			name:              "Error if CA certificate is invalid",
+			settings:          `{"brokerUrl": "ssl://localhost:8883", "topic": "alerts", "tlsCACertificate": "invalid"}`,
+			expectedInitError: `failed to parse CA certificate`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`,
+			expectedConfig: MQTTConfig{
+				BrokerURL:     "tcp://localhost:1883",
+				Topic:         "alerts",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name: "Extracts all fields",
+			settings: `{
+				"brokerUrl": "ssl://localhost:8883",
+				"clientId": "grafana",
+				"topic": "alerts/{{ .CommonLabels.line }}",
+				"qos": "1",
+				"retain": true,
+				"messageFormat": "text",
+				"title": "test-title",
+				"message": "test-message",
+				"username": "user",
+				"password": "pass",
+				"tlsSkipVerify": true
+			}`,
+			secretSettings: map[string][]byte{
+				"password": []byte("secret-pass"),
+			},
+			expectedConfig: MQTTConfig{
+				BrokerURL:     "ssl://localhost:8883",
+				ClientID:      "grafana",
+				Topic:         "alerts/{{ .CommonLabels.line }}",
+				QoS:           1,
+				Retain:        true,
+				MessageFormat: MessageFormatText,
+				Title:         "test-title",
+				Message:       "test-message",
+				Username:      "user",
+				Password:      "secret-pass",
+				TLS:           TLSSettings{InsecureSkipVerify: true},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewMQTTConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "line": "line-1"},
+				Annotations: model.LabelSet{"summary": "conveyor stopped"},
+			},
+		},
+	}
+
+	newContext := func() context.Context {
+		ctx := notify.WithGroupKey(context.Background(), "alertname")
+		ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+		return notify.WithReceiverName(ctx, "my_receiver")
+	}
+
+	t.Run("should render topic and publish JSON message", func(t *testing.T) {
+		var topic string
+		var payload []byte
+		n := NewMQTTNotifier(MQTTConfig{
+			Topic:         "alerts/{{ .CommonLabels.line }}",
+			MessageFormat: MessageFormatJSON,
+			Title:         "{{ .CommonLabels.alertname }}",
+			Message:       "{{ .CommonAnnotations.summary }}",
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(_ context.Context, _ MQTTConfig, t string, p []byte) error {
+			topic, payload = t, p
+			return nil
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "alerts/line-1", topic)
+
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal(payload, &msg))
+		require.Equal(t, "alert1", msg["title"])
+		require.Equal(t, "conveyor stopped", msg["message"])
+		require.Equal(t, "alerting", msg["state"])
+		require.Equal(t, "firing", msg["status"])
+		require.EqualValues(t, 1, msg["orgId"])
+	})
+
+	t.Run("should publish only the message in text format", func(t *testing.T) {
+		var payload []byte
+		n := NewMQTTNotifier(MQTTConfig{
+			Topic:         "alerts",
+			MessageFormat: MessageFormatText,
+			Message:       "{{ .Status }}: {{ .CommonAnnotations.summary }}",
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(_ context.Context, _ MQTTConfig, _ string, p []byte) error {
+			payload = p
+			return nil
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "firing: conveyor stopped", string(payload))
+	})
+
+	t.Run("should fail if topic cannot be rendered", func(t *testing.T) {
+		n := NewMQTTNotifier(MQTTConfig{
+			Topic:         "{{ .Unknown }",
+			MessageFormat: MessageFormatText,
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+		n.publish = func(context.Context, MQTTConfig, string, []byte) error {
+			require.Fail(t, "message should not be published")
+			return nil
+		}
+
+		ok, err := n.Notify(newContext(), alerts...)
+		require.False(t, ok)
+		require.ErrorContains(t, err, "failed to template MQTT topic")
+	})
+
+	t.Run("should publish to broker", func(t *testing.T) {
+		broker := newFakeMQTTBroker(t)
+		n := NewMQTTNotifier(MQTTConfig{
+			BrokerURL:     "tcp://" + broker.addr,
+			ClientID:      "grafana-test",
+			Topic:         "alerts/{{ .CommonLabels.line }}",
+			QoS:           1,
+			Retain:        true,
+			MessageFormat: MessageFormatText,
+			Message:       "{{ .Status }}",
+			Username:      "user",
+			Password:      "pass",
+		}, receivers.Metadata{}, tmpl, &logging.FakeLogger{}, 1)
+
+		ctx, cancel := context.WithTimeout(newContext(), 10*time.Second)
+		defer cancel()
+		ok, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		connect := <-broker.connects
+		require.Regexp(t, "^grafana-test-.+$", connect.ClientIdentifier)
+		require.Equal(t, "user", connect.Username)
+		require.Equal(t, "pass", string(connect.Password))
+
+		published := <-broker.published
+		require.Equal(t, "alerts/line-1", published.TopicName)
+		require.Equal(t, "firing", string(published.Payload))
+		require.EqualValues(t, 1, published.Qos)
+		require.True(t, published.Retain)
+	})
+}
+
+// fakeMQTTBroker accepts MQTT connections and acknowledges the published messages.
+type fakeMQTTBroker struct {
+	addr      string
+	connects  chan *packets.ConnectPacket
+	published chan *packets.PublishPacket
+}
+
+func newFakeMQTTBroker(t *testing.T) *fakeMQTTBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	b := &fakeMQTTBroker{
+		addr:      l.Addr().String(),
+		connects:  make(chan *packets.ConnectPacket, 1),
+		published: make(chan *packets.PublishPacket, 1),
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeMQTTBroker) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	for {
+		p, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := p.(type) {
+		case *packets.ConnectPacket:
+			b.connects <- p
+			if err := packets.NewControlPacket(packets.Connack).Write(conn); err != nil {
+				return
+			}
+		case *packets.PublishPacket:
+			b.published <- p
+			if p.Qos == 1 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				if err := ack.Write(conn); err != nil {
+					return
+				}
+			}
+		case *packets.PingreqPacket:
+			if err := packets.NewControlPacket(packets.Pingresp).Write(conn); err != nil {
+				return
+			}
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
@@ -73,6 +73,10 @@ type MultiOrgAlertmanager struct {
 	// clusterPeer represents the clustering peers of Alertmanagers between Grafana instances.
 	peer         alertingNotify.ClusterPeer
 	settleCancel context.CancelFunc
+	// alertsSharing shares alerts with the other members of the cluster, if enabled.
+	alertsSharing *alertsSharing
+	// ruleStateSharing shares the state of alert rules with the other members of the cluster, if enabled.
+	ruleStateSharing *ruleStateSharing
 
 	configStore AlertingStore
 	orgStore    store.OrgStore
@@ -398,6 +402,24 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers returns the name of this instance and the names of the active members of the Alertmanager cluster.
+// The name is empty if high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembers() (string, []string) {
+	switch p := moa.peer.(type) {
+	case *cluster.Peer:
+		peers := p.Peers()
+		members := make([]string, 0, len(peers))
+		for _, m := range peers {
+			members = append(members, m.Name())
+		}
+		return p.Name(), members
+	case *redisPeer:
+		return p.withPrefix(p.name), p.Members()
+	default:
+		return "", nil
+	}
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
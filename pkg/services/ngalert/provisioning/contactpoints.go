@@ -18,6 +18,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -520,6 +521,9 @@ func ValidateContactPoint(ctx context.Context, e apimodels.EmbeddedContactPoint,
 	if err != nil {
 		return err
 	}
+	if integrations.IsSupported(integration.Type) {
+		return integrations.Validate(ctx, &integration, decryptFunc)
+	}
 	_, err = alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
 		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
 			Integrations: []*alertingNotify.GrafanaIntegrationConfig{&integration},
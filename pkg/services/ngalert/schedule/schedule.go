@@ -41,6 +41,14 @@ type AlertsSender interface {
 	Send(ctx context.Context, key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// RuleStateSharing shares the state of the alert rules between the members of the cluster.
+type RuleStateSharing interface {
+	// ShareRuleState sends the state of a rule evaluated by this instance to the other members of the cluster.
+	ShareRuleState(key ngmodels.AlertRuleKey, instances []ngmodels.AlertInstance)
+	// OnRuleState registers the function called with the state of the rules shared by the other members.
+	OnRuleState(handler func(key ngmodels.AlertRuleKey, instances []ngmodels.AlertInstance))
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
@@ -90,6 +98,16 @@ type schedule struct {
 	// last evaluated.
 	schedulableAlertRules alertRulesRegistry
 
+	// jitterEvaluations spreads the evaluations of rules with the same interval over the interval.
+	jitterEvaluations bool
+
+	// sharder decides which alert rules are evaluated by this instance. It is nil if all rules are evaluated.
+	sharder *ruleSharder
+	// notOwned contains the rules that are evaluated by other members of the cluster.
+	notOwned map[ngmodels.AlertRuleKey]struct{}
+	// ruleStateSharing shares the state of the rules evaluated by this instance with the other members of the cluster.
+	ruleStateSharing RuleStateSharing
+
 	tracer tracing.Tracer
 }
 
@@ -107,6 +125,12 @@ type SchedulerCfg struct {
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
 	Log                  log.Logger
+	// JitterEvaluations spreads the evaluations of rules with the same interval over the interval, based on the rule UID.
+	JitterEvaluations bool
+	// ClusterMembership, if set, partitions the evaluation of alert rules between the members of the cluster.
+	ClusterMembership ClusterMembership
+	// RuleStateSharing, if set, keeps the state of the rules evaluated by other members of the cluster up to date.
+	RuleStateSharing RuleStateSharing
 }
 
 // NewScheduler returns a new schedule.
@@ -127,6 +151,15 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		jitterEvaluations:     cfg.JitterEvaluations,
+		notOwned:              make(map[ngmodels.AlertRuleKey]struct{}),
+		ruleStateSharing:      cfg.RuleStateSharing,
+	}
+	if cfg.ClusterMembership != nil {
+		sch.sharder = newRuleSharder(cfg.ClusterMembership, defaultMembershipSettleTime, cfg.Log.New("component", "sharding"))
+	}
+	if sch.ruleStateSharing != nil {
+		sch.ruleStateSharing.OnRuleState(sch.setSharedRuleState)
 	}
 
 	return &sch
@@ -241,12 +274,29 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	registeredDefinitions := sch.registry.keyMap()
 
 	sch.updateRulesMetrics(alertRules)
+	rebalanced := false
+	if sch.sharder != nil {
+		rebalanced = sch.sharder.sync(tick)
+	}
 
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
+	owned := 0
+	// notOwnedToRefresh are the rules evaluated by other members of the cluster whose owner has changed. Their state
+	// is loaded from the database, then kept up to date by the state their owner shares after every evaluation.
+	notOwnedToRefresh := make(map[int64][]*ngmodels.AlertRule)
 	for _, item := range alertRules {
 		key := item.GetKey()
+		if !sch.isOwned(key) {
+			if sch.handOver(key) || rebalanced {
+				notOwnedToRefresh[key.OrgID] = append(notOwnedToRefresh[key.OrgID], item)
+			}
+			continue
+		}
+		_, acquired := sch.notOwned[key]
+		delete(sch.notOwned, key)
+		owned++
 		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
 		// enforce minimum evaluation interval
@@ -258,7 +308,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 		if newRoutine && !invalidInterval {
+			rule := item
 			dispatcherGroup.Go(func() error {
+				if acquired {
+					// the rule was evaluated by another member of the cluster, continue from the state it saved
+					sch.stateManager.WarmRule(ngmodels.WithRuleKey(ruleInfo.ctx, key), rule)
+				}
 				return sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
 			})
 		}
@@ -271,7 +326,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == 0
+		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == sch.evaluationOffset(key, itemFrequency)
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
@@ -309,6 +364,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		delete(registeredDefinitions, key)
 	}
 
+	if sch.sharder != nil {
+		sch.metrics.OwnedAlertRules.Set(float64(owned))
+	}
+	for orgID, rules := range notOwnedToRefresh {
+		sch.stateManager.WarmRules(ctx, orgID, rules)
+	}
+
 	if len(missingFolder) > 0 { // if this happens then there can be problems with fetching folders from the database.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
@@ -339,12 +401,63 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
+		if _, ok := sch.notOwned[key]; ok {
+			continue
+		}
 		toDelete = append(toDelete, key)
 	}
 	sch.deleteAlertRule(toDelete...)
+	for key := range sch.notOwned {
+		if sch.schedulableAlertRules.get(key) == nil {
+			delete(sch.notOwned, key)
+			sch.stateManager.ForgetRule(key)
+		}
+	}
 	return readyToRun, registeredDefinitions, updatedRules
 }
 
+// isOwned returns true if the rule is evaluated by this instance.
+func (sch *schedule) isOwned(key ngmodels.AlertRuleKey) bool {
+	return sch.sharder == nil || sch.sharder.owns(key)
+}
+
+// setSharedRuleState replaces the state of a rule evaluated by another member of the cluster with the state shared
+// by that member. The state of the rules evaluated by this instance is never replaced, even if it was shared by their
+// previous owner.
+func (sch *schedule) setSharedRuleState(key ngmodels.AlertRuleKey, instances []ngmodels.AlertInstance) {
+	if sch.isOwned(key) {
+		return
+	}
+	rule := sch.schedulableAlertRules.get(key)
+	if rule == nil {
+		return
+	}
+	sch.stateManager.SetRuleInstances(rule, instances)
+}
+
+// handOver stops the evaluation routine of a rule that is evaluated by another member of the cluster from now on.
+// The state of the rule is neither deleted from the database nor resolved, because the new owner continues from it.
+// It returns false if the rule was already handed over.
+func (sch *schedule) handOver(key ngmodels.AlertRuleKey) bool {
+	if _, ok := sch.notOwned[key]; ok {
+		return false
+	}
+	sch.notOwned[key] = struct{}{}
+	sch.log.Debug("Alert rule is evaluated by another member of the cluster", key.LogContext()...)
+	if ruleInfo, ok := sch.registry.del(key); ok {
+		ruleInfo.stop(errRuleNotOwned)
+	}
+	return true
+}
+
+// evaluationOffset returns the tick, within the interval of the rule, at which the rule is evaluated.
+func (sch *schedule) evaluationOffset(key ngmodels.AlertRuleKey, itemFrequency int64) int64 {
+	if !sch.jitterEvaluations || itemFrequency <= 1 {
+		return 0
+	}
+	return int64(hashString(fmt.Sprintf("%d/%s", key.OrgID, key.UID)) % uint64(itemFrequency))
+}
+
 func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertRuleKey, evalCh <-chan *evaluation, updateCh <-chan ruleVersionAndPauseStatus) error {
 	grafanaCtx = ngmodels.WithRuleKey(grafanaCtx, key)
 	logger := sch.log.FromContext(grafanaCtx)
@@ -429,6 +542,9 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			state.GetRuleExtraLabels(e.rule, e.folderTitle, !sch.disableGrafanaFolder),
 		)
 		processDuration.Observe(sch.clock.Now().Sub(start).Seconds())
+		if sch.ruleStateSharing != nil {
+			sch.ruleStateSharing.ShareRuleState(key, sch.stateManager.RuleInstances(key))
+		}
 
 		start = sch.clock.Now()
 		alerts := state.FromStateTransitionToPostableAlerts(processedStates, sch.stateManager, sch.appURL)
@@ -0,0 +1,166 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of virtual nodes of every member in the hash ring.
+// More tokens spread the rules more evenly between members at the cost of a bigger ring.
+const ringTokensPerMember = 128
+
+// defaultMembershipSettleTime is how long the members of the cluster must stay the same before the rules are
+// rebalanced. It is longer than the time the Alertmanager cluster waits to settle, so that members that are
+// joining or flapping do not cause rules to move back and forth.
+const defaultMembershipSettleTime = 45 * time.Second
+
+var errRuleNotOwned = errors.New("rule is evaluated by another member of the cluster")
+
+// ClusterMembership provides the members of the high availability cluster that share the evaluation of alert rules.
+type ClusterMembership interface {
+	// ClusterMembers returns the name of this instance and the names of all active members of the cluster, including this instance.
+	// An empty name means that the instance is not part of a cluster.
+	ClusterMembers() (string, []string)
+}
+
+// ruleSharder assigns every alert rule to a single member of the cluster by consistent hashing of the rule key,
+// so that when members join or leave the cluster, only the rules of the affected members move to other members.
+type ruleSharder struct {
+	membership ClusterMembership
+	settleTime time.Duration
+	log        log.Logger
+
+	mtx     sync.RWMutex
+	self    string
+	members []string
+	tokens  []ringToken
+
+	// pendingSelf and pendingMembers are the members of the cluster that differ from the ones of the ring,
+	// observed without change since pendingSince.
+	pending        bool
+	pendingSelf    string
+	pendingMembers []string
+	pendingSince   time.Time
+}
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+func newRuleSharder(membership ClusterMembership, settleTime time.Duration, logger log.Logger) *ruleSharder {
+	return &ruleSharder{
+		membership: membership,
+		settleTime: settleTime,
+		log:        logger,
+	}
+}
+
+// sync rebuilds the hash ring once the members of the cluster have changed and then stayed the same for the settle
+// time. Until then, rules stay with their current owners, which is every member before the ring is first built.
+// It returns true if the ring was rebuilt.
+func (s *ruleSharder) sync(now time.Time) bool {
+	self, members := s.membership.ClusterMembers()
+	members = normalizeMembers(self, members)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if self == s.self && equalMembers(members, s.members) {
+		s.pending = false
+		return false
+	}
+	if !s.pending || self != s.pendingSelf || !equalMembers(members, s.pendingMembers) {
+		s.pending, s.pendingSelf, s.pendingMembers, s.pendingSince = true, self, members, now
+		s.log.Debug("Cluster members have changed, waiting for them to settle", "self", self, "members", strings.Join(members, ","))
+	}
+	if now.Sub(s.pendingSince) < s.settleTime {
+		return false
+	}
+
+	tokens := make([]ringToken, 0, len(members)*ringTokensPerMember)
+	for _, m := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			tokens = append(tokens, ringToken{hash: hashString(fmt.Sprintf("%s-%d", m, i)), member: m})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].hash == tokens[j].hash {
+			return tokens[i].member < tokens[j].member
+		}
+		return tokens[i].hash < tokens[j].hash
+	})
+
+	s.log.Info("Cluster members have changed, rebalancing alert rules", "self", self, "members", strings.Join(members, ","))
+	s.self, s.members, s.tokens = self, members, tokens
+	s.pending = false
+	return true
+}
+
+// owns returns true if the rule must be evaluated by this instance.
+func (s *ruleSharder) owns(key ngmodels.AlertRuleKey) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if len(s.tokens) == 0 || s.self == "" {
+		// not in a cluster, evaluate everything
+		return true
+	}
+	return s.ownerOf(key) == s.self
+}
+
+func (s *ruleSharder) ownerOf(key ngmodels.AlertRuleKey) string {
+	h := hashString(fmt.Sprintf("%d/%s", key.OrgID, key.UID))
+	idx := sort.Search(len(s.tokens), func(i int) bool {
+		return s.tokens[i].hash >= h
+	})
+	if idx == len(s.tokens) {
+		idx = 0
+	}
+	return s.tokens[idx].member
+}
+
+// normalizeMembers returns the sorted unique members. The instance itself is always a member,
+// because it keeps evaluating rules even if it could not learn about the other members.
+func normalizeMembers(self string, members []string) []string {
+	if self == "" {
+		return nil
+	}
+	unique := make(map[string]struct{}, len(members)+1)
+	unique[self] = struct{}{}
+	for _, m := range members {
+		unique[m] = struct{}{}
+	}
+	result := make([]string, 0, len(unique))
+	for m := range unique {
+		result = append(result, m)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	// We can ignore err as fnv64 does not return an error
+	// nolint:errcheck,gosec
+	h.Write([]byte(s))
+	return h.Sum64()
+}
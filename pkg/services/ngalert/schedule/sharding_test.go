@@ -0,0 +1,284 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestRuleSharder(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+	ownersOf := func(members ...string) map[models.AlertRuleKey]string {
+		sharders := make(map[string]*ruleSharder, len(members))
+		for _, m := range members {
+			sharders[m] = newRuleSharder(&fakeClusterMembership{self: m, members: members}, 0, log.NewNopLogger())
+			require.True(t, sharders[m].sync(time.Time{}))
+		}
+		owners := make(map[models.AlertRuleKey]string, len(keys))
+		for _, key := range keys {
+			for m, s := range sharders {
+				if s.owns(key) {
+					require.Emptyf(t, owners[key], "rule %s is owned by %s and %s", key, owners[key], m)
+					owners[key] = m
+				}
+			}
+			require.NotEmptyf(t, owners[key], "rule %s is not owned by any member", key)
+		}
+		return owners
+	}
+
+	t.Run("should own all rules when not in a cluster", func(t *testing.T) {
+		s := newRuleSharder(&fakeClusterMembership{}, 0, log.NewNopLogger())
+		s.sync(time.Time{})
+		for _, key := range keys {
+			require.True(t, s.owns(key))
+		}
+	})
+
+	t.Run("should assign every rule to a single member", func(t *testing.T) {
+		owners := ownersOf("a", "b", "c")
+		count := map[string]int{}
+		for _, m := range owners {
+			count[m]++
+		}
+		for _, m := range []string{"a", "b", "c"} {
+			require.Greaterf(t, count[m], len(keys)/6, "member %s owns too few rules: %v", m, count)
+		}
+	})
+
+	t.Run("should only move rules of the member that left", func(t *testing.T) {
+		before := ownersOf("a", "b", "c")
+		after := ownersOf("a", "c")
+		for key, owner := range before {
+			if owner != "b" {
+				require.Equal(t, owner, after[key])
+			}
+		}
+	})
+
+	t.Run("should rebuild the ring only when members change", func(t *testing.T) {
+		membership := &fakeClusterMembership{self: "a", members: []string{"b", "a"}}
+		s := newRuleSharder(membership, 0, log.NewNopLogger())
+		require.True(t, s.sync(time.Time{}))
+		require.Equal(t, []string{"a", "b"}, s.members)
+
+		membership.set("a", []string{"a", "b"})
+		require.False(t, s.sync(time.Time{}))
+
+		membership.set("a", []string{"b"})
+		require.False(t, s.sync(time.Time{}), "the instance itself is always a member")
+
+		membership.set("a", []string{"a", "b", "c"})
+		require.True(t, s.sync(time.Time{}))
+	})
+}
+
+func TestRuleSharderSettle(t *testing.T) {
+	membership := &fakeClusterMembership{self: "a", members: []string{"a"}}
+	s := newRuleSharder(membership, time.Minute, log.NewNopLogger())
+	now := time.Time{}
+	require.False(t, s.sync(now), "the ring is built once members have settled")
+	require.True(t, s.sync(now.Add(time.Minute)))
+
+	membership.set("a", []string{"a", "b"})
+	now = now.Add(2 * time.Minute)
+	require.False(t, s.sync(now))
+	require.Equal(t, []string{"a"}, s.members)
+
+	t.Run("should restart waiting when members change again", func(t *testing.T) {
+		membership.set("a", []string{"a", "b", "c"})
+		require.False(t, s.sync(now.Add(30*time.Second)))
+		require.False(t, s.sync(now.Add(time.Minute)))
+		require.True(t, s.sync(now.Add(90*time.Second)))
+		require.Equal(t, []string{"a", "b", "c"}, s.members)
+		now = now.Add(90 * time.Second)
+	})
+
+	t.Run("should ignore members that come back before settling", func(t *testing.T) {
+		membership.set("a", []string{"a", "b"})
+		require.False(t, s.sync(now.Add(10*time.Second)))
+		membership.set("a", []string{"a", "b", "c"})
+		require.False(t, s.sync(now.Add(20*time.Second)))
+		membership.set("a", []string{"a", "b"})
+		require.False(t, s.sync(now.Add(time.Minute)), "members must stay the same for the whole settle time")
+		require.Equal(t, []string{"a", "b", "c"}, s.members)
+	})
+}
+
+func TestProcessTicksWithSharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sched := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+
+	membership := &fakeClusterMembership{self: "a", members: []string{"a"}}
+	sched.sharder = newRuleSharder(membership, 0, log.NewNopLogger())
+	sharing := &fakeRuleStateSharing{}
+	sched.ruleStateSharing = sharing
+
+	evalAppliedCh := make(chan evalAppliedInfo, 10)
+	stopAppliedCh := make(chan models.AlertRuleKey, 10)
+	sched.evalAppliedFunc = func(alertDefKey models.AlertRuleKey, now time.Time) {
+		evalAppliedCh <- evalAppliedInfo{alertDefKey: alertDefKey, now: now}
+	}
+	sched.stopAppliedFunc = func(alertDefKey models.AlertRuleKey) {
+		stopAppliedCh <- alertDefKey
+	}
+
+	// find a rule for each member of the cluster {a, b}
+	other := newRuleSharder(&fakeClusterMembership{self: "b", members: []string{"a", "b"}}, 0, log.NewNopLogger())
+	other.sync(time.Time{})
+	var ownRule, otherRule *models.AlertRule
+	for ownRule == nil || otherRule == nil {
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second), withQueryForState(t, eval.Alerting))()
+		if other.owns(rule.GetKey()) {
+			otherRule = rule
+		} else {
+			ownRule = rule
+		}
+	}
+	ruleStore.PutRule(ctx, ownRule, otherRule)
+
+	tick := time.Time{}
+
+	t.Run("should evaluate all rules when alone", func(t *testing.T) {
+		tick = tick.Add(time.Second)
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, 2)
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey(), otherRule.GetKey())
+		require.NotEmpty(t, sched.stateManager.GetStatesForRuleUID(otherRule.OrgID, otherRule.UID))
+		require.NotEmpty(t, sharing.get(ownRule.GetKey()), "state of evaluated rules must be shared")
+	})
+
+	t.Run("should stop rules of another member without deleting their state", func(t *testing.T) {
+		membership.set("a", []string{"a", "b"})
+		instanceStore.RecordedOps = nil
+		tick = tick.Add(time.Second)
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, 1)
+		require.Equal(t, ownRule, scheduled[0].rule)
+		assertStopRun(t, stopAppliedCh, otherRule.GetKey())
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey())
+
+		require.Contains(t, instanceStore.RecordedOps, models.ListAlertInstancesQuery{RuleOrgID: otherRule.OrgID},
+			"state of the rule must be loaded from the database")
+		for _, op := range instanceStore.RecordedOps {
+			if q, ok := op.(state.FakeInstanceStoreOp); ok {
+				require.NotEqualf(t, "DeleteAlertInstances", q.Name, "state of the rule must not be deleted")
+			}
+		}
+	})
+
+	t.Run("should not load the state of rules of another member again", func(t *testing.T) {
+		instanceStore.RecordedOps = nil
+		tick = tick.Add(time.Second)
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, 1)
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey())
+		for _, op := range instanceStore.RecordedOps {
+			require.NotEqual(t, models.ListAlertInstancesQuery{RuleOrgID: otherRule.OrgID}, op)
+		}
+	})
+
+	t.Run("should update the state of rules of another member with the state it shares", func(t *testing.T) {
+		shared := []models.AlertInstance{{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: otherRule.OrgID, RuleUID: otherRule.UID, LabelsHash: "hash"},
+			Labels:           models.InstanceLabels{"shared": "true"},
+			CurrentState:     models.InstanceStatePending,
+		}}
+		sched.setSharedRuleState(otherRule.GetKey(), shared)
+
+		states := sched.stateManager.GetStatesForRuleUID(otherRule.OrgID, otherRule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Pending, states[0].State)
+		require.Equal(t, "true", states[0].Labels["shared"])
+	})
+
+	t.Run("should ignore the state shared for rules of this instance", func(t *testing.T) {
+		before := sched.stateManager.GetStatesForRuleUID(ownRule.OrgID, ownRule.UID)
+		sched.setSharedRuleState(ownRule.GetKey(), nil)
+		require.Equal(t, before, sched.stateManager.GetStatesForRuleUID(ownRule.OrgID, ownRule.UID))
+	})
+
+	t.Run("should load the state of rules that come back", func(t *testing.T) {
+		membership.set("a", []string{"a"})
+		instanceStore.RecordedOps = nil
+		tick = tick.Add(time.Second)
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, 2)
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey(), otherRule.GetKey())
+		require.Contains(t, instanceStore.RecordedOps, models.ListAlertInstancesQuery{RuleOrgID: otherRule.OrgID, RuleUID: otherRule.UID})
+	})
+}
+
+func TestEvaluationOffset(t *testing.T) {
+	sched := &schedule{}
+	key := models.AlertRuleKey{OrgID: 1, UID: "test"}
+	require.Zero(t, sched.evaluationOffset(key, 10))
+
+	sched.jitterEvaluations = true
+	require.Zero(t, sched.evaluationOffset(key, 1))
+	offsets := map[int64]struct{}{}
+	for i := 0; i < 100; i++ {
+		offset := sched.evaluationOffset(models.AlertRuleKey{OrgID: 1, UID: fmt.Sprintf("rule-%d", i)}, 10)
+		require.GreaterOrEqual(t, offset, int64(0))
+		require.Less(t, offset, int64(10))
+		offsets[offset] = struct{}{}
+	}
+	require.Greater(t, len(offsets), 1, "evaluations should be spread over the interval")
+}
+
+// fakeRuleStateSharing records the state of the last evaluation of every rule.
+type fakeRuleStateSharing struct {
+	mtx    sync.Mutex
+	shared map[models.AlertRuleKey][]models.AlertInstance
+}
+
+func (f *fakeRuleStateSharing) ShareRuleState(key models.AlertRuleKey, instances []models.AlertInstance) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.shared == nil {
+		f.shared = make(map[models.AlertRuleKey][]models.AlertInstance)
+	}
+	f.shared[key] = instances
+}
+
+func (f *fakeRuleStateSharing) OnRuleState(func(key models.AlertRuleKey, instances []models.AlertInstance)) {}
+
+func (f *fakeRuleStateSharing) get(key models.AlertRuleKey) []models.AlertInstance {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.shared[key]
+}
+
+type fakeClusterMembership struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) ClusterMembers() (string, []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.self, f.members
+}
+
+func (f *fakeClusterMembership) set(self string, members []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.self, f.members = self, members
+}
@@ -0,0 +1,442 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/weaveworks/common/http/client"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// Labels the Prometheus backend adds to every series, in addition to the labels of the alert instance.
+// They take precedence over instance labels and external labels with the same name.
+const (
+	PromOrgIDLabel         = "grafana_org_id"
+	PromRuleUIDLabel       = "grafana_rule_uid"
+	PromGroupLabel         = "grafana_group"
+	PromFolderUIDLabel     = "grafana_folder_uid"
+	PromDashboardUIDLabel  = "grafana_dashboard_uid"
+	PromPanelIDLabel       = "grafana_panel_id"
+	PromStateLabel         = "grafana_alertstate"
+	PromPreviousStateLabel = "grafana_previous_alertstate"
+	// PromVarLabel is the name of the variable, such as the ref ID of an expression, of a value series.
+	PromVarLabel = "grafana_var"
+)
+
+// DefaultPrometheusMetricName is the name of the series written by the Prometheus backend.
+const DefaultPrometheusMetricName = "GRAFANA_ALERTS"
+
+// promValueMetricSuffix is appended to the metric name to name the series holding the values of
+// the evaluations that caused state transitions.
+const promValueMetricSuffix = "_VALUE"
+
+var promSystemLabels = []string{
+	model.MetricNameLabel,
+	PromOrgIDLabel,
+	PromRuleUIDLabel,
+	PromGroupLabel,
+	PromFolderUIDLabel,
+	PromDashboardUIDLabel,
+	PromPanelIDLabel,
+	PromStateLabel,
+	PromPreviousStateLabel,
+	PromVarLabel,
+}
+
+type remotePrometheusClient interface {
+	ping(context.Context) error
+	write(context.Context, []prompb.TimeSeries) error
+	query(ctx context.Context, promQL string, at time.Time) (promQueryRes, error)
+}
+
+// RemotePrometheusBackend is a state.Historian that records state history as ALERTS-style series to a
+// Prometheus remote write endpoint, and reads it back with the Prometheus query API.
+//
+// Each state transition is a sample with the value 1 of a series that is labeled with the labels of the
+// alert instance, the rule, and the previous and current state of the transition. The values of the
+// evaluation are samples, at the same time, of series with the same labels and the name of the variable,
+// named after the metric name with the _VALUE suffix.
+type RemotePrometheusBackend struct {
+	client         remotePrometheusClient
+	metricName     string
+	externalLabels map[string]string
+	clock          clock.Clock
+	metrics        *metrics.Historian
+	log            log.Logger
+}
+
+func NewRemotePrometheusBackend(cfg PrometheusConfig, req client.Requester, metrics *metrics.Historian) *RemotePrometheusBackend {
+	logger := log.New("ngalert.state.historian", "backend", "prometheus")
+	metricName := cfg.MetricName
+	if metricName == "" {
+		metricName = DefaultPrometheusMetricName
+	}
+	return &RemotePrometheusBackend{
+		client:         newPrometheusClient(cfg, req, metrics, logger),
+		metricName:     metricName,
+		externalLabels: cfg.ExternalLabels,
+		clock:          clock.New(),
+		metrics:        metrics,
+		log:            logger,
+	}
+}
+
+func (h *RemotePrometheusBackend) TestConnection(ctx context.Context) error {
+	return h.client.ping(ctx)
+}
+
+// Record writes a number of state transitions for a given rule to a Prometheus remote write endpoint.
+func (h *RemotePrometheusBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	series, transitions := statesToTimeSeries(rule, states, h.metricName, h.externalLabels, logger)
+
+	errCh := make(chan error, 1)
+	if len(series) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// See RemoteLokiBackend.Record for the reasons.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "prometheus").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(transitions))
+
+		if err := h.client.write(ctx, series); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "prometheus").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(transitions))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch")
+	}(writeCtx)
+	return errCh
+}
+
+// Query retrieves state history entries from Prometheus and formats the results into a dataframe
+// with the same layout as the one of the Loki backend.
+func (h *RemotePrometheusBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultQueryRange)
+	}
+	if query.From.After(query.To) {
+		return nil, fmt.Errorf("start time cannot be after end time")
+	}
+
+	promQL, err := buildPromQuery(query, h.metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.client.query(ctx, promQL, query.To)
+	if err != nil {
+		return nil, err
+	}
+	return h.promResultToFrame(res, query.From, query.Limit)
+}
+
+// statesToTimeSeries returns the series of the state transitions that should be recorded and of their values,
+// and the number of transitions.
+func statesToTimeSeries(rule history_model.RuleMeta, states []state.StateTransition, metricName string, externalLabels map[string]string, logger log.Logger) ([]prompb.TimeSeries, int) {
+	series := make([]prompb.TimeSeries, 0, len(states))
+	transitions := 0
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+		transitions++
+
+		labels := make(map[string]string)
+		for k, v := range removePrivateLabels(state.Labels) {
+			labels[sanitizePromLabelName(k)] = v
+		}
+		for k, v := range externalLabels {
+			labels[sanitizePromLabelName(k)] = v
+		}
+		// System-defined labels take precedence over instance and external labels.
+		labels[model.MetricNameLabel] = metricName
+		labels[PromOrgIDLabel] = fmt.Sprint(rule.OrgID)
+		labels[PromRuleUIDLabel] = rule.UID
+		labels[PromGroupLabel] = rule.Group
+		labels[PromFolderUIDLabel] = rule.NamespaceUID
+		if rule.DashboardUID != "" {
+			labels[PromDashboardUIDLabel] = rule.DashboardUID
+			labels[PromPanelIDLabel] = fmt.Sprint(rule.PanelID)
+		}
+		labels[PromStateLabel] = state.Formatted()
+		labels[PromPreviousStateLabel] = state.PreviousFormatted()
+		// An instance label named like the variable label would make the value series collide.
+		delete(labels, PromVarLabel)
+
+		ts := state.State.LastEvaluationTime.UnixMilli()
+		series = append(series, prompb.TimeSeries{
+			Labels:  toPromLabels(labels),
+			Samples: []prompb.Sample{{Value: 1, Timestamp: ts}},
+		})
+
+		if state.State.State != eval.Error && state.State.State != eval.NoData {
+			vars := make([]string, 0, len(state.State.Values))
+			for k := range state.State.Values {
+				vars = append(vars, k)
+			}
+			sort.Strings(vars)
+			labels[model.MetricNameLabel] = metricName + promValueMetricSuffix
+			for _, v := range vars {
+				labels[PromVarLabel] = v
+				series = append(series, prompb.TimeSeries{
+					Labels:  toPromLabels(labels),
+					Samples: []prompb.Sample{{Value: state.State.Values[v], Timestamp: ts}},
+				})
+			}
+		}
+		logger.Debug("Alert state changed", "newState", state.Formatted(), "oldState", state.PreviousFormatted())
+	}
+	return series, transitions
+}
+
+// toPromLabels returns the non-empty labels sorted by name, as expected by remote write.
+func toPromLabels(labels map[string]string) []prompb.Label {
+	promLabels := make([]prompb.Label, 0, len(labels))
+	for k, v := range labels {
+		if v == "" {
+			// Prometheus treats empty labels as missing.
+			continue
+		}
+		promLabels = append(promLabels, prompb.Label{Name: k, Value: v})
+	}
+	sort.Slice(promLabels, func(i, j int) bool {
+		return promLabels[i].Name < promLabels[j].Name
+	})
+	return promLabels
+}
+
+// buildPromQuery returns a range vector selector of all the transition and value samples that match the
+// query in the time range of the query, when evaluated at the end of the time range.
+func buildPromQuery(query models.HistoryQuery, metricName string) (string, error) {
+	matchers := []string{
+		fmt.Sprintf("%s=~%q", model.MetricNameLabel, regexp.QuoteMeta(metricName)+"("+promValueMetricSuffix+")?"),
+		fmt.Sprintf("%s=%q", PromOrgIDLabel, fmt.Sprint(query.OrgID)),
+	}
+	if query.RuleUID != "" {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", PromRuleUIDLabel, query.RuleUID))
+	}
+	if query.DashboardUID != "" {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", PromDashboardUIDLabel, query.DashboardUID))
+	}
+	if query.PanelID != 0 {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", PromPanelIDLabel, fmt.Sprint(query.PanelID)))
+	}
+
+	labelKeys := make([]string, 0, len(query.Labels))
+	for k := range query.Labels {
+		labelKeys = append(labelKeys, k)
+	}
+	// Ensure that all queries we build are deterministic.
+	sort.Strings(labelKeys)
+	for _, k := range labelKeys {
+		if !model.LabelName(k).IsValid() {
+			return "", fmt.Errorf("invalid label name %q", k)
+		}
+		matchers = append(matchers, fmt.Sprintf("%s=%q", k, query.Labels[k]))
+	}
+
+	// The range is rounded up to the next second, samples before the start of the query are filtered out later.
+	rangeSeconds := int64((query.To.Sub(query.From) + time.Second - 1) / time.Second)
+	if rangeSeconds < 1 {
+		rangeSeconds = 1
+	}
+	return fmt.Sprintf("{%s}[%ds]", strings.Join(matchers, ","), rangeSeconds), nil
+}
+
+// promResultToFrame merges all the transition samples of the result in one history sorted by timestamp, with
+// the values sampled at the same time. If there are more samples than limit, the most recent ones are kept.
+func (h *RemotePrometheusBackend) promResultToFrame(res promQueryRes, from time.Time, limit int) (*data.Frame, error) {
+	type entry struct {
+		t      time.Time
+		line   json.RawMessage
+		labels json.RawMessage
+	}
+
+	// Values of a transition, by the labels of the transition series and the time of the transition.
+	values := make(map[string]map[time.Time]map[string]float64)
+	valueMetricName := h.metricName + promValueMetricSuffix
+	for _, series := range res.Data.Result {
+		if series.Metric[model.MetricNameLabel] != valueMetricName {
+			continue
+		}
+		key := transitionKey(series.Metric)
+		if values[key] == nil {
+			values[key] = make(map[time.Time]map[string]float64)
+		}
+		for _, s := range series.Values {
+			v, err := strconv.ParseFloat(s.V, 64)
+			if err != nil {
+				h.log.Warn("Skipping invalid value in state history", "value", s.V, "error", err)
+				continue
+			}
+			if values[key][s.T] == nil {
+				values[key][s.T] = make(map[string]float64)
+			}
+			values[key][s.T][series.Metric[PromVarLabel]] = v
+		}
+	}
+
+	var entries []entry
+	for _, series := range res.Data.Result {
+		if series.Metric[model.MetricNameLabel] == valueMetricName {
+			continue
+		}
+		streamLabels, instanceLabels := h.splitLabels(series.Metric)
+		lblsJson, err := json.Marshal(streamLabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize labels: %w", err)
+		}
+		seriesValues := values[transitionKey(series.Metric)]
+		for _, s := range series.Values {
+			if s.T.Before(from) {
+				continue
+			}
+			entryValues := jsonifyValues(seriesValues[s.T])
+			if entryValues == nil {
+				entryValues = simplejson.New()
+			}
+			line, err := json.Marshal(lokiEntry{
+				SchemaVersion:  1,
+				Previous:       series.Metric[PromPreviousStateLabel],
+				Current:        series.Metric[PromStateLabel],
+				Values:         entryValues,
+				DashboardUID:   series.Metric[PromDashboardUIDLabel],
+				PanelID:        parsePanelID(series.Metric[PromPanelIDLabel]),
+				Fingerprint:    labelFingerprint(instanceLabels),
+				RuleUID:        series.Metric[PromRuleUIDLabel],
+				InstanceLabels: instanceLabels,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize entry: %w", err)
+			}
+			entries = append(entries, entry{t: s.T, line: line, labels: lblsJson})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].t.Before(entries[j].t)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		times = append(times, e.t)
+		lines = append(lines, e.line)
+		labels = append(labels, e.labels)
+	}
+
+	lbls := data.Labels(map[string]string{})
+	frame := data.NewFrame("states")
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+// transitionKey identifies the transition series of a transition or value series by its labels,
+// which are the same except for the metric name and the variable.
+func transitionKey(metric map[string]string) string {
+	labels := make(data.Labels, len(metric))
+	for k, v := range metric {
+		if k == model.MetricNameLabel || k == PromVarLabel {
+			continue
+		}
+		labels[k] = v
+	}
+	return labels.String()
+}
+
+// splitLabels splits the labels of a series in the labels that identify the rule, named like the labels of
+// the streams of the Loki backend, and the labels of the alert instance.
+func (h *RemotePrometheusBackend) splitLabels(metric map[string]string) (map[string]string, data.Labels) {
+	stream := map[string]string{
+		StateHistoryLabelKey: StateHistoryLabelValue,
+		OrgIDLabel:           metric[PromOrgIDLabel],
+		GroupLabel:           metric[PromGroupLabel],
+		FolderUIDLabel:       metric[PromFolderUIDLabel],
+	}
+	instance := make(data.Labels, len(metric))
+	for k, v := range metric {
+		instance[k] = v
+	}
+	for _, k := range promSystemLabels {
+		delete(instance, k)
+	}
+	for k, v := range h.externalLabels {
+		k = sanitizePromLabelName(k)
+		if instance[k] == v {
+			delete(instance, k)
+			stream[k] = v
+		}
+	}
+	return stream, instance
+}
+
+func parsePanelID(s string) int64 {
+	if s == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// sanitizePromLabelName replaces the characters that are not allowed in Prometheus label names with underscores.
+func sanitizePromLabelName(name string) string {
+	if model.LabelName(name).IsValid() {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteRune('_')
+	}
+	return b.String()
+}
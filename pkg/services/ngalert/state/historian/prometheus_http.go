@@ -0,0 +1,243 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/weaveworks/common/http/client"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type PrometheusConfig struct {
+	// QueryURL is the URL of the Prometheus HTTP API, without the /api/v1 suffix.
+	QueryURL *url.URL
+	// WriteURL is the full URL of the remote write endpoint, e.g. http://prometheus:9090/api/v1/write.
+	WriteURL          *url.URL
+	BasicAuthUser     string
+	BasicAuthPassword string
+	TenantID          string
+	MetricName        string
+	ExternalLabels    map[string]string
+}
+
+func NewPrometheusConfig(cfg setting.UnifiedAlertingStateHistorySettings) (PrometheusConfig, error) {
+	if cfg.PrometheusQueryURL == "" {
+		return PrometheusConfig{}, fmt.Errorf("prometheus query URL must be provided")
+	}
+	if cfg.PrometheusWriteURL == "" {
+		return PrometheusConfig{}, fmt.Errorf("prometheus remote write URL must be provided")
+	}
+
+	queryURL, err := url.Parse(cfg.PrometheusQueryURL)
+	if err != nil {
+		return PrometheusConfig{}, fmt.Errorf("failed to parse prometheus query URL: %w", err)
+	}
+	writeURL, err := url.Parse(cfg.PrometheusWriteURL)
+	if err != nil {
+		return PrometheusConfig{}, fmt.Errorf("failed to parse prometheus remote write URL: %w", err)
+	}
+
+	return PrometheusConfig{
+		QueryURL:          queryURL,
+		WriteURL:          writeURL,
+		BasicAuthUser:     cfg.PrometheusBasicAuthUsername,
+		BasicAuthPassword: cfg.PrometheusBasicAuthPassword,
+		TenantID:          cfg.PrometheusTenantID,
+		MetricName:        cfg.PrometheusMetricName,
+		ExternalLabels:    cfg.ExternalLabels,
+	}, nil
+}
+
+type httpPrometheusClient struct {
+	client  client.Requester
+	cfg     PrometheusConfig
+	metrics *metrics.Historian
+	log     log.Logger
+}
+
+func newPrometheusClient(cfg PrometheusConfig, req client.Requester, metrics *metrics.Historian, logger log.Logger) *httpPrometheusClient {
+	tc := client.NewTimedClient(req, metrics.WriteDuration)
+	return &httpPrometheusClient{
+		client:  tc,
+		cfg:     cfg,
+		metrics: metrics,
+		log:     logger.New("protocol", "http"),
+	}
+}
+
+func (c *httpPrometheusClient) ping(ctx context.Context) error {
+	uri := c.cfg.QueryURL.JoinPath("/api/v1/status/buildinfo")
+	req, err := http.NewRequest(http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	c.setAuthAndTenantHeaders(req)
+
+	req = req.WithContext(ctx)
+	res, err := c.client.Do(req)
+	if res != nil {
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("ping request to prometheus endpoint returned a non-200 status code: %d", res.StatusCode)
+	}
+	c.log.Debug("Ping request to Prometheus endpoint succeeded", "status", res.StatusCode)
+	return nil
+}
+
+func (c *httpPrometheusClient) write(ctx context.Context, series []prompb.TimeSeries) error {
+	raw, err := proto.Marshal(&prompb.WriteRequest{Timeseries: series})
+	if err != nil {
+		return fmt.Errorf("failed to serialize remote write payload: %w", err)
+	}
+	enc := snappy.Encode(nil, raw)
+
+	req, err := http.NewRequest(http.MethodPost, c.cfg.WriteURL.String(), bytes.NewReader(enc))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	c.setAuthAndTenantHeaders(req)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	c.metrics.BytesWritten.Add(float64(len(enc)))
+	req = req.WithContext(ctx)
+	resp, err := c.client.Do(req)
+	if resp != nil {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		byt, _ := io.ReadAll(resp.Body)
+		if len(byt) > 0 {
+			c.log.Error("Error response from Prometheus", "response", string(byt), "status", resp.StatusCode)
+		} else {
+			c.log.Error("Error response from Prometheus with an empty body", "status", resp.StatusCode)
+		}
+		return fmt.Errorf("received a non-200 response from prometheus, status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *httpPrometheusClient) query(ctx context.Context, promQL string, at time.Time) (promQueryRes, error) {
+	queryURL := c.cfg.QueryURL.JoinPath("/api/v1/query")
+	values := url.Values{}
+	values.Set("query", promQL)
+	values.Set("time", strconv.FormatFloat(float64(at.UnixMilli())/1000, 'f', -1, 64))
+	queryURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return promQueryRes{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	c.setAuthAndTenantHeaders(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return promQueryRes{}, fmt.Errorf("error executing request: %w", err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return promQueryRes{}, fmt.Errorf("error reading request response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		if len(data) > 0 {
+			c.log.Error("Error response from Prometheus", "response", string(data), "status", res.StatusCode)
+		} else {
+			c.log.Error("Error response from Prometheus with an empty body", "status", res.StatusCode)
+		}
+		return promQueryRes{}, fmt.Errorf("received a non-200 response from prometheus, status: %d", res.StatusCode)
+	}
+
+	result := promQueryRes{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return promQueryRes{}, fmt.Errorf("error parsing request response: %w", err)
+	}
+	if result.Data.ResultType != "matrix" {
+		return promQueryRes{}, fmt.Errorf("unexpected result type from prometheus: %q", result.Data.ResultType)
+	}
+	return result, nil
+}
+
+func (c *httpPrometheusClient) setAuthAndTenantHeaders(req *http.Request) {
+	if c.cfg.BasicAuthUser != "" || c.cfg.BasicAuthPassword != "" {
+		req.SetBasicAuth(c.cfg.BasicAuthUser, c.cfg.BasicAuthPassword)
+	}
+
+	if c.cfg.TenantID != "" {
+		req.Header.Add("X-Scope-OrgID", c.cfg.TenantID)
+	}
+}
+
+type promQueryRes struct {
+	Data promQueryData `json:"data"`
+}
+
+type promQueryData struct {
+	ResultType string       `json:"resultType"`
+	Result     []promSeries `json:"result"`
+}
+
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values []promSample      `json:"values"`
+}
+
+type promSample struct {
+	T time.Time
+	V string
+}
+
+func (s *promSample) UnmarshalJSON(b []byte) error {
+	// A Prometheus sample is formatted like a list with two elements, [At, Val]
+	// At is a unix timestamp in seconds, with a decimal part.
+	// Val is a string containing the value.
+	var tuple [2]json.RawMessage
+	if err := json.Unmarshal(b, &tuple); err != nil {
+		return fmt.Errorf("failed to deserialize sample in Prometheus response: %w", err)
+	}
+	var ts float64
+	if err := json.Unmarshal(tuple[0], &ts); err != nil {
+		return fmt.Errorf("timestamp in Prometheus sample is not a number: %s", tuple[0])
+	}
+	if err := json.Unmarshal(tuple[1], &s.V); err != nil {
+		return fmt.Errorf("value in Prometheus sample is not a string: %s", tuple[1])
+	}
+	s.T = time.UnixMilli(int64(ts*1000 + 0.5))
+	return nil
+}
@@ -0,0 +1,319 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/http/client"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusConfig(t *testing.T) {
+	t.Run("requires both URLs", func(t *testing.T) {
+		_, err := NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{PrometheusWriteURL: "http://prometheus/api/v1/write"})
+		require.ErrorContains(t, err, "query URL")
+
+		_, err = NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{PrometheusQueryURL: "http://prometheus"})
+		require.ErrorContains(t, err, "remote write URL")
+	})
+
+	t.Run("captures settings", func(t *testing.T) {
+		cfg, err := NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{
+			PrometheusQueryURL:          "http://prometheus",
+			PrometheusWriteURL:          "http://prometheus/api/v1/write",
+			PrometheusTenantID:          "tenant",
+			PrometheusBasicAuthUsername: "user",
+			PrometheusBasicAuthPassword: "pass",
+			PrometheusMetricName:        "ALERTS_HISTORY",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "http://prometheus/api/v1/write", cfg.WriteURL.String())
+		require.Equal(t, "tenant", cfg.TenantID)
+		require.Equal(t, "user", cfg.BasicAuthUser)
+		require.Equal(t, "pass", cfg.BasicAuthPassword)
+		require.Equal(t, "ALERTS_HISTORY", cfg.MetricName)
+	})
+}
+
+func TestStatesToTimeSeries(t *testing.T) {
+	t.Run("skips non-transitory states", func(t *testing.T) {
+		res, transitions := statesToTimeSeries(createTestRule(), singleFromNormal(&state.State{State: eval.Normal}), DefaultPrometheusMetricName, nil, log.NewNopLogger())
+
+		require.Empty(t, res)
+		require.Zero(t, transitions)
+	})
+
+	t.Run("produces expected labels", func(t *testing.T) {
+		rule := createTestRule()
+		now := time.Unix(1700000000, 0)
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b", "dotted.label": "c", "__private__": "d", "grafana_rule_uid": "spoofed"},
+			LastEvaluationTime: now,
+		})
+
+		res, transitions := statesToTimeSeries(rule, states, DefaultPrometheusMetricName, map[string]string{"cluster": "eu"}, log.NewNopLogger())
+
+		require.Len(t, res, 1)
+		require.Equal(t, 1, transitions)
+		labels := map[string]string{}
+		for _, l := range res[0].Labels {
+			labels[l.Name] = l.Value
+		}
+		require.Equal(t, map[string]string{
+			"__name__":             DefaultPrometheusMetricName,
+			"a":                    "b",
+			"dotted_label":         "c",
+			"cluster":              "eu",
+			PromOrgIDLabel:         "1",
+			PromRuleUIDLabel:       rule.UID,
+			PromGroupLabel:         rule.Group,
+			PromFolderUIDLabel:     rule.NamespaceUID,
+			PromDashboardUIDLabel:  rule.DashboardUID,
+			PromPanelIDLabel:       "123",
+			PromStateLabel:         "Alerting",
+			PromPreviousStateLabel: "Normal",
+		}, labels)
+		require.Equal(t, []prompb.Sample{{Value: 1, Timestamp: now.UnixMilli()}}, res[0].Samples)
+	})
+
+	t.Run("writes a value series per variable", func(t *testing.T) {
+		now := time.Unix(1700000000, 0)
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b"},
+			Values:             map[string]float64{"B": 2, "A": 1.5},
+			LastEvaluationTime: now,
+		})
+
+		res, transitions := statesToTimeSeries(createTestRule(), states, DefaultPrometheusMetricName, nil, log.NewNopLogger())
+
+		require.Len(t, res, 3)
+		require.Equal(t, 1, transitions)
+		for i, v := range []struct {
+			name  string
+			value float64
+		}{{"A", 1.5}, {"B", 2}} {
+			labels := map[string]string{}
+			for _, l := range res[i+1].Labels {
+				labels[l.Name] = l.Value
+			}
+			require.Equal(t, DefaultPrometheusMetricName+"_VALUE", labels["__name__"])
+			require.Equal(t, v.name, labels[PromVarLabel])
+			require.Equal(t, "Alerting", labels[PromStateLabel])
+			require.Equal(t, "b", labels["a"])
+			require.Equal(t, []prompb.Sample{{Value: v.value, Timestamp: now.UnixMilli()}}, res[i+1].Samples)
+		}
+	})
+
+	t.Run("does not write values of errors", func(t *testing.T) {
+		states := singleFromNormal(&state.State{
+			State:  eval.Error,
+			Values: map[string]float64{"A": 1},
+		})
+
+		res, _ := statesToTimeSeries(createTestRule(), states, DefaultPrometheusMetricName, nil, log.NewNopLogger())
+
+		require.Len(t, res, 1)
+	})
+}
+
+func TestBuildPromQuery(t *testing.T) {
+	to := time.Unix(1700000000, 0)
+	q, err := buildPromQuery(models.HistoryQuery{
+		OrgID:        1,
+		RuleUID:      "rule-uid",
+		DashboardUID: "dash-uid",
+		PanelID:      2,
+		Labels:       map[string]string{"b": "2", "a": "1"},
+		From:         to.Add(-90 * time.Minute),
+		To:           to,
+	}, DefaultPrometheusMetricName)
+	require.NoError(t, err)
+	require.Equal(t, `{__name__=~"GRAFANA_ALERTS(_VALUE)?",grafana_org_id="1",grafana_rule_uid="rule-uid",grafana_dashboard_uid="dash-uid",grafana_panel_id="2",a="1",b="2"}[5400s]`, q)
+
+	_, err = buildPromQuery(models.HistoryQuery{OrgID: 1, Labels: map[string]string{"not valid": "x"}, From: to.Add(-time.Hour), To: to}, DefaultPrometheusMetricName)
+	require.Error(t, err)
+}
+
+func TestRemotePrometheusBackend(t *testing.T) {
+	t.Run("writes state transitions with remote write", func(t *testing.T) {
+		req := NewFakeRequester()
+		backend := createTestPrometheusBackend(req, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		states := singleFromNormal(&state.State{
+			State:  eval.Alerting,
+			Labels: data.Labels{"a": "b"},
+		})
+
+		err := <-backend.Record(context.Background(), createTestRule(), states)
+
+		require.NoError(t, err)
+		require.Equal(t, "/api/v1/write", req.lastRequest.URL.Path)
+		require.Equal(t, "snappy", req.lastRequest.Header.Get("Content-Encoding"))
+		require.Equal(t, "tenant", req.lastRequest.Header.Get("X-Scope-OrgID"))
+
+		decoded, err := snappy.Decode(nil, readBody(t, req.lastRequest))
+		require.NoError(t, err)
+		var wr prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(decoded, &wr))
+		require.Len(t, wr.Timeseries, 1)
+	})
+
+	t.Run("reports write failures", func(t *testing.T) {
+		backend := createTestPrometheusBackend(NewFakeRequester().WithResponse(badResponse()), metrics.NewHistorianMetrics(prometheus.NewRegistry())) //nolint:bodyclose
+		states := singleFromNormal(&state.State{State: eval.Alerting})
+
+		err := <-backend.Record(context.Background(), createTestRule(), states)
+
+		require.Error(t, err)
+	})
+
+	t.Run("queries state history as a merged frame", func(t *testing.T) {
+		req := NewFakeRequester().WithResponse(jsonResponse(`{
+			"status": "success",
+			"data": {
+				"resultType": "matrix",
+				"result": [
+					{
+						"metric": {"__name__": "GRAFANA_ALERTS", "a": "b", "cluster": "eu", "grafana_org_id": "1", "grafana_rule_uid": "rule-uid", "grafana_group": "my-group", "grafana_folder_uid": "my-folder", "grafana_alertstate": "Alerting", "grafana_previous_alertstate": "Normal"},
+						"values": [[1700000030, "1"], [1700000090.5, "1"]]
+					},
+					{
+						"metric": {"__name__": "GRAFANA_ALERTS", "a": "b", "cluster": "eu", "grafana_org_id": "1", "grafana_rule_uid": "rule-uid", "grafana_group": "my-group", "grafana_folder_uid": "my-folder", "grafana_alertstate": "Normal", "grafana_previous_alertstate": "Alerting"},
+						"values": [[1700000060, "1"], [1699990000, "1"]]
+					}
+				]
+			}
+		}`))
+		backend := createTestPrometheusBackend(req, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{
+			OrgID:   1,
+			RuleUID: "rule-uid",
+			From:    time.Unix(1700000000, 0),
+			To:      time.Unix(1700000100, 0),
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "/api/v1/query", req.lastRequest.URL.Path)
+		require.Equal(t, "1700000100", req.lastRequest.URL.Query().Get("time"))
+		require.Equal(t, 3, frame.Rows(), "samples before the start of the query are dropped")
+
+		times := frame.Fields[0]
+		require.Equal(t, time.Unix(1700000030, 0), times.At(0))
+		require.Equal(t, time.Unix(1700000060, 0), times.At(1))
+		require.Equal(t, time.UnixMilli(1700000090500), times.At(2))
+
+		entry := requireEntry(t, sample{V: string(frame.Fields[1].At(1).(json.RawMessage))})
+		require.Equal(t, "Alerting", entry.Previous)
+		require.Equal(t, "Normal", entry.Current)
+		require.Equal(t, "rule-uid", entry.RuleUID)
+		require.Equal(t, map[string]string{"a": "b"}, entry.InstanceLabels)
+		require.JSONEq(t, `{"from":"state-history","orgID":"1","group":"my-group","folderUID":"my-folder","cluster":"eu"}`, string(frame.Fields[2].At(1).(json.RawMessage)))
+	})
+
+	t.Run("reads back the values of saved state transitions", func(t *testing.T) {
+		writeReq := NewFakeRequester()
+		backend := createTestPrometheusBackend(writeReq, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b"},
+			Values:             map[string]float64{"A": 1.5, "B": math.Inf(1)},
+			LastEvaluationTime: time.Unix(1700000030, 0),
+		})
+		require.NoError(t, <-backend.Record(context.Background(), createTestRule(), states))
+
+		decoded, err := snappy.Decode(nil, readBody(t, writeReq.lastRequest))
+		require.NoError(t, err)
+		var wr prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(decoded, &wr))
+		require.Len(t, wr.Timeseries, 3)
+		result := make([]any, 0, len(wr.Timeseries))
+		for _, ts := range wr.Timeseries {
+			metric := map[string]string{}
+			for _, l := range ts.Labels {
+				metric[l.Name] = l.Value
+			}
+			value := strconv.FormatFloat(ts.Samples[0].Value, 'f', -1, 64)
+			result = append(result, map[string]any{"metric": metric, "values": []any{[]any{1700000030, value}}})
+		}
+		body, err := json.Marshal(map[string]any{
+			"data": map[string]any{"resultType": "matrix", "result": result},
+		})
+		require.NoError(t, err)
+
+		queryReq := NewFakeRequester().WithResponse(jsonResponse(string(body)))
+		backend = createTestPrometheusBackend(queryReq, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{
+			OrgID: 1,
+			From:  time.Unix(1700000000, 0),
+			To:    time.Unix(1700000100, 0),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+
+		entry := requireEntry(t, sample{V: string(frame.Fields[1].At(0).(json.RawMessage))})
+		require.Equal(t, 1.5, entry.Values.Get("A").MustFloat64())
+		require.Equal(t, "+Inf", entry.Values.Get("B").MustString())
+		require.Equal(t, map[string]string{"a": "b"}, entry.InstanceLabels)
+	})
+
+	t.Run("keeps the most recent entries when limited", func(t *testing.T) {
+		req := NewFakeRequester().WithResponse(jsonResponse(`{"data": {"resultType": "matrix", "result": [
+			{"metric": {"grafana_alertstate": "Alerting"}, "values": [[1700000010, "1"], [1700000020, "1"], [1700000030, "1"]]}
+		]}}`))
+		backend := createTestPrometheusBackend(req, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{
+			OrgID: 1,
+			From:  time.Unix(1700000000, 0),
+			To:    time.Unix(1700000100, 0),
+			Limit: 2,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(1700000020, 0), frame.Fields[0].At(0))
+	})
+}
+
+func createTestPrometheusBackend(req client.Requester, met *metrics.Historian) *RemotePrometheusBackend {
+	u, _ := url.Parse("http://some.url")
+	cfg := PrometheusConfig{
+		QueryURL:       u,
+		WriteURL:       u.JoinPath("/api/v1/write"),
+		TenantID:       "tenant",
+		ExternalLabels: map[string]string{"cluster": "eu"},
+	}
+	return NewRemotePrometheusBackend(cfg, req, met)
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Header:        make(http.Header, 0),
+	}
+}
@@ -153,24 +153,8 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
+			s := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
@@ -178,6 +162,116 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// WarmRule replaces the cached states of the rule with the states saved in the instance store.
+// It is used when the rule was evaluated by another Grafana instance until now.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.FromContext(ctx)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+	rs := &ruleStates{states: make(map[string]*State, len(alertInstances))}
+	for _, entry := range alertInstances {
+		s := st.stateFromInstance(entry, rule)
+		rs.states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.GetKey(), rs)
+	logger.Debug("State of the rule has been loaded", "states", len(rs.states))
+}
+
+// SetRuleInstances replaces the cached states of the rule with the given instances. It is used for the rules that
+// are evaluated by other Grafana instances, which share the state of a rule every time they evaluate it.
+func (st *Manager) SetRuleInstances(rule *ngModels.AlertRule, instances []ngModels.AlertInstance) {
+	rs := &ruleStates{states: make(map[string]*State, len(instances))}
+	for i := range instances {
+		s := st.stateFromInstance(&instances[i], rule)
+		rs.states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.GetKey(), rs)
+}
+
+// RuleInstances returns the cached states of the rule as the alert instances saved in the instance store.
+func (st *Manager) RuleInstances(ruleKey ngModels.AlertRuleKey) []ngModels.AlertInstance {
+	states := st.cache.getStatesForRuleUID(ruleKey.OrgID, ruleKey.UID, false)
+	instances := make([]ngModels.AlertInstance, 0, len(states))
+	for _, s := range states {
+		instance, err := instanceFromState(s)
+		if err != nil {
+			st.log.Error("Failed to create a key for alert state", append(ruleKey.LogContext(), "cacheID", s.CacheID, "error", err)...)
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+// WarmRules replaces the cached states of the rules of the organization with the states saved in the instance store.
+// It is used for the rules that are evaluated by other Grafana instances when their owner changes, until the owner
+// shares their state with SetRuleInstances.
+func (st *Manager) WarmRules(ctx context.Context, orgID int64, rules []*ngModels.AlertRule) {
+	if st.instanceStore == nil || len(rules) == 0 {
+		return
+	}
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: orgID,
+	})
+	if err != nil {
+		st.log.FromContext(ctx).Error("Unable to fetch the state of rules evaluated by other instances", "org", orgID, "error", err)
+		return
+	}
+
+	byUID := make(map[string]*ruleStates, len(rules))
+	rulesByUID := make(map[string]*ngModels.AlertRule, len(rules))
+	for _, rule := range rules {
+		byUID[rule.UID] = &ruleStates{states: make(map[string]*State)}
+		rulesByUID[rule.UID] = rule
+	}
+	for _, entry := range alertInstances {
+		rs, ok := byUID[entry.RuleUID]
+		if !ok {
+			continue
+		}
+		s := st.stateFromInstance(entry, rulesByUID[entry.RuleUID])
+		rs.states[s.CacheID] = s
+	}
+	for uid, rs := range byUID {
+		st.cache.setRuleStates(ngModels.AlertRuleKey{OrgID: orgID, UID: uid}, rs)
+	}
+}
+
+// ForgetRule removes the states of the rule from the cache without changing the instance store and without
+// recording state history. It is used when the rule is evaluated by another Grafana instance from now on.
+func (st *Manager) ForgetRule(ruleKey ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("Error getting cacheId for entry", "error", err)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          rule.Annotations,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -445,20 +539,11 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			return nil
 		}
 
-		key, err := s.GetAlertInstanceKey()
+		instance, err := instanceFromState(s.State)
 		if err != nil {
 			logger.Error("Failed to create a key for alert state to save it to database. The state will be ignored ", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
 			return nil
 		}
-		instance := ngModels.AlertInstance{
-			AlertInstanceKey:  key,
-			Labels:            ngModels.InstanceLabels(s.Labels),
-			CurrentState:      ngModels.InstanceStateType(s.State.State.String()),
-			CurrentReason:     s.StateReason,
-			LastEvalTime:      s.LastEvaluationTime,
-			CurrentStateSince: s.StartsAt,
-			CurrentStateEnd:   s.EndsAt,
-		}
 
 		err = st.instanceStore.SaveAlertInstance(ctx, instance)
 		if err != nil {
@@ -474,6 +559,23 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 	logger.Debug("Saving alert states done", "count", len(states), "max_state_save_concurrency", st.maxStateSaveConcurrency, "duration", time.Since(start))
 }
 
+// instanceFromState returns the alert instance that represents the state in the instance store.
+func instanceFromState(s *State) (ngModels.AlertInstance, error) {
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return ngModels.AlertInstance{}, err
+	}
+	return ngModels.AlertInstance{
+		AlertInstanceKey:  key,
+		Labels:            ngModels.InstanceLabels(s.Labels),
+		CurrentState:      ngModels.InstanceStateType(s.State.String()),
+		CurrentReason:     s.StateReason,
+		LastEvalTime:      s.LastEvaluationTime,
+		CurrentStateSince: s.StartsAt,
+		CurrentStateEnd:   s.EndsAt,
+	}, nil
+}
+
 func (st *Manager) deleteAlertStates(ctx context.Context, logger log.Logger, states []StateTransition) {
 	if st.instanceStore == nil || len(states) == 0 {
 		return
@@ -47,6 +47,10 @@ func ProvideConfig(settingProvider setting.Provider, grafanaCfg *setting.Cfg, fe
 		grafanaCfg.AngularSupportEnabled,
 		grafanaCfg.GrafanaComURL,
 		grafanaCfg.DisablePlugins,
+		grafanaCfg.PluginProcessCgroupPath,
+		grafanaCfg.PluginRepositoryMirror,
+		grafanaCfg.PluginHotReload,
+		grafanaCfg.PluginHotReloadInterval,
 	), nil
 }
 
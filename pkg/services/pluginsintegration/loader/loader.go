@@ -33,3 +33,7 @@ func (l *Loader) Load(ctx context.Context, src plugins.PluginSource) ([]*plugins
 func (l *Loader) Unload(ctx context.Context, p *plugins.Plugin) (*plugins.Plugin, error) {
 	return l.loader.Unload(ctx, p)
 }
+
+func (l *Loader) Reload(ctx context.Context, src plugins.PluginSource, loaded []*plugins.Plugin) ([]*plugins.Plugin, error) {
+	return l.loader.Reload(ctx, src, loaded)
+}
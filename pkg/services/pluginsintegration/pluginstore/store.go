@@ -2,7 +2,9 @@ package pluginstore
 
 import (
 	"context"
+	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +28,9 @@ type Store interface {
 type Service struct {
 	pluginRegistry registry.Service
 	pluginLoader   loader.Service
+	// watchers are the plugin sources whose changes are loaded while the service is running.
+	watchers []sources.Watcher
+	log      log.Logger
 }
 
 func ProvideService(pluginRegistry registry.Service, pluginSources sources.Registry,
@@ -36,6 +41,7 @@ func ProvideService(pluginRegistry registry.Service, pluginSources sources.Regis
 	logger := log.New("plugin.store")
 	logger.Info("Loading plugins...")
 
+	var watchers []sources.Watcher
 	for _, ps := range pluginSources.List(ctx) {
 		loadedPlugins, err := pluginLoader.Load(ctx, ps)
 		if err != nil {
@@ -44,15 +50,32 @@ func ProvideService(pluginRegistry registry.Service, pluginSources sources.Regis
 		}
 
 		totalPlugins += len(loadedPlugins)
+		if w, ok := ps.(sources.Watcher); ok {
+			watchers = append(watchers, w)
+		}
 	}
 
 	logger.Info("Plugins loaded", "count", totalPlugins, "duration", time.Since(start))
 
-	return New(pluginRegistry, pluginLoader), nil
+	s := New(pluginRegistry, pluginLoader)
+	s.watchers = watchers
+	return s, nil
 }
 
 func (s *Service) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, w := range s.watchers {
+		wg.Add(1)
+		go func(w sources.Watcher) {
+			defer wg.Done()
+			w.Watch(ctx, func(ctx context.Context, changes []sources.PluginDirChange) {
+				s.reload(ctx, w.PluginClass(ctx), changes)
+			})
+		}(w)
+	}
+
 	<-ctx.Done()
+	wg.Wait()
 	s.shutdown(ctx)
 	return ctx.Err()
 }
@@ -61,6 +84,7 @@ func New(pluginRegistry registry.Service, pluginLoader loader.Service) *Service
 	return &Service{
 		pluginRegistry: pluginRegistry,
 		pluginLoader:   pluginLoader,
+		log:            log.New("plugin.store"),
 	}
 }
 
@@ -152,6 +176,68 @@ func (s *Service) Routes(ctx context.Context) []*plugins.StaticRoute {
 	return staticRoutes
 }
 
+// reload loads the plugins of the plugin directories which were added or changed, and unloads the plugins
+// previously loaded from the directories which were removed. The plugins of a changed directory are only
+// unloaded once their new version is validated, so that a broken update keeps the running version.
+// Unloading a plugin decommissions it and stops its backend process.
+func (s *Service) reload(ctx context.Context, class plugins.Class, changes []sources.PluginDirChange) {
+	for _, c := range changes {
+		logger := s.log.New("path", c.Path, "change", c.Op)
+		loaded := s.pluginsInDir(ctx, c.Path)
+		if c.Op == sources.PluginDirAdded && len(loaded) > 0 {
+			// plugins installed with the plugin installer are loaded already
+			continue
+		}
+
+		if c.Op == sources.PluginDirRemoved {
+			for _, p := range loaded {
+				if _, err := s.pluginLoader.Unload(ctx, p); err != nil {
+					logger.Error("Failed to unload plugin", "pluginId", p.ID, "error", err)
+					continue
+				}
+				logger.Info("Plugin unloaded", "pluginId", p.ID, "version", p.Info.Version)
+			}
+			continue
+		}
+
+		src := sources.NewLocalSource(class, []string{c.Path})
+		var reloaded []*plugins.Plugin
+		var err error
+		if len(loaded) == 0 {
+			reloaded, err = s.pluginLoader.Load(ctx, src)
+		} else {
+			reloaded, err = s.pluginLoader.Reload(ctx, src, loaded)
+		}
+		if err != nil {
+			logger.Error("Failed to load plugin", "error", err)
+			continue
+		}
+		if len(reloaded) == 0 {
+			logger.Warn("No plugin could be loaded from directory")
+		}
+		for _, p := range reloaded {
+			logger.Info("Plugin loaded", "pluginId", p.ID, "version", p.Info.Version)
+		}
+	}
+}
+
+// pluginsInDir returns the registered plugins loaded from the directory or its subdirectories. The paths
+// reported by watched sources are the ones of the plugins loaded from them, so they are compared as is.
+func (s *Service) pluginsInDir(ctx context.Context, dir string) []*plugins.Plugin {
+	var res []*plugins.Plugin
+	for _, p := range s.pluginRegistry.Plugins(ctx) {
+		if p.FS == nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, p.FS.Base())
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
 func (s *Service) shutdown(ctx context.Context) {
 	var wg sync.WaitGroup
 	for _, plugin := range s.pluginRegistry.Plugins(ctx) {
@@ -2,6 +2,8 @@ package pluginstore
 
 import (
 	"context"
+	"path/filepath"
+	"sort"
 	"sync"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/plugins/manager/sources"
 )
 
 func TestStore_ProvideService(t *testing.T) {
@@ -223,6 +226,161 @@ func TestProcessManager_shutdown(t *testing.T) {
 	})
 }
 
+func TestStore_Run(t *testing.T) {
+	t.Run("Changes of watched plugin sources are reloaded until the context is cancelled", func(t *testing.T) {
+		loaded := make(chan string, 1)
+		w := &fakeWatcher{
+			FakePluginSource: fakes.FakePluginSource{
+				PluginClassFunc: func(ctx context.Context) plugins.Class {
+					return plugins.ClassExternal
+				},
+			},
+			changes: []sources.PluginDirChange{{Path: "test-panel", Op: sources.PluginDirAdded}},
+		}
+		s := New(fakes.NewFakePluginRegistry(), &fakes.FakeLoader{
+			LoadFunc: func(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error) {
+				loaded <- src.PluginURIs(ctx)[0]
+				return nil, nil
+			},
+		})
+		s.watchers = []sources.Watcher{w}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		runErr := make(chan error)
+		go func() {
+			runErr <- s.Run(ctx)
+		}()
+
+		require.Equal(t, "test-panel", <-loaded)
+		cancel()
+		require.ErrorIs(t, <-runErr, context.Canceled)
+	})
+}
+
+type fakeWatcher struct {
+	fakes.FakePluginSource
+	changes []sources.PluginDirChange
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, onChange func(ctx context.Context, changes []sources.PluginDirChange)) {
+	onChange(ctx, w.changes)
+	<-ctx.Done()
+}
+
+func TestStore_reload(t *testing.T) {
+	dir := t.TempDir()
+	newPlugin := func(id, version string) *plugins.Plugin {
+		return &plugins.Plugin{
+			JSONData: plugins.JSONData{ID: id, Info: plugins.Info{Version: version}},
+			FS:       plugins.NewLocalFS(filepath.Join(dir, id)),
+		}
+	}
+
+	setup := func(t *testing.T, registered ...*plugins.Plugin) (*Service, *fakes.FakePluginRegistry, *[]string) {
+		t.Helper()
+
+		var calls []string
+		reg := fakes.NewFakePluginRegistry()
+		for _, p := range registered {
+			reg.Store[p.ID] = p
+		}
+		s := New(reg, &fakes.FakeLoader{
+			LoadFunc: func(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error) {
+				require.Equal(t, plugins.ClassExternal, src.PluginClass(ctx))
+				require.Len(t, src.PluginURIs(ctx), 1)
+				p := newPlugin(filepath.Base(src.PluginURIs(ctx)[0]), "2.0.0")
+				reg.Store[p.ID] = p
+				calls = append(calls, "load "+p.ID)
+				return []*plugins.Plugin{p}, nil
+			},
+			UnloadFunc: func(_ context.Context, p *plugins.Plugin) (*plugins.Plugin, error) {
+				delete(reg.Store, p.ID)
+				calls = append(calls, "unload "+p.ID+" v"+p.Info.Version)
+				return p, nil
+			},
+			ReloadFunc: func(ctx context.Context, src plugins.PluginSource, loaded []*plugins.Plugin) ([]*plugins.Plugin, error) {
+				require.Equal(t, plugins.ClassExternal, src.PluginClass(ctx))
+				require.Len(t, src.PluginURIs(ctx), 1)
+				for _, p := range loaded {
+					calls = append(calls, "replace "+p.ID+" v"+p.Info.Version)
+				}
+				p := newPlugin(filepath.Base(src.PluginURIs(ctx)[0]), "2.0.0")
+				reg.Store[p.ID] = p
+				calls = append(calls, "reload "+p.ID)
+				return []*plugins.Plugin{p}, nil
+			},
+		})
+		return s, reg, &calls
+	}
+
+	t.Run("Changed plugin is reloaded in place of the loaded plugins", func(t *testing.T) {
+		child := newPlugin("test-app", "1.0.0")
+		child.ID = "test-app-child"
+		child.FS = plugins.NewLocalFS(filepath.Join(dir, "test-app", "datasource"))
+		s, reg, calls := setup(t, newPlugin("test-app", "1.0.0"), child, newPlugin("test-panel", "1.0.0"))
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-app"), Op: sources.PluginDirChanged},
+		})
+		sort.Strings((*calls)[:2])
+		require.Equal(t, []string{"replace test-app v1.0.0", "replace test-app-child v1.0.0", "reload test-app"}, *calls)
+		require.Equal(t, "2.0.0", reg.Store["test-app"].Info.Version)
+		require.Equal(t, "1.0.0", reg.Store["test-panel"].Info.Version)
+	})
+
+	t.Run("Changed plugin which fails validation keeps the loaded version", func(t *testing.T) {
+		s, reg, _ := setup(t, newPlugin("test-panel", "1.0.0"))
+		s.pluginLoader.(*fakes.FakeLoader).ReloadFunc = func(_ context.Context, _ plugins.PluginSource, _ []*plugins.Plugin) ([]*plugins.Plugin, error) {
+			return nil, nil
+		}
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: sources.PluginDirChanged},
+		})
+		require.Equal(t, "1.0.0", reg.Store["test-panel"].Info.Version)
+	})
+
+	t.Run("Changed directory without loaded plugins is loaded", func(t *testing.T) {
+		s, reg, calls := setup(t)
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: sources.PluginDirChanged},
+		})
+		require.Equal(t, []string{"load test-panel"}, *calls)
+		require.Contains(t, reg.Store, "test-panel")
+	})
+
+	t.Run("Added plugin is loaded", func(t *testing.T) {
+		s, reg, calls := setup(t)
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: sources.PluginDirAdded},
+		})
+		require.Equal(t, []string{"load test-panel"}, *calls)
+		require.Contains(t, reg.Store, "test-panel")
+	})
+
+	t.Run("Added plugin which is already loaded is skipped", func(t *testing.T) {
+		s, _, calls := setup(t, newPlugin("test-panel", "1.0.0"))
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: sources.PluginDirAdded},
+		})
+		require.Empty(t, *calls)
+	})
+
+	t.Run("Removed plugin is unloaded", func(t *testing.T) {
+		s, reg, calls := setup(t, newPlugin("test-panel", "1.0.0"), newPlugin("test-panel-2", "1.0.0"))
+
+		s.reload(context.Background(), plugins.ClassExternal, []sources.PluginDirChange{
+			{Path: filepath.Join(dir, "test-panel"), Op: sources.PluginDirRemoved},
+		})
+		require.Equal(t, []string{"unload test-panel v1.0.0"}, *calls)
+		require.NotContains(t, reg.Store, "test-panel")
+		require.Contains(t, reg.Store, "test-panel-2")
+	})
+}
+
 func TestStore_availablePlugins(t *testing.T) {
 	t.Run("Decommissioned plugins are excluded from availablePlugins", func(t *testing.T) {
 		p1 := &plugins.Plugin{JSONData: plugins.JSONData{ID: "test-datasource"}}
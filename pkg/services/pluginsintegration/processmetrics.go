@@ -0,0 +1,74 @@
+package pluginsintegration
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
+)
+
+// ProvideProcessService provides the backend plugin process service and registers
+// metrics about the processes it keeps alive.
+func ProvideProcessService(promRegisterer prometheus.Registerer) (*process.Service, error) {
+	s := process.ProvideService()
+	if err := promRegisterer.Register(newProcessCollector(s)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// processCollector exposes the status of backend plugin processes as prometheus metrics.
+type processCollector struct {
+	supervisor          process.Supervisor
+	restarts            *prometheus.Desc
+	healthCheckFailures *prometheus.Desc
+	crashLoop           *prometheus.Desc
+	limitViolations     *prometheus.Desc
+}
+
+func newProcessCollector(supervisor process.Supervisor) *processCollector {
+	return &processCollector{
+		supervisor: supervisor,
+		restarts: prometheus.NewDesc(
+			prometheus.BuildFQName("grafana", "", "plugin_process_restarts_total"),
+			"The total amount of backend plugin process restarts",
+			[]string{"plugin_id"}, nil,
+		),
+		healthCheckFailures: prometheus.NewDesc(
+			prometheus.BuildFQName("grafana", "", "plugin_process_health_check_failures_total"),
+			"The total amount of health checks a backend plugin process did not respond to",
+			[]string{"plugin_id"}, nil,
+		),
+		crashLoop: prometheus.NewDesc(
+			prometheus.BuildFQName("grafana", "", "plugin_process_crash_loop"),
+			"Whether a backend plugin process is crash looping",
+			[]string{"plugin_id"}, nil,
+		),
+		limitViolations: prometheus.NewDesc(
+			prometheus.BuildFQName("grafana", "", "plugin_process_limit_violations_total"),
+			"The total amount of times a backend plugin process exceeded one of its resource limits",
+			[]string{"plugin_id", "limit"}, nil,
+		),
+	}
+}
+
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.restarts
+	ch <- c.healthCheckFailures
+	ch <- c.crashLoop
+	ch <- c.limitViolations
+}
+
+func (c *processCollector) Collect(ch chan<- prometheus.Metric) {
+	for pluginID, status := range c.supervisor.Statuses() {
+		crashLoop := 0.0
+		if status.State == process.StateCrashLoop {
+			crashLoop = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.restarts, prometheus.CounterValue, float64(status.Restarts), pluginID)
+		ch <- prometheus.MustNewConstMetric(c.healthCheckFailures, prometheus.CounterValue, float64(status.HealthCheckFailures), pluginID)
+		ch <- prometheus.MustNewConstMetric(c.crashLoop, prometheus.GaugeValue, crashLoop, pluginID)
+		for limit, n := range status.LimitViolations {
+			ch <- prometheus.MustNewConstMetric(c.limitViolations, prometheus.CounterValue, float64(n), pluginID, limit)
+		}
+	}
+}
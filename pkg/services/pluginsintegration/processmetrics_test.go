@@ -0,0 +1,55 @@
+package pluginsintegration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
+)
+
+func TestProcessCollector(t *testing.T) {
+	collector := newProcessCollector(&fakeSupervisor{statuses: map[string]process.Status{
+		"running-plugin":  {State: process.StateRunning, Restarts: 1},
+		"crashing-plugin": {State: process.StateCrashLoop, Restarts: 6, HealthCheckFailures: 2, LimitViolations: map[string]int{"memory": 3}},
+	}})
+
+	expected := `
+# HELP grafana_plugin_process_crash_loop Whether a backend plugin process is crash looping
+# TYPE grafana_plugin_process_crash_loop gauge
+grafana_plugin_process_crash_loop{plugin_id="crashing-plugin"} 1
+grafana_plugin_process_crash_loop{plugin_id="running-plugin"} 0
+# HELP grafana_plugin_process_health_check_failures_total The total amount of health checks a backend plugin process did not respond to
+# TYPE grafana_plugin_process_health_check_failures_total counter
+grafana_plugin_process_health_check_failures_total{plugin_id="crashing-plugin"} 2
+grafana_plugin_process_health_check_failures_total{plugin_id="running-plugin"} 0
+# HELP grafana_plugin_process_limit_violations_total The total amount of times a backend plugin process exceeded one of its resource limits
+# TYPE grafana_plugin_process_limit_violations_total counter
+grafana_plugin_process_limit_violations_total{limit="memory",plugin_id="crashing-plugin"} 3
+# HELP grafana_plugin_process_restarts_total The total amount of backend plugin process restarts
+# TYPE grafana_plugin_process_restarts_total counter
+grafana_plugin_process_restarts_total{plugin_id="crashing-plugin"} 6
+grafana_plugin_process_restarts_total{plugin_id="running-plugin"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+type fakeSupervisor struct {
+	statuses map[string]process.Status
+}
+
+func (s *fakeSupervisor) Status(pluginID string) (process.Status, bool) {
+	status, exists := s.statuses[pluginID]
+	return status, exists
+}
+
+func (s *fakeSupervisor) Statuses() map[string]process.Status {
+	return s.statuses
+}
+
+func (s *fakeSupervisor) Restart(_ context.Context, _ string) error {
+	return nil
+}
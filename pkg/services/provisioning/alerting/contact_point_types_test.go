@@ -60,6 +60,36 @@ func TestReceivers(t *testing.T) {
 		_, err = cp.mapToModel("test")
 		require.Error(t, err)
 	})
+	t.Run("Broker integrations should be validated on mapping", func(t *testing.T) {
+		testCases := []struct {
+			_type    string
+			settings string
+			valid    bool
+		}{
+			{_type: "mqtt", settings: `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 1}`, valid: true},
+			{_type: "mqtt", settings: `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 3}`},
+			{_type: "mqtt", settings: `{"brokerUrl": "tcp://localhost:1883"}`},
+			{_type: "amqp", settings: `{"url": "amqp://localhost:5672", "exchange": "alerts", "routingKey": "grafana"}`, valid: true},
+			{_type: "amqp", settings: `{"url": "amqp://localhost:5672"}`},
+		}
+		for _, tc := range testCases {
+			cp := validReceiverV1(t)
+			var _type values.StringValue
+			err := yaml.Unmarshal([]byte(tc._type), &_type)
+			require.NoError(t, err)
+			cp.Type = _type
+			var settings values.JSONValue
+			err = yaml.Unmarshal([]byte(tc.settings), &settings)
+			require.NoError(t, err)
+			cp.Settings = settings
+			_, err = cp.mapToModel("test")
+			if tc.valid {
+				require.NoError(t, err, tc.settings)
+			} else {
+				require.Error(t, err, tc.settings)
+			}
+		}
+	})
 }
 
 func validReceiverV1(t *testing.T) ReceiverV1 {
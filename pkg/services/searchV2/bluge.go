@@ -33,12 +33,13 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldQueryText   = "query_text" // panel target queries
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
-	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc, config bluge.Config) (*orgIndex, error) {
+	dashboardWriter, err := bluge.OpenWriter(config)
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
 	}
@@ -207,9 +208,13 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		if queries := panel.Fields["queries"]; queries != "" {
+			doc.AddField(bluge.NewTextField(documentFieldQueryText, queries).SearchTermPositions())
+		}
+
 		for _, ref := range panel.References {
 			switch ref.Family {
-			case entity.StandardKindDashboard:
+			case entity.StandardKindDataSource:
 				if ref.Type != "" {
 					doc.AddField(bluge.NewKeywordField(documentFieldDSType, ref.Type).
 						StoreValue().
@@ -435,6 +440,14 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
+	// Panel query text
+	if q.QueryText != "" {
+		fullQuery.AddMust(bluge.NewMatchQuery(q.QueryText).
+			SetField(documentFieldQueryText).
+			SetOperator(bluge.MatchQueryOperatorAnd)) // all terms must match
+		hasConstraints = true
+	}
+
 	// Folder
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
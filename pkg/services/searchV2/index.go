@@ -68,6 +68,8 @@ type buildSignal struct {
 
 type orgIndex struct {
 	writers map[indexType]*bluge.Writer
+	// path is a directory of persisted index, empty for in-memory index.
+	path string
 }
 
 type indexType string
@@ -88,6 +90,16 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 	return reader, func() { _ = reader.Close() }, nil
 }
 
+func (i *orgIndex) close() error {
+	var closeErr error
+	for _, w := range i.writers {
+		if err := w.Close(); err != nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
 type searchIndex struct {
 	mu                      sync.RWMutex
 	loader                  dashboardLoader
@@ -104,6 +116,8 @@ type searchIndex struct {
 	tracer                  tracing.Tracer
 	features                featuremgmt.FeatureToggles
 	settings                setting.SearchSettings
+	// closed is set on shutdown after indexes were persisted.
+	closed bool
 }
 
 func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
@@ -183,7 +197,7 @@ func (i *searchIndex) sync(ctx context.Context) error {
 }
 
 func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh chan struct{}) error {
-	i.logger.Info("Initializing SearchV2", "dashboardLoadingBatchSize", i.settings.DashboardLoadingBatchSize, "fullReindexInterval", i.settings.FullReindexInterval, "indexUpdateInterval", i.settings.IndexUpdateInterval)
+	i.logger.Info("Initializing SearchV2", "dashboardLoadingBatchSize", i.settings.DashboardLoadingBatchSize, "fullReindexInterval", i.settings.FullReindexInterval, "indexUpdateInterval", i.settings.IndexUpdateInterval, "indexPath", i.settings.IndexPath)
 	initialSetupCtx, initialSetupSpan := i.tracer.Start(ctx, "searchV2 initialSetup")
 
 	reIndexInterval := i.settings.FullReindexInterval
@@ -204,18 +218,54 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 		lastEventID = lastEvent.Id
 	}
 
-	err = i.buildInitialIndexes(initialSetupCtx, orgIDs)
+	orgIDsToBuild := orgIDs
+	var loadedOrgIndexes map[int64]int64
+	if i.persistenceEnabled() {
+		loadedOrgIndexes = i.loadPersistedIndexes(initialSetupCtx, orgIDs, lastEventID)
+		orgIDsToBuild = make([]int64, 0, len(orgIDs))
+		for _, orgID := range orgIDs {
+			if _, ok := loadedOrgIndexes[orgID]; !ok {
+				orgIDsToBuild = append(orgIDsToBuild, orgID)
+			}
+		}
+		// Start from the oldest checkpoint, re-applying events on up-to-date
+		// indexes is harmless since dashboards are reloaded from the database.
+		for _, checkpointEventID := range loadedOrgIndexes {
+			if checkpointEventID < lastEventID {
+				lastEventID = checkpointEventID
+			}
+		}
+	}
+
+	err = i.buildInitialIndexes(initialSetupCtx, orgIDsToBuild)
 	if err != nil {
 		initialSetupSpan.End()
 		return err
 	}
 
+	if len(loadedOrgIndexes) > 0 {
+		// Catch up loaded indexes before reporting search as ready.
+		lastEventID = i.applyIndexUpdates(initialSetupCtx, lastEventID)
+	}
+
 	// This semaphore channel allows limiting concurrent async re-indexing routines to 1.
 	asyncReIndexSemaphore := make(chan struct{}, 1)
 
 	// Channel to handle signals about asynchronous full re-indexing completion.
 	reIndexDoneCh := make(chan int64, 1)
 
+	// Number of asynchronous re-indexing routines started and the lowest event ID
+	// they started at. Indexes built by them may miss events after that ID until
+	// events are re-applied, so the checkpoint written on shutdown must not be newer.
+	var reIndexInProgress int
+	var reIndexStartEventID int64
+	startReIndex := func(eventID int64) {
+		if reIndexInProgress == 0 || eventID < reIndexStartEventID {
+			reIndexStartEventID = eventID
+		}
+		reIndexInProgress++
+	}
+
 	i.initializationMutex.Lock()
 	i.initialIndexingComplete = true
 	i.initializationMutex.Unlock()
@@ -257,6 +307,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			// Full re-indexing will be later re-started in `case lastIndexedEventID := <-reIndexDoneCh`
 			// branch.
 			fullReIndexTimer.Stop()
+			startReIndex(lastIndexedEventID)
 			go func() {
 				defer span.End()
 				// We need semaphore here since asynchronous re-indexing may be in progress already.
@@ -275,6 +326,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			// come to an approach which does not require periodic re-indexing at all. One possible way
 			// is to use DB triggers, see https://github.com/grafana/grafana/pull/47712.
 			lastIndexedEventID := lastEventID
+			startReIndex(lastIndexedEventID)
 			go func() {
 				defer span.End()
 				// Do full re-index asynchronously to avoid blocking index synchronization
@@ -294,6 +346,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			// Asynchronous re-indexing is finished. Set lastEventID to the value which
 			// was actual at the re-indexing start – so that we could re-apply all the
 			// events happened during async index build process and make sure it's consistent.
+			reIndexInProgress--
 			if lastEventID != lastIndexedEventID {
 				i.logger.Info("Re-apply event ID to last indexed", "currentEventID", lastEventID, "lastIndexedEventID", lastIndexedEventID)
 				lastEventID = lastIndexedEventID
@@ -302,6 +355,13 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			}
 			fullReIndexTimer.Reset(reIndexInterval)
 		case <-ctx.Done():
+			if i.persistenceEnabled() {
+				checkpointEventID := lastEventID
+				if reIndexInProgress > 0 && reIndexStartEventID < checkpointEventID {
+					checkpointEventID = reIndexStartEventID
+				}
+				i.persistIndexes(checkpointEventID)
+			}
 			return ctx.Err()
 		}
 	}
@@ -496,13 +556,20 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 		attribute.Int("dashboardCount", len(dashboards)),
 	))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	config, indexPath, err := i.newIndexConfig(orgID)
+	if err != nil {
+		initOrgIndexSpan.End()
+		return 0, err
+	}
+	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender, config)
 
 	initOrgIndexSpan.End()
 
 	if err != nil {
+		i.removeIndexDir(indexPath)
 		return 0, fmt.Errorf("error initializing index: %w", err)
 	}
+	index.path = indexPath
 	orgSearchIndexTotalTime := time.Since(started)
 	orgSearchIndexBuildTime := orgSearchIndexTotalTime - orgSearchIndexLoadTime
 
@@ -514,14 +581,24 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchDashboardCount", len(dashboards))...)
 
 	i.mu.Lock()
-	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
-		for _, w := range oldIndex.writers {
-			_ = w.Close()
-		}
+	if i.closed {
+		// Shutdown happened while index was built.
+		i.mu.Unlock()
+		_ = index.close()
+		i.removeIndexDir(index.path)
+		return 0, errors.New("search index is closed")
+	}
+	oldIndex, ok := i.perOrgIndex[orgID]
+	if ok {
+		_ = oldIndex.close()
 	}
 	i.perOrgIndex[orgID] = index
 	i.mu.Unlock()
 
+	if ok {
+		i.removeIndexDir(oldIndex.path)
+	}
+
 	i.initializationMutex.Lock()
 	i.initializedOrgs[orgID] = true
 	i.initializationMutex.Unlock()
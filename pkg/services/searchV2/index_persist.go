@@ -0,0 +1,234 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blugelabs/bluge"
+)
+
+// persistedIndexVersion must be incremented on every change of indexed document
+// structure, so that indexes persisted by previous Grafana versions are rebuilt.
+const persistedIndexVersion = 2
+
+const checkpointFileName = "checkpoint.json"
+
+// indexCheckpoint is written to org index directory on graceful shutdown. It points
+// to the directory with the persisted index and holds the ID of the last entity event
+// applied to it.
+type indexCheckpoint struct {
+	Version     int    `json:"version"`
+	Dir         string `json:"dir"`
+	LastEventID int64  `json:"lastEventId"`
+}
+
+func (i *searchIndex) persistenceEnabled() bool {
+	return i.settings.IndexPath != ""
+}
+
+func (i *searchIndex) orgIndexDir(orgID int64) string {
+	return filepath.Join(i.settings.IndexPath, "org_"+strconv.FormatInt(orgID, 10))
+}
+
+// newIndexConfig returns config for a new org index and the directory where the index
+// is stored. Directory is empty when index is kept in memory only. Every build gets a
+// new directory since the previous index is still used until the new one is ready.
+func (i *searchIndex) newIndexConfig(orgID int64) (bluge.Config, string, error) {
+	if !i.persistenceEnabled() {
+		return bluge.InMemoryOnlyConfig(), "", nil
+	}
+	dir := filepath.Join(i.orgIndexDir(orgID), strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return bluge.Config{}, "", fmt.Errorf("error creating index directory: %w", err)
+	}
+	return bluge.DefaultConfig(dir), dir, nil
+}
+
+func (i *searchIndex) removeIndexDir(dir string) {
+	if dir == "" {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		i.logger.Warn("Can't remove index directory", "dir", dir, "error", err)
+	}
+}
+
+// loadPersistedIndexes opens org indexes persisted on previous shutdown and returns
+// checkpoint event IDs of loaded indexes. An index is only loaded if all entity events
+// after its checkpoint are still available, otherwise it's built from scratch.
+// All persisted data which was not loaded is removed.
+func (i *searchIndex) loadPersistedIndexes(ctx context.Context, orgIDs []int64, lastEventID int64) map[int64]int64 {
+	started := time.Now()
+	loaded := make(map[int64]int64, len(orgIDs))
+	inUse := map[string]bool{}
+	canReplay := map[int64]bool{}
+
+	for _, orgID := range orgIDs {
+		checkpoint, err := i.readCheckpoint(orgID)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				i.logger.Warn("Can't read search index checkpoint", "orgId", orgID, "error", err)
+			}
+			continue
+		}
+		if checkpoint.Version != persistedIndexVersion {
+			i.logger.Info("Persisted search index version changed", "orgId", orgID, "version", checkpoint.Version, "expectedVersion", persistedIndexVersion)
+			continue
+		}
+		ok, checked := canReplay[checkpoint.LastEventID]
+		if !checked {
+			ok = i.canReplayEventsAfter(ctx, checkpoint.LastEventID, lastEventID)
+			canReplay[checkpoint.LastEventID] = ok
+		}
+		if !ok {
+			i.logger.Info("Entity events after search index checkpoint are not available", "orgId", orgID, "checkpointEventId", checkpoint.LastEventID, "lastEventId", lastEventID)
+			continue
+		}
+
+		dir := filepath.Join(i.orgIndexDir(orgID), checkpoint.Dir)
+		if _, err := os.Stat(dir); err != nil {
+			i.logger.Warn("Can't find persisted search index", "orgId", orgID, "dir", dir, "error", err)
+			continue
+		}
+		writer, err := bluge.OpenWriter(bluge.DefaultConfig(dir))
+		if err != nil {
+			i.logger.Warn("Can't open persisted search index", "orgId", orgID, "dir", dir, "error", err)
+			continue
+		}
+
+		i.mu.Lock()
+		i.perOrgIndex[orgID] = &orgIndex{
+			writers: map[indexType]*bluge.Writer{
+				indexTypeDashboard: writer,
+			},
+			path: dir,
+		}
+		i.mu.Unlock()
+
+		i.initializationMutex.Lock()
+		i.initializedOrgs[orgID] = true
+		i.initializationMutex.Unlock()
+
+		inUse[dir] = true
+		loaded[orgID] = checkpoint.LastEventID
+	}
+
+	// Checkpoints of loaded indexes are removed too. Index changes from now on are
+	// not flushed to a checkpoint until shutdown, so after a crash indexes are rebuilt.
+	i.removeUnusedIndexData(inUse)
+
+	i.logger.Info("Finish loading persisted indexes", "elapsed", time.Since(started), "numOrgs", len(loaded))
+	return loaded
+}
+
+func (i *searchIndex) readCheckpoint(orgID int64) (indexCheckpoint, error) {
+	var checkpoint indexCheckpoint
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from the configured data path.
+	data, err := os.ReadFile(filepath.Join(i.orgIndexDir(orgID), checkpointFileName))
+	if err != nil {
+		return checkpoint, err
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, err
+	}
+	if checkpoint.Dir == "" || filepath.Base(checkpoint.Dir) != checkpoint.Dir {
+		return checkpoint, fmt.Errorf("invalid index directory %q", checkpoint.Dir)
+	}
+	return checkpoint, nil
+}
+
+// canReplayEventsAfter checks whether index which has all events up to eventID applied
+// can be caught up with lastEventID. Old events are periodically removed from the
+// entity events table, so we require the event following the checkpoint to still exist.
+func (i *searchIndex) canReplayEventsAfter(ctx context.Context, eventID int64, lastEventID int64) bool {
+	if eventID == lastEventID {
+		return true
+	}
+	if eventID > lastEventID {
+		// Events were removed or database was replaced.
+		return false
+	}
+	events, err := i.eventStore.GetAllEventsAfter(ctx, eventID)
+	if err != nil {
+		i.logger.Warn("Can't load events", "error", err)
+		return false
+	}
+	return len(events) > 0 && events[0].Id == eventID+1
+}
+
+func (i *searchIndex) removeUnusedIndexData(inUse map[string]bool) {
+	orgDirs, err := os.ReadDir(i.settings.IndexPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			i.logger.Warn("Can't read search index directory", "dir", i.settings.IndexPath, "error", err)
+		}
+		return
+	}
+	for _, orgDir := range orgDirs {
+		if !orgDir.IsDir() || !strings.HasPrefix(orgDir.Name(), "org_") {
+			continue
+		}
+		orgPath := filepath.Join(i.settings.IndexPath, orgDir.Name())
+		entries, err := os.ReadDir(orgPath)
+		if err != nil {
+			i.logger.Warn("Can't read search index directory", "dir", orgPath, "error", err)
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(orgPath, entry.Name())
+			if inUse[path] {
+				continue
+			}
+			i.removeIndexDir(path)
+		}
+	}
+}
+
+// persistIndexes closes all org indexes and writes checkpoints for persisted ones,
+// so that they could be loaded on next start. Index can't be used after that.
+func (i *searchIndex) persistIndexes(lastEventID int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.closed = true
+
+	for orgID, index := range i.perOrgIndex {
+		if err := index.close(); err != nil {
+			i.logger.Warn("Can't close search index", "orgId", orgID, "error", err)
+			continue
+		}
+		if index.path == "" {
+			continue
+		}
+		checkpoint := indexCheckpoint{
+			Version:     persistedIndexVersion,
+			Dir:         filepath.Base(index.path),
+			LastEventID: lastEventID,
+		}
+		if err := i.writeCheckpoint(orgID, checkpoint); err != nil {
+			i.logger.Warn("Can't write search index checkpoint", "orgId", orgID, "error", err)
+		}
+	}
+	i.logger.Info("Search indexes persisted", "lastEventId", lastEventID)
+}
+
+func (i *searchIndex) writeCheckpoint(orgID int64, checkpoint indexCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first to never leave a partially written checkpoint.
+	path := filepath.Join(i.orgIndexDir(orgID), checkpointFileName)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -3,13 +3,18 @@ package searchV2
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/blugelabs/bluge"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -192,6 +197,84 @@ func TestDashboardIndexUpdates(t *testing.T) {
 	})
 }
 
+func TestDashboardIndexPersistence(t *testing.T) {
+	newIndex := func(t *testing.T, indexPath string, evStore eventStore) *searchIndex {
+		t.Helper()
+		return newSearchIndex(&testDashboardLoader{dashboards: testDashboards}, evStore, &NoopDocumentExtender{}, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{IndexPath: indexPath})
+	}
+
+	countDocs := func(t *testing.T, index *searchIndex) uint64 {
+		t.Helper()
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		reader, cancel, err := orgIdx.readerForIndex(indexTypeDashboard)
+		require.NoError(t, err)
+		defer cancel()
+		count, err := reader.Count()
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("load-from-checkpoint", func(t *testing.T) {
+		indexPath := t.TempDir()
+		index := newIndex(t, indexPath, &store.MockEntityEventsService{})
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		numDocs := countDocs(t, index)
+		index.persistIndexes(5)
+
+		evStore := &store.MockEntityEventsService{}
+		evStore.On("GetAllEventsAfter", mock.Anything, int64(5)).Return([]*store.EntityEvent{{Id: 6}}, nil)
+		restored := newIndex(t, indexPath, evStore)
+		loaded := restored.loadPersistedIndexes(context.Background(), []int64{testOrgID}, 6)
+		require.Equal(t, map[int64]int64{testOrgID: 5}, loaded)
+		require.Equal(t, numDocs, countDocs(t, restored))
+		require.True(t, restored.initializedOrgs[testOrgID])
+
+		// Checkpoint is removed once loaded, so a crash leads to rebuilding index.
+		_, err = os.Stat(filepath.Join(restored.orgIndexDir(testOrgID), checkpointFileName))
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("rebuild-when-events-removed", func(t *testing.T) {
+		indexPath := t.TempDir()
+		index := newIndex(t, indexPath, &store.MockEntityEventsService{})
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		index.persistIndexes(5)
+
+		// Events 6 and 7 were deleted by the entity events cleanup.
+		evStore := &store.MockEntityEventsService{}
+		evStore.On("GetAllEventsAfter", mock.Anything, int64(5)).Return([]*store.EntityEvent{{Id: 8}}, nil)
+		restored := newIndex(t, indexPath, evStore)
+		loaded := restored.loadPersistedIndexes(context.Background(), []int64{testOrgID}, 8)
+		require.Empty(t, loaded)
+		_, ok := restored.getOrgIndex(testOrgID)
+		require.False(t, ok)
+
+		entries, err := os.ReadDir(restored.orgIndexDir(testOrgID))
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("reindex-removes-previous-index", func(t *testing.T) {
+		indexPath := t.TempDir()
+		index := newIndex(t, indexPath, &store.MockEntityEventsService{})
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ := index.getOrgIndex(testOrgID)
+		previousPath := orgIdx.path
+
+		_, err = index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ = index.getOrgIndex(testOrgID)
+		require.NotEqual(t, previousPath, orgIdx.path)
+		_, err = os.Stat(previousPath)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+		require.Equal(t, uint64(len(testDashboards)), countDocs(t, index))
+	})
+}
+
 var testSortDashboards = []dashboard{
 	{
 		id:  1,
@@ -548,6 +631,64 @@ func TestDashboardIndex_Panels(t *testing.T) {
 	})
 }
 
+var dashboardsWithPanelQueries = []dashboard{
+	{
+		id:  1,
+		uid: "1",
+		summary: &entity.EntitySummary{
+			Name: "My Dash",
+			Nested: []*entity.EntitySummary{
+				newNestedPanelWithQueries(1, 1, "Requests", "prom-uid", `sum(rate(http_requests_total{job="api"}[5m]))`),
+				newNestedPanelWithQueries(2, 1, "Errors", "prom-uid", `sum(rate(http_errors_total{job="api"}[5m]))`),
+				newNestedPanelWithQueries(3, 1, "Table", "sql-uid", "SELECT time, value FROM requests"),
+			},
+		},
+	},
+}
+
+func newNestedPanelWithQueries(id, dashId int64, name string, dsUID string, queries ...string) *entity.EntitySummary {
+	summary := newNestedPanel(id, dashId, name)
+	summary.Fields = map[string]string{"queries": strings.Join(queries, "\n")}
+	summary.References = []*entity.EntityExternalReference{
+		{Family: entity.StandardKindDataSource, Identifier: dsUID},
+	}
+	return summary
+}
+
+func TestDashboardIndex_PanelQueries(t *testing.T) {
+	searchPanelNames := func(t *testing.T, query DashboardQuery) []string {
+		t.Helper()
+		index := initTestOrgIndexFromDashes(t, dashboardsWithPanelQueries)
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, query, &NoopQueryExtender{}, "")
+		require.NoError(t, resp.Error)
+		names := []string{}
+		frame := getFrameWithNames(resp)
+		if frame == nil {
+			return names
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			names = append(names, frame.Fields[0].At(i).(string))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("query-text", func(t *testing.T) {
+		require.Equal(t, []string{"Requests"}, searchPanelNames(t, DashboardQuery{QueryText: "http_requests_total"}))
+		require.Equal(t, []string{"Errors", "Requests"}, searchPanelNames(t, DashboardQuery{QueryText: "api rate"}))
+		require.Equal(t, []string{}, searchPanelNames(t, DashboardQuery{QueryText: "http_requests_total requests"}))
+	})
+
+	t.Run("query-text-and-name", func(t *testing.T) {
+		require.Equal(t, []string{"Errors"}, searchPanelNames(t, DashboardQuery{Query: "err", QueryText: "api"}))
+	})
+
+	t.Run("datasource-uid", func(t *testing.T) {
+		require.Equal(t, []string{"Table"}, searchPanelNames(t, DashboardQuery{Datasource: "sql-uid"}))
+		require.Equal(t, []string{"Errors", "Requests"}, searchPanelNames(t, DashboardQuery{Datasource: "prom-uid", Kind: []string{string(entityKindPanel)}}))
+	})
+}
+
 var punctuationSplitNgramDashboards = []dashboard{
 	{
 		id:  1,
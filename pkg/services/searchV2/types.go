@@ -23,6 +23,7 @@ type DashboardQuery struct {
 	Tags               []string     `json:"tags,omitempty"`
 	Kind               []string     `json:"kind,omitempty"`
 	PanelType          string       `json:"panel_type,omitempty"`
+	QueryText          string       `json:"query_text,omitempty"` // matches panels with all the terms in their queries
 	UIDs               []string     `json:"uid,omitempty"`
 	Explain            bool         `json:"explain,omitempty"`            // adds details on why document matched
 	WithAllowedActions bool         `json:"withAllowedActions,omitempty"` // adds allowed actions per entity
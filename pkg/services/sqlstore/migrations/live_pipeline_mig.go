@@ -0,0 +1,42 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addLivePipelineMigrations(mg *Migrator) {
+	channelRuleV1 := Table{
+		Name: "live_channel_rule",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "pattern", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "settings", Type: DB_MediumText, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "pattern"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live_channel_rule table v1", NewAddTableMigration(channelRuleV1))
+	mg.AddMigration("add unique index live_channel_rule.org_id_pattern", NewAddIndexMigration(channelRuleV1, channelRuleV1.Indices[0]))
+
+	writeConfigV1 := Table{
+		Name: "live_write_config",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "settings", Type: DB_MediumText, Nullable: false},
+			{Name: "secure_settings", Type: DB_MediumText, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live_write_config table v1", NewAddTableMigration(writeConfigV1))
+	mg.AddMigration("add unique index live_write_config.org_id_uid", NewAddIndexMigration(writeConfigV1, writeConfigV1.Indices[0]))
+}
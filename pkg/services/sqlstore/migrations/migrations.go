@@ -111,6 +111,10 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	dashboardFolderMigrations.AddDashboardFolderMigrations(mg)
 
 	ssosettings.AddMigration(mg)
+
+	addLivePipelineMigrations(mg)
+
+	addDeletedDashboardMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
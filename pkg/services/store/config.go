@@ -101,6 +101,10 @@ type StorageGitConfig struct {
 	RequirePullRequest bool   `json:"requirePullRequest"`
 	PullInterval       string `json:"pullInterval"`
 
+	// Bidirectional commits saves to Branch as the saving user and pushes them to Remote.
+	// Remote changes are applied every PullInterval.
+	Bidirectional bool `json:"bidirectional,omitempty"`
+
 	// SECURE JSON :grimicing:
 	AccessToken string `json:"accessToken,omitempty"` // Simplest auth method for github
 }
@@ -128,7 +128,7 @@ func initEntityTables(mg *migrator.Migrator) {
 		},
 	})
 
-	tables = append(tables, migrator.Table{
+	entityHistoryV0 := migrator.Table{
 		Name: "entity_history",
 		Columns: []*migrator.Column{
 			{Name: "grn", Type: migrator.DB_NVarchar, Length: grnLength, Nullable: false},
@@ -150,7 +150,8 @@ func initEntityTables(mg *migrator.Migrator) {
 			{Cols: []string{"grn", "version"}, Type: migrator.UniqueIndex},
 			{Cols: []string{"updated_by"}, Type: migrator.IndexType},
 		},
-	})
+	}
+	tables = append(tables, entityHistoryV0)
 
 	tables = append(tables, migrator.Table{
 		Name: "entity_nested",
@@ -189,8 +190,74 @@ func initEntityTables(mg *migrator.Migrator) {
 		}
 	}
 
+	addEntityHistoryV1Migrations(mg, entityHistoryV0, grnLength)
+
 	mg.AddMigration("set path collation on entity table", migrator.NewRawSQLMigration("").
 		// MySQL `utf8mb4_unicode_ci` collation is set in `mysql_dialect.go`
 		// SQLite uses a `BINARY` collation by default
 		Postgres("ALTER TABLE entity_folder ALTER COLUMN slug_path TYPE VARCHAR(1024) COLLATE \"C\";")) // Collate C - sorting done based on character code byte values
 }
+
+// addEntityHistoryV1Migrations replaces the entity_history table with one that records every change with a
+// resource version, so that changes can be watched, and keeps a row for deleted entities. The previous table
+// is kept as entity_history_v0 after its rows are copied, without the indices whose names are now used by v1.
+func addEntityHistoryV1Migrations(mg *migrator.Migrator, entityHistoryV0 migrator.Table, grnLength int) {
+	entityHistoryV1 := migrator.Table{
+		Name: "entity_history",
+		Columns: []*migrator.Column{
+			// Increases with every change, used to resume watching
+			{Name: "resource_version", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "grn", Type: migrator.DB_NVarchar, Length: grnLength, Nullable: false},
+			{Name: "version", Type: migrator.DB_NVarchar, Length: 128, Nullable: false},
+
+			// The entity identifier (kept after the entity is deleted)
+			{Name: "tenant_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+
+			// created, updated or deleted
+			{Name: "action", Type: migrator.DB_Int, Nullable: false},
+
+			// Raw bytes
+			{Name: "body", Type: migrator.DB_LongBlob, Nullable: false},
+			{Name: "size", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "etag", Type: migrator.DB_NVarchar, Length: 32, Nullable: false, IsLatin: true}, // md5(body)
+
+			// Who changed what when
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+
+			// Commit message
+			{Name: "message", Type: migrator.DB_Text, Nullable: false}, // defaults to empty string
+
+			// Summary data at the time of the change
+			{Name: "labels", Type: migrator.DB_Text, Nullable: true}, // JSON object
+			{Name: "fields", Type: migrator.DB_Text, Nullable: true}, // JSON object
+		},
+		Indices: []*migrator.Index{
+			// not unique, the deleted rows keep the last version of the entity, which can be created again
+			{Cols: []string{"grn", "version"}, Type: migrator.IndexType},
+			{Cols: []string{"updated_by"}, Type: migrator.IndexType},
+			{Cols: []string{"tenant_id", "kind"}, Type: migrator.IndexType},
+		},
+	}
+
+	for _, index := range entityHistoryV0.Indices {
+		mg.AddMigration(fmt.Sprintf("drop index %s - v0", index.XName(entityHistoryV0.Name)), migrator.NewDropIndexMigration(entityHistoryV0, index))
+	}
+	mg.AddMigration("rename table entity_history to entity_history_v0", migrator.NewRenameTableMigration("entity_history", "entity_history_v0"))
+	mg.AddMigration("create table entity_history - v1", migrator.NewAddTableMigration(entityHistoryV1))
+	for i := range entityHistoryV1.Indices {
+		mg.AddMigration(fmt.Sprintf("create table entity_history - v1, index: %d", i), migrator.NewAddIndexMigration(entityHistoryV1, entityHistoryV1.Indices[i]))
+	}
+
+	// The history of deleted entities was removed with them, so every version belongs to an existing entity.
+	// The versions are copied as updates (action 2), in the order they were saved to assign increasing resource versions.
+	mg.AddMigration("copy entity_history v0 to v1", migrator.NewRawSQLMigration(
+		"INSERT INTO entity_history "+
+			"(grn, version, tenant_id, kind, uid, folder, action, body, size, etag, updated_at, updated_by, message, labels, fields) "+
+			"SELECT h.grn, h.version, e.tenant_id, e.kind, e.uid, e.folder, 2, h.body, h.size, h.etag, h.updated_at, h.updated_by, h.message, e.labels, e.fields "+
+			"FROM entity_history_v0 h INNER JOIN entity e ON e.grn = h.grn "+
+			"ORDER BY h.updated_at, h.version"))
+}
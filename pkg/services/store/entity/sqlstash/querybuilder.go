@@ -10,6 +10,7 @@ type selectQuery struct {
 	dialect  migrator.Dialect
 	fields   []string // SELECT xyz
 	from     string   // FROM object
+	sort     []string // ORDER BY xyz
 	limit    int64
 	oneExtra bool
 
@@ -77,6 +78,15 @@ func (q *selectQuery) toQuery() (string, []any) {
 		}
 	}
 
+	if len(q.sort) > 0 {
+		quotedSort := make([]string, len(q.sort))
+		for i, f := range q.sort {
+			quotedSort[i] = q.dialect.Quote(f)
+		}
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(quotedSort, ","))
+	}
+
 	if q.limit > 0 || q.oneExtra {
 		limit := q.limit
 		if limit < 1 {
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/slugify"
 	"github.com/grafana/grafana/pkg/services/grpcserver"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
@@ -29,10 +30,12 @@ var _ entity.EntityStoreAdminServer = &sqlEntityServer{}
 
 func ProvideSQLEntityServer(db db.DB, cfg *setting.Cfg, grpcServerProvider grpcserver.Provider, kinds kind.KindRegistry, resolver resolver.EntityReferenceResolver) entity.EntityStoreServer {
 	entityServer := &sqlEntityServer{
-		sess:     db.GetSqlxSession(),
-		log:      log.New("sql-entity-server"),
-		kinds:    kinds,
-		resolver: resolver,
+		sess:              db.GetSqlxSession(),
+		dialect:           db.GetDialect(),
+		log:               log.New("sql-entity-server"),
+		kinds:             kinds,
+		resolver:          resolver,
+		watchPollInterval: defaultWatchPollInterval,
 	}
 	entity.RegisterEntityStoreServer(grpcServerProvider.GetServer(), entityServer)
 	return entityServer
@@ -41,10 +44,31 @@ func ProvideSQLEntityServer(db db.DB, cfg *setting.Cfg, grpcServerProvider grpcs
 type sqlEntityServer struct {
 	log      log.Logger
 	sess     *session.SessionDB
+	dialect  migrator.Dialect
 	kinds    kind.KindRegistry
 	resolver resolver.EntityReferenceResolver
+
+	// how often the entity_history table is checked for changes while watching
+	watchPollInterval time.Duration
 }
 
+const (
+	defaultWatchPollInterval = time.Second
+	watchBatchSize           = 100
+
+	// Resource versions are assigned when the changes are inserted, so a transaction can commit a change with a
+	// lower resource version than a change committed before. The changes of the last watchSafetyWindow are read
+	// again by every poll, so that such changes are not skipped as long as they commit within the window.
+	watchSafetyWindow = 10 * time.Second
+)
+
+// The change recorded in each entity_history row
+const (
+	historyActionCreated = 1
+	historyActionUpdated = 2
+	historyActionDeleted = 3
+)
+
 func getReadSelect(r *entity.ReadEntityRequest) string {
 	fields := []string{
 		"tenant_id", "kind", "uid", "folder", // GRN + folder
@@ -175,7 +199,7 @@ func (s *sqlEntityServer) readFromHistory(ctx context.Context, r *entity.ReadEnt
 
 	rows, err := s.sess.Query(ctx,
 		"SELECT "+strings.Join(fields, ",")+
-			" FROM entity_history WHERE grn=? AND version=?", oid, r.Version)
+			" FROM entity_history WHERE grn=? AND version=? AND action<>?", oid, r.Version, historyActionDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -382,6 +406,11 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 			}
 		}
 
+		action := historyActionUpdated
+		if !isUpdate {
+			action = historyActionCreated
+		}
+
 		// 1. Add the `entity_history` values
 		versionInfo.Size = int64(len(body))
 		versionInfo.ETag = etag
@@ -389,12 +418,16 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 		versionInfo.UpdatedBy = updatedBy
 		_, err = tx.Exec(ctx, `INSERT INTO entity_history (`+
 			"grn, version, message, "+
+			"tenant_id, kind, uid, folder, action, "+
 			"size, body, etag, "+
-			"updated_at, updated_by) "+
-			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"updated_at, updated_by, "+
+			"labels, fields) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			oid, versionInfo.Version, versionInfo.Comment,
+			grn.TenantID, grn.ResourceKind, grn.ResourceIdentifier, r.Folder, action,
 			versionInfo.Size, body, versionInfo.ETag,
 			updatedAt, versionInfo.UpdatedBy,
+			summary.labels, summary.fields,
 		)
 		if err != nil {
 			return err
@@ -631,14 +664,65 @@ func (s *sqlEntityServer) Delete(ctx context.Context, r *entity.DeleteEntityRequ
 		return nil, err
 	}
 
+	modifier, err := appcontext.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	rsp := &entity.DeleteEntityResponse{}
 	err = s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		tombstone, err := s.selectTombstone(ctx, tx, grn2)
+		if err != nil {
+			return err
+		}
 		rsp.OK, err = doDelete(ctx, tx, grn2)
+		if err != nil || !rsp.OK {
+			return err
+		}
+
+		// Keep the last version in the history so watchers are notified about the delete
+		_, err = tx.Exec(ctx, `INSERT INTO entity_history (`+
+			"grn, version, message, "+
+			"tenant_id, kind, uid, folder, action, "+
+			"size, body, etag, "+
+			"updated_at, updated_by, "+
+			"labels, fields) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			grn2.ToGRNString(), tombstone.Version, "",
+			grn2.TenantID, grn2.ResourceKind, grn2.ResourceIdentifier, tombstone.Folder, historyActionDeleted,
+			tombstone.Size, tombstone.Body, tombstone.ETag,
+			time.Now().UnixMilli(), store.GetUserIDString(modifier),
+			tombstone.labels, tombstone.fields,
+		)
 		return err
 	})
 	return rsp, err
 }
 
+type historyTombstone struct {
+	*entity.Entity
+	labels *string
+	fields *string
+}
+
+// selectTombstone reads the values of the entity that are kept in the history once it is deleted
+func (s *sqlEntityServer) selectTombstone(ctx context.Context, tx *session.SessionTx, grn2 *grn.GRN) (*historyTombstone, error) {
+	rows, err := tx.Query(ctx, "SELECT folder,version,size,body,etag,labels,fields FROM entity WHERE grn=?", grn2.ToGRNString())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	t := &historyTombstone{Entity: &entity.Entity{}}
+	if rows.Next() {
+		err = rows.Scan(&t.Folder, &t.Version, &t.Size, &t.Body, &t.ETag, &t.labels, &t.fields)
+	}
+	if t.Body == nil {
+		t.Body = []byte{} // the history body can not be null
+	}
+	return t, err
+}
+
 func doDelete(ctx context.Context, tx *session.SessionTx, grn2 *grn.GRN) (bool, error) {
 	str := grn2.ToGRNString()
 	results, err := tx.Exec(ctx, "DELETE FROM entity WHERE grn=?", str)
@@ -651,7 +735,8 @@ func doDelete(ctx context.Context, tx *session.SessionTx, grn2 *grn.GRN) (bool,
 	}
 
 	// TODO: keep history? would need current version bump, and the "write" would have to get from history
-	_, err = tx.Exec(ctx, "DELETE FROM entity_history WHERE grn=?", str)
+	// The rows of previous deletes are kept, so watchers are notified about them
+	_, err = tx.Exec(ctx, "DELETE FROM entity_history WHERE grn=? AND action<>?", str, historyActionDeleted)
 	if err != nil {
 		return false, err
 	}
@@ -682,7 +767,7 @@ func (s *sqlEntityServer) History(ctx context.Context, r *entity.EntityHistoryRe
 	oid := grn2.ToGRNString()
 
 	page := ""
-	args := []any{oid}
+	args := []any{oid, historyActionDeleted}
 	if r.NextPageToken != "" {
 		// args = append(args, r.NextPageToken) // TODO, need to get time from the version
 		// page = "AND updated <= ?"
@@ -691,7 +776,7 @@ func (s *sqlEntityServer) History(ctx context.Context, r *entity.EntityHistoryRe
 
 	query := "SELECT version,size,etag,updated_at,updated_by,message \n" +
 		" FROM entity_history \n" +
-		" WHERE grn=? " + page + "\n" +
+		" WHERE grn=? AND action<>? " + page + "\n" +
 		" ORDER BY updated_at DESC LIMIT 100"
 
 	rows, err := s.sess.Query(ctx, query, args...)
@@ -745,6 +830,7 @@ func (s *sqlEntityServer) Search(ctx context.Context, r *entity.EntitySearchRequ
 	}
 
 	entityQuery := selectQuery{
+		dialect:  s.dialect,
 		fields:   fields,
 		from:     "entity", // the table
 		args:     []any{},
@@ -846,6 +932,230 @@ func (s *sqlEntityServer) Search(ctx context.Context, r *entity.EntitySearchRequ
 	return rsp, err
 }
 
-func (s *sqlEntityServer) Watch(*entity.EntityWatchRequest, entity.EntityStore_WatchServer) error {
-	return fmt.Errorf("unimplemented")
+func (s *sqlEntityServer) Watch(r *entity.EntityWatchRequest, w entity.EntityStore_WatchServer) error {
+	ctx := w.Context()
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("missing user in context")
+	}
+
+	grns := make([]string, 0, len(r.GRN))
+	for _, g := range r.GRN {
+		g, err := s.validateGRN(ctx, g)
+		if err != nil {
+			return err
+		}
+		grns = append(grns, g.ToGRNString())
+	}
+
+	// Without a resource version, only changes made after the watch started are sent
+	since := r.Since
+	if since < 1 {
+		since, err = s.getLatestResourceVersion(ctx, user.OrgID)
+		if err != nil {
+			return err
+		}
+	}
+	cursor := newWatchCursor(since)
+
+	ticker := time.NewTicker(s.watchPollInterval)
+	defer ticker.Stop()
+	for {
+		err = s.pollHistory(ctx, r, w, user.OrgID, grns, cursor, time.Now())
+		if ctx.Err() != nil {
+			// the watch ended while reading the history
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *sqlEntityServer) getLatestResourceVersion(ctx context.Context, tenantID int64) (int64, error) {
+	rows, err := s.sess.Query(ctx, "SELECT MAX(resource_version) FROM entity_history WHERE tenant_id=?", tenantID)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var latest sql.NullInt64
+	if rows.Next() {
+		err = rows.Scan(&latest)
+	}
+	return latest.Int64, err
+}
+
+// watchCursor tracks the changes that were read by a watch
+type watchCursor struct {
+	// all the changes up to this resource version were read, they are not read again
+	since int64
+	// the changes read after since, and when they were first read
+	read map[int64]time.Time
+}
+
+func newWatchCursor(since int64) *watchCursor {
+	return &watchCursor{since: since, read: map[int64]time.Time{}}
+}
+
+// advance moves the cursor after the changes that were read before the safety window
+func (c *watchCursor) advance(now time.Time) {
+	for rv, readAt := range c.read {
+		if rv > c.since && now.Sub(readAt) >= watchSafetyWindow {
+			c.since = rv
+		}
+	}
+	for rv := range c.read {
+		if rv <= c.since {
+			delete(c.read, rv)
+		}
+	}
+}
+
+// pollHistory sends all the matching changes recorded after the cursor that were not sent yet
+func (s *sqlEntityServer) pollHistory(ctx context.Context, r *entity.EntityWatchRequest, w entity.EntityStore_WatchServer, tenantID int64, grns []string, cursor *watchCursor, now time.Time) error {
+	defer cursor.advance(now)
+
+	from := cursor.since
+	for {
+		events, err := s.readHistory(ctx, r, tenantID, grns, from)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			from = event.Timestamp
+			if _, ok := cursor.read[event.Timestamp]; ok {
+				continue // already sent
+			}
+			cursor.read[event.Timestamp] = now
+			if len(event.Entity) == 0 {
+				continue // filtered out by labels
+			}
+			if err := w.Send(event); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < watchBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *sqlEntityServer) readHistory(ctx context.Context, r *entity.EntityWatchRequest, tenantID int64, grns []string, since int64) ([]*entity.EntityWatchResponse, error) {
+	fields := []string{
+		"resource_version", "action",
+		"tenant_id", "kind", "uid", "folder",
+		"version", "size", "etag",
+		"updated_at", "updated_by",
+		"labels", "fields",
+	}
+	if r.WithBody {
+		fields = append(fields, "body")
+	}
+
+	historyQuery := selectQuery{
+		dialect: s.dialect,
+		fields:  fields,
+		from:    "entity_history",
+		sort:    []string{"resource_version"},
+		limit:   watchBatchSize,
+	}
+	historyQuery.addWhere("tenant_id", tenantID)
+	historyQuery.addWhere("resource_version > ?", since)
+
+	if len(r.Kind) > 0 {
+		historyQuery.addWhereIn("kind", r.Kind)
+	}
+	if r.Folder != "" {
+		historyQuery.addWhere("folder", r.Folder)
+	}
+	if len(grns) > 0 {
+		historyQuery.addWhereIn("grn", grns)
+	}
+
+	query, args := historyQuery.toQuery()
+	rows, err := s.sess.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	events := []*entity.EntityWatchResponse{}
+	for rows.Next() {
+		event := &entity.EntityWatchResponse{}
+		raw := &entity.Entity{
+			GRN: &grn.GRN{},
+		}
+		summaryjson := summarySupport{}
+		action := 0
+
+		args := []any{
+			&event.Timestamp, &action,
+			&raw.GRN.TenantID, &raw.GRN.ResourceKind, &raw.GRN.ResourceIdentifier, &raw.Folder,
+			&raw.Version, &raw.Size, &raw.ETag,
+			&raw.UpdatedAt, &raw.UpdatedBy,
+			&summaryjson.labels, &summaryjson.fields,
+		}
+		if r.WithBody {
+			args = append(args, &raw.Body)
+		}
+
+		err = rows.Scan(args...)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+
+		switch action {
+		case historyActionCreated:
+			event.Action = entity.EntityWatchResponse_CREATED
+		case historyActionDeleted:
+			event.Action = entity.EntityWatchResponse_DELETED
+		default:
+			event.Action = entity.EntityWatchResponse_UPDATED
+		}
+
+		summary, err := summaryjson.toEntitySummary()
+		if err != nil {
+			return nil, err
+		}
+		if !matchesLabels(summary.Labels, r.Labels) {
+			continue
+		}
+
+		if r.WithLabels || r.WithFields {
+			if !r.WithLabels {
+				summary.Labels = nil
+			}
+			if !r.WithFields {
+				summary.Fields = nil
+			}
+			raw.SummaryJson, err = json.Marshal(summary)
+			if err != nil {
+				return nil, err
+			}
+		}
+		event.Entity = []*entity.Entity{raw}
+	}
+	return events, rows.Err()
+}
+
+func matchesLabels(labels map[string]string, selector map[string]string) bool {
+	for k, v := range selector {
+		if val, ok := labels[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
 }
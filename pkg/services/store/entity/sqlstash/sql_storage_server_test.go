@@ -0,0 +1,196 @@
+package sqlstash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/grn"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/store/entity"
+	"github.com/grafana/grafana/pkg/services/store/entity/migrations"
+	"github.com/grafana/grafana/pkg/services/store/kind"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationWatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	err := migrations.MigrateEntityStore(sqlStore, featuremgmt.WithFeatures(featuremgmt.FlagEntityStore))
+	require.NoError(t, err)
+
+	s := &sqlEntityServer{
+		sess:              sqlStore.GetSqlxSession(),
+		dialect:           sqlStore.GetDialect(),
+		log:               log.NewNopLogger(),
+		kinds:             kind.NewKindRegistry(),
+		watchPollInterval: 10 * time.Millisecond,
+	}
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{UserID: 1, OrgID: 1})
+
+	write := func(uid string, folder string, body string) {
+		t.Helper()
+		_, err := s.Write(ctx, &entity.WriteEntityRequest{
+			GRN:    &grn.GRN{ResourceKind: entity.StandardKindDashboard, ResourceIdentifier: uid},
+			Folder: folder,
+			Body:   []byte(body),
+		})
+		require.NoError(t, err)
+	}
+
+	write("a", "f1", `{"title": "A", "tags": ["prod"]}`)
+	write("b", "f2", `{"title": "B"}`)
+	write("a", "f1", `{"title": "A2", "tags": ["prod"]}`)
+	rsp, err := s.Delete(ctx, &entity.DeleteEntityRequest{
+		GRN: &grn.GRN{ResourceKind: entity.StandardKindDashboard, ResourceIdentifier: "b"},
+	})
+	require.NoError(t, err)
+	require.True(t, rsp.OK)
+
+	var deleteVersion int64
+	t.Run("should send all changes after the resource version", func(t *testing.T) {
+		// the history of deleted entities is replaced by the delete event
+		events := watch(ctx, t, s, &entity.EntityWatchRequest{Since: 1, WithBody: true}, 2)
+		require.Equal(t, entity.EntityWatchResponse_UPDATED, events[0].Action)
+		require.Equal(t, "a", events[0].Entity[0].GRN.ResourceIdentifier)
+		require.Equal(t, "2", events[0].Entity[0].Version)
+		require.Contains(t, string(events[0].Entity[0].Body), "A2")
+		require.Equal(t, entity.EntityWatchResponse_DELETED, events[1].Action)
+		require.Equal(t, "b", events[1].Entity[0].GRN.ResourceIdentifier)
+		require.Equal(t, "f2", events[1].Entity[0].Folder)
+		require.Contains(t, string(events[1].Entity[0].Body), "B")
+		require.Less(t, events[0].Timestamp, events[1].Timestamp)
+		deleteVersion = events[1].Timestamp
+
+		events = watch(ctx, t, s, &entity.EntityWatchRequest{Since: events[0].Timestamp}, 1)
+		require.Equal(t, entity.EntityWatchResponse_DELETED, events[0].Action)
+	})
+
+	t.Run("should only send new changes without a resource version", func(t *testing.T) {
+		events := watch(ctx, t, s, &entity.EntityWatchRequest{}, 0)
+		require.Empty(t, events)
+	})
+
+	t.Run("should filter by folder and labels", func(t *testing.T) {
+		events := watch(ctx, t, s, &entity.EntityWatchRequest{Since: 1, Folder: "f2"}, 1)
+		require.Equal(t, "b", events[0].Entity[0].GRN.ResourceIdentifier)
+
+		events = watch(ctx, t, s, &entity.EntityWatchRequest{Since: -1, Labels: map[string]string{"prod": ""}, WithLabels: true}, 0)
+		require.Empty(t, events)
+
+		events = watch(ctx, t, s, &entity.EntityWatchRequest{Since: 1, Labels: map[string]string{"prod": ""}, WithLabels: true}, 1)
+		require.Equal(t, "a", events[0].Entity[0].GRN.ResourceIdentifier)
+		require.JSONEq(t, `{"labels":{"prod":""}}`, string(events[0].Entity[0].SummaryJson))
+	})
+
+	t.Run("should send changes made while watching", func(t *testing.T) {
+		watchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		stream := &fakeWatchServer{ctx: watchCtx, events: make(chan *entity.EntityWatchResponse, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- s.Watch(&entity.EntityWatchRequest{Kind: []string{entity.StandardKindDashboard}}, stream)
+		}()
+
+		// the watch only sends new changes, so keep writing until the watch has started
+		var event *entity.EntityWatchResponse
+		require.Eventually(t, func() bool {
+			write("c", "f1", `{"title": "C `+time.Now().String()+`"}`)
+			select {
+			case event = <-stream.events:
+				return true
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}, 4*time.Second, 10*time.Millisecond)
+		require.Equal(t, "c", event.Entity[0].GRN.ResourceIdentifier)
+		require.Nil(t, event.Entity[0].Body)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("should keep the delete event when the entity is created again", func(t *testing.T) {
+		write("b", "f2", `{"title": "B2"}`)
+
+		events := watch(ctx, t, s, &entity.EntityWatchRequest{Since: deleteVersion - 1, Folder: "f2"}, 2)
+		require.Equal(t, entity.EntityWatchResponse_DELETED, events[0].Action)
+		require.Equal(t, entity.EntityWatchResponse_CREATED, events[1].Action)
+		require.Equal(t, "b", events[1].Entity[0].GRN.ResourceIdentifier)
+		require.Equal(t, "1", events[1].Entity[0].Version)
+	})
+
+	t.Run("should send changes committed after changes with a higher resource version", func(t *testing.T) {
+		write("d", "f3", `{"title": "D"}`)
+
+		r := &entity.EntityWatchRequest{Folder: "f3"}
+		cursor := newWatchCursor(0)
+		stream := &fakeWatchServer{ctx: ctx, events: make(chan *entity.EntityWatchResponse, 10)}
+		now := time.Now()
+		require.NoError(t, s.pollHistory(ctx, r, stream, 1, nil, cursor, now))
+		require.Len(t, stream.events, 1)
+		latest := (<-stream.events).Timestamp
+
+		// the first version of "b" was removed from the history with the entity, commit a change with its resource version
+		_, err := s.sess.Exec(ctx, "INSERT INTO entity_history "+
+			"(resource_version, grn, version, message, tenant_id, kind, uid, folder, action, size, body, etag, updated_at, updated_by) "+
+			"VALUES (2, 'late', '1', '', 1, ?, 'e', 'f3', ?, 0, '', '', 0, '')", entity.StandardKindDashboard, historyActionCreated)
+		require.NoError(t, err)
+
+		require.NoError(t, s.pollHistory(ctx, r, stream, 1, nil, cursor, now.Add(time.Second)))
+		require.Len(t, stream.events, 1)
+		event := <-stream.events
+		require.Equal(t, int64(2), event.Timestamp)
+		require.Equal(t, "e", event.Entity[0].GRN.ResourceIdentifier)
+
+		require.NoError(t, s.pollHistory(ctx, r, stream, 1, nil, cursor, now.Add(2*time.Second)))
+		require.Empty(t, stream.events, "changes are only sent once")
+
+		require.NoError(t, s.pollHistory(ctx, r, stream, 1, nil, cursor, now.Add(watchSafetyWindow+time.Second)))
+		require.Empty(t, stream.events)
+		require.Equal(t, latest, cursor.since)
+		require.Empty(t, cursor.read)
+	})
+}
+
+// watch collects the expected number of events, and checks that no other events are sent
+func watch(ctx context.Context, t *testing.T, s *sqlEntityServer, r *entity.EntityWatchRequest, count int) []*entity.EntityWatchResponse {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	stream := &fakeWatchServer{ctx: ctx, events: make(chan *entity.EntityWatchResponse, 100)}
+	require.NoError(t, s.Watch(r, stream))
+	close(stream.events)
+
+	events := []*entity.EntityWatchResponse{}
+	for e := range stream.events {
+		events = append(events, e)
+	}
+	require.Len(t, events, count)
+	return events
+}
+
+type fakeWatchServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *entity.EntityWatchResponse
+}
+
+func (f *fakeWatchServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchServer) Send(e *entity.EntityWatchResponse) error {
+	f.events <- e
+	return nil
+}
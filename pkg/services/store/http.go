@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -68,6 +69,11 @@ func (s *standardStorageService) doWrite(c *contextmodel.ReqContext) response.Re
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "save error", err)
 	}
+	// the ETag of the saved file lets the client save it again without reloading it,
+	// a pull request does not change the file so the loaded ETag is still valid
+	if rsp.Code == http.StatusOK && cmd.Workflow != WriteValueWorkflow_PR {
+		c.Resp.Header().Set("ETag", strconv.Quote(contentHash(cmd.Body)))
+	}
 	return response.JSON(200, rsp)
 }
 
@@ -174,6 +180,7 @@ func (s *standardStorageService) read(c *contextmodel.ReqContext) response.Respo
 	if strings.HasSuffix(path, ".svg") {
 		c.Resp.Header().Set("Content-Type", "image/svg+xml")
 	}
+	c.Resp.Header().Set("ETag", strconv.Quote(contentHash(file.Contents)))
 	return response.Respond(200, file.Contents)
 }
 
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/store/entity"
@@ -77,6 +78,9 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) en
 			p.Description = panel.Description
 			p.Fields = make(map[string]string, 0)
 			p.Fields["type"] = panel.Type
+			if len(panel.Queries) > 0 {
+				p.Fields["queries"] = strings.Join(panel.Queries, "\n")
+			}
 
 			if panel.Type != "row" {
 				panelRefs.Add(entity.ExternalEntityReferencePlugin, string(plugins.TypePanel), panel.Type)
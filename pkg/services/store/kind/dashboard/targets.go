@@ -4,9 +4,19 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// targetQueryFields are target fields holding query text in the most common data sources.
+var targetQueryFields = map[string]bool{
+	"expr":       true, // prometheus, loki
+	"query":      true, // influxdb, elasticsearch, tempo and many others
+	"rawSql":     true, // sql data sources
+	"target":     true, // graphite
+	"expression": true, // server side expressions
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -76,6 +86,13 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 			iter.Skip()
 
 		default:
+			if targetQueryFields[l1Field] && iter.WhatIsNext() == jsoniter.StringValue {
+				if query := iter.ReadString(); query != "" {
+					s.queries = append(s.queries, query)
+				}
+				continue
+			}
+
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
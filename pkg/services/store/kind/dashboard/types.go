@@ -9,6 +9,7 @@ type panelInfo struct {
 	LibraryPanel  string          `json:"libraryPanel,omitempty"` // UID of referenced library panel
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`   // UIDs
 	Transformer   []string        `json:"transformer,omitempty"`  // ids of the transformation steps
+	Queries       []string        `json:"queries,omitempty"`      // query text of the targets
 	// Rows define panels as sub objects
 	Collapsed []panelInfo `json:"collapsed,omitempty"`
 }
@@ -605,12 +605,20 @@ func (s *standardStorageService) getWorkflowOptions(ctx context.Context, user *u
 	meta := root.Meta()
 	if meta.Config.Type == rootStorageTypeGit && meta.Config.Git != nil {
 		cfg := meta.Config.Git
-		options.Workflows = append(options.Workflows, workflowInfo{
-			Type:        WriteValueWorkflow_PR,
-			Label:       "Create pull request",
-			Description: "Create a new upstream pull request",
-		})
-		if !cfg.RequirePullRequest {
+		if !cfg.Bidirectional || cfg.AccessToken != "" {
+			options.Workflows = append(options.Workflows, workflowInfo{
+				Type:        WriteValueWorkflow_PR,
+				Label:       "Create pull request",
+				Description: "Create a new upstream pull request",
+			})
+		}
+		if cfg.Bidirectional && !cfg.RequirePullRequest {
+			options.Workflows = append(options.Workflows, workflowInfo{
+				Type:        WriteValueWorkflow_Save,
+				Label:       "Save",
+				Description: "Commit and push to " + cfg.Branch,
+			})
+		} else if !cfg.RequirePullRequest {
 			options.Workflows = append(options.Workflows, workflowInfo{
 				Type:        WriteValueWorkflow_Push,
 				Label:       "Push to " + cfg.Branch,
@@ -7,15 +7,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"gocloud.dev/blob"
 
 	"github.com/grafana/grafana/pkg/infra/filestorage"
-	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -29,8 +31,14 @@ type rootStorageGit struct {
 	root     string // repostitory root
 
 	github *githubHelper
+	auth   transport.AuthMethod
 	meta   RootStorageMeta
 	store  filestorage.FileStorage
+
+	// mu serializes operations on the local working copy in bidirectional mode.
+	mu         sync.Mutex
+	conflictMu sync.RWMutex
+	conflict   *gitConflictError // set when the last pull could not apply remote changes
 }
 
 func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache string) *rootStorageGit {
@@ -93,12 +101,16 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 	if meta.Notice == nil {
 		repo, err := git.PlainOpen(localWorkCache)
 		if errors.Is(err, git.ErrRepositoryNotExists) {
-			repo, err = git.PlainClone(localWorkCache, false, &git.CloneOptions{
+			opts := &git.CloneOptions{
 				URL:      cfg.Remote,
 				Progress: os.Stdout,
 				//Depth:    1,
 				//SingleBranch: true,
-			})
+			}
+			if cfg.Bidirectional && cfg.Branch != "" {
+				opts.ReferenceName = plumbing.NewBranchReferenceName(cfg.Branch)
+			}
+			repo, err = git.PlainClone(localWorkCache, false, opts)
 		}
 
 		if err != nil {
@@ -142,6 +154,8 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 				}
 
 				if token != "" {
+					// GitHub accepts any non-empty user name with a token.
+					s.auth = &githttp.BasicAuth{Username: "grafana", Password: token}
 					s.github, err = newGithubHelper(context.Background(), cfg.Remote, token)
 					if err != nil {
 						meta.Notice = append(meta.Notice, data.Notice{
@@ -169,7 +183,8 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 		// Try pulling after init
 		if s.repo != nil && !scfg.Disabled {
 			err = s.Sync()
-			if err != nil {
+			var conflict *gitConflictError
+			if err != nil && !errors.As(err, &conflict) {
 				meta.Notice = append(meta.Notice, data.Notice{
 					Severity: data.NoticeSeverityError,
 					Text:     "unable to pull: " + err.Error(),
@@ -186,7 +201,7 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 					go func() {
 						for range ticker.C {
 							grafanaStorageLogger.Info("Try git pull", "branch", s.settings.Remote)
-							err = s.Sync()
+							err := s.Sync()
 							if err != nil {
 								grafanaStorageLogger.Info("Error pulling", "error", err)
 							}
@@ -202,7 +217,17 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 }
 
 func (s *rootStorageGit) Meta() RootStorageMeta {
-	return s.meta
+	s.conflictMu.RLock()
+	defer s.conflictMu.RUnlock()
+	if s.conflict == nil {
+		return s.meta
+	}
+	meta := s.meta
+	meta.Notice = append([]data.Notice{{
+		Severity: data.NoticeSeverityError,
+		Text:     "unable to apply remote changes: " + s.conflict.Error(),
+	}}, s.meta.Notice...)
+	return meta
 }
 
 func (s *rootStorageGit) Store() filestorage.FileStorage {
@@ -226,6 +251,9 @@ func (s *rootStorageGit) Pull() error {
 }
 
 func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error) {
+	if s.settings.Bidirectional && cmd.Workflow != WriteValueWorkflow_PR {
+		return s.commitAndPush(ctx, cmd)
+	}
 	if s.github == nil {
 		return nil, fmt.Errorf("github client not initialized")
 	}
@@ -282,92 +310,44 @@ func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*Wr
 	}
 
 	// Push to remote branch (save)
-	if cmd.Workflow == WriteValueWorkflow_Push || true {
-		res := &WriteValueResponse{
-			Branch: s.settings.Branch,
-		}
-		ref, _, err := s.github.getRef(ctx, s.settings.Branch)
-		if err != nil {
-			res.Code = 500
-			res.Message = "unable to create branch"
-			return res, nil
-		}
-		err = s.github.pushCommit(ctx, ref, cmd)
-		if err != nil {
-			res.Code = 500
-			res.Message = "error creating commit"
-			return res, nil
-		}
-		ref, _, _ = s.github.getRef(ctx, s.settings.Branch)
-		if ref != nil {
-			res.Hash = *ref.Object.SHA
-			res.URL = ref.GetURL()
-		}
-
-		err = s.Pull()
-		if err != nil {
-			res.Message = "error pulling: " + err.Error()
-		}
-
-		res.Code = 200
-		return res, nil
-	}
-
-	rel := cmd.Path
-	if s.meta.Config.Git.Root != "" {
-		rel = filepath.Join(s.meta.Config.Git.Root, cmd.Path)
+	res := &WriteValueResponse{
+		Branch: s.settings.Branch,
 	}
-
-	fpath := filepath.Join(s.root, rel)
-	err := os.WriteFile(fpath, cmd.Body, 0644)
-	if err != nil {
-		return nil, err
-	}
-
-	w, err := s.repo.Worktree()
+	ref, _, err := s.github.getRef(ctx, s.settings.Branch)
 	if err != nil {
-		return nil, err
+		res.Code = 500
+		res.Message = "unable to create branch"
+		return res, nil
 	}
-
-	// The file we just wrote
-	_, err = w.Add(rel)
+	err = s.github.pushCommit(ctx, ref, cmd)
 	if err != nil {
-		return nil, err
-	}
-
-	msg := cmd.Message
-	if msg == "" {
-		msg = "changes from grafana ui"
+		res.Code = 500
+		res.Message = "error creating commit"
+		return res, nil
 	}
-	usr := cmd.User
-	if usr == nil {
-		usr = &user.SignedInUser{}
+	ref, _, _ = s.github.getRef(ctx, s.settings.Branch)
+	if ref != nil {
+		res.Hash = *ref.Object.SHA
+		res.URL = ref.GetURL()
 	}
 
-	hash, err := w.Commit(msg, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  firstRealString(usr.Name, usr.Login, usr.Email, "?"),
-			Email: firstRealString(usr.Email, usr.Login, usr.Name, "?"),
-			When:  time.Now(),
-		},
-	})
+	err = s.Pull()
 	if err != nil {
-		return nil, err
+		res.Message = "error pulling: " + err.Error()
 	}
 
-	grafanaStorageLogger.Info("Made commit", "hash", hash)
-	// err = s.repo.Push(&git.PushOptions{
-	// 	InsecureSkipTLS: true,
-	// })
-
-	return &WriteValueResponse{
-		Hash:    hash.String(),
-		Message: "made commit",
-	}, nil
+	res.Code = 200
+	return res, nil
 }
 
 func (s *rootStorageGit) Sync() error {
 	grafanaStorageLogger.Info("GIT PULL", "remote", s.settings.Remote)
+	if s.settings.Bidirectional {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, err := s.pull(context.Background())
+		return err
+	}
 	err := s.Pull()
 	if err != nil {
 		if err.Error() == "already up-to-date" {
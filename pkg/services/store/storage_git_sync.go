@@ -0,0 +1,314 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const gitRemoteName = "origin"
+
+// gitConflictError is returned when remote changes can not be applied to the local working copy.
+type gitConflictError struct {
+	reason string
+	paths  []string
+}
+
+func (e *gitConflictError) Error() string {
+	if len(e.paths) == 0 {
+		return e.reason
+	}
+	return fmt.Sprintf("%s: %s", e.reason, strings.Join(e.paths, ", "))
+}
+
+// localBranch returns the branch commits are made to in bidirectional mode.
+func (s *rootStorageGit) localBranch() (plumbing.ReferenceName, error) {
+	if s.settings.Branch != "" {
+		return plumbing.NewBranchReferenceName(s.settings.Branch), nil
+	}
+	head, err := s.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Name(), nil
+}
+
+// pull fetches the configured branch and fast-forwards the working copy to it. Returns
+// repository paths changed by the applied remote commits. The working copy is left
+// untouched and a conflict error is returned when the remote history diverged or when
+// uncommitted local changes touch the same paths. Must be called with s.mu held.
+func (s *rootStorageGit) pull(ctx context.Context) ([]string, error) {
+	branch, err := s.localBranch()
+	if err != nil {
+		return nil, err
+	}
+	remoteRefName := plumbing.NewRemoteReferenceName(gitRemoteName, branch.Short())
+
+	err = s.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branch, remoteRefName))},
+		Auth:       s.auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("error fetching: %w", err)
+	}
+
+	remoteRef, err := s.repo.Reference(remoteRefName, true)
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	if head.Name() != branch {
+		return nil, fmt.Errorf("working copy is on %s, expected %s", head.Name().Short(), branch.Short())
+	}
+	if head.Hash() == remoteRef.Hash() {
+		s.setConflict(nil)
+		return nil, nil
+	}
+
+	headCommit, err := s.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	remoteCommit, err := s.repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+	isAncestor, err := headCommit.IsAncestor(remoteCommit)
+	if err != nil {
+		return nil, err
+	}
+	if !isAncestor {
+		return nil, s.setConflict(&gitConflictError{reason: "remote branch " + branch.Short() + " diverged from the local working copy"})
+	}
+
+	changed, err := changedPaths(headCommit, remoteCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := s.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+	var conflicting []string
+	for _, p := range changed {
+		if fileStatus, ok := status[p]; ok && (fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified) {
+			conflicting = append(conflicting, p)
+		}
+	}
+	if len(conflicting) > 0 {
+		return nil, s.setConflict(&gitConflictError{reason: "uncommitted local changes conflict with remote changes", paths: conflicting})
+	}
+
+	// Reset keeps untracked files and aborts on other uncommitted changes.
+	err = w.Reset(&git.ResetOptions{Commit: remoteRef.Hash(), Mode: git.MergeReset})
+	if errors.Is(err, git.ErrUnstagedChanges) {
+		return nil, s.setConflict(&gitConflictError{reason: "working copy has uncommitted changes"})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	grafanaStorageLogger.Info("Applied remote changes", "remote", s.settings.Remote, "branch", branch.Short(), "hash", remoteRef.Hash(), "files", len(changed))
+	s.setConflict(nil)
+	return changed, nil
+}
+
+func changedPaths(from *object.Commit, to *object.Commit) ([]string, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		if c.To.Name != "" {
+			paths = append(paths, c.To.Name)
+		}
+		if c.From.Name != "" && c.From.Name != c.To.Name {
+			paths = append(paths, c.From.Name)
+		}
+	}
+	return paths, nil
+}
+
+func (s *rootStorageGit) setConflict(err *gitConflictError) error {
+	s.conflictMu.Lock()
+	defer s.conflictMu.Unlock()
+	s.conflict = err
+	if err == nil {
+		return nil
+	}
+	grafanaStorageLogger.Warn("Unable to apply remote changes", "remote", s.settings.Remote, "error", err)
+	return err
+}
+
+// contentHash returns the git blob hash of the contents of a file, which identifies the
+// version of the file a save is based on.
+func contentHash(contents []byte) string {
+	return plumbing.ComputeHash(plumbing.BlobObject, contents).String()
+}
+
+// committedHash returns the blob hash of the file in the head commit, or an empty string
+// if the file does not exist. Must be called with s.mu held.
+func (s *rootStorageGit) committedHash(rel string) (string, error) {
+	head, err := s.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	commit, err := s.repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+	file, err := commit.File(rel)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return file.Hash.String(), nil
+}
+
+// commitAndPush saves the value as a commit authored by the user on the configured branch
+// and pushes it. Remote changes are pulled first. Saving a file which is not the version
+// the user loaded is reported as a conflict, so that the user can reload it. Without the
+// previous hash, only the changes applied by this pull are detected.
+func (s *rootStorageGit) commitAndPush(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch, err := s.localBranch()
+	if err != nil {
+		return nil, err
+	}
+	res := &WriteValueResponse{
+		Branch: branch.Short(),
+	}
+
+	rel := strings.TrimPrefix(path.Clean("/"+cmd.Path), "/")
+	if s.settings.Root != "" {
+		rel = path.Join(s.settings.Root, rel)
+	}
+
+	changed, err := s.pull(ctx)
+	if err != nil {
+		var conflict *gitConflictError
+		if errors.As(err, &conflict) {
+			res.Code = http.StatusConflict
+			res.Message = conflict.Error()
+			return res, nil
+		}
+		return nil, err
+	}
+	if cmd.PreviousHash != "" {
+		current, err := s.committedHash(rel)
+		if err != nil {
+			return nil, err
+		}
+		if current != cmd.PreviousHash {
+			res.Code = http.StatusConflict
+			res.Message = "file was changed since it was loaded, reload it before saving"
+			return res, nil
+		}
+	} else {
+		for _, p := range changed {
+			if p == rel {
+				res.Code = http.StatusConflict
+				res.Message = "file was changed in the remote repository, reload it before saving"
+				return res, nil
+			}
+		}
+	}
+
+	head, err := s.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := s.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	fpath := filepath.Join(w.Filesystem.Root(), filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(fpath), 0750); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(fpath, cmd.Body, 0600); err != nil {
+		return nil, err
+	}
+	if _, err := w.Add(rel); err != nil {
+		return nil, err
+	}
+
+	msg := cmd.Message
+	if msg == "" {
+		msg = "changes from grafana ui"
+	}
+	usr := cmd.User
+	if usr == nil {
+		usr = &user.SignedInUser{}
+	}
+	hash, err := w.Commit(msg, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  firstRealString(usr.Name, usr.Login, usr.Email, "?"),
+			Email: firstRealString(usr.Email, usr.Login, usr.Name, "?"),
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", branch, branch))},
+		Auth:       s.auth,
+	})
+	if err != nil {
+		// Drop the commit, so the local branch never has commits missing in the remote.
+		if resetErr := w.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.MergeReset}); resetErr != nil {
+			grafanaStorageLogger.Error("Error dropping commit after failed push", "hash", hash, "error", resetErr)
+		}
+		res.Code = http.StatusInternalServerError
+		if strings.Contains(err.Error(), "non-fast-forward") {
+			res.Code = http.StatusConflict
+		}
+		res.Message = "error pushing: " + err.Error()
+		return res, nil
+	}
+
+	grafanaStorageLogger.Info("Pushed commit", "remote", s.settings.Remote, "branch", branch.Short(), "hash", hash)
+	res.Code = http.StatusOK
+	res.Hash = hash.String()
+	res.Message = "pushed commit"
+	return res, nil
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// setupGitRemote creates a local bare repository with a single commit on the master branch.
+func setupGitRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git-upload-pack"); err != nil {
+		t.Skip("git is required for local repository transport")
+	}
+
+	seed := filepath.Join(t.TempDir(), "seed")
+	repo, err := git.PlainInit(seed, false)
+	require.NoError(t, err)
+	commitFile(t, repo, "dashboards/a.json", `{"title": "A"}`)
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	_, err = git.PlainClone(remote, true, &git.CloneOptions{URL: seed})
+	require.NoError(t, err)
+	return remote
+}
+
+func commitFile(t *testing.T, repo *git.Repository, path string, body string) {
+	t.Helper()
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	fpath := filepath.Join(w.Filesystem.Root(), filepath.FromSlash(path))
+	require.NoError(t, os.MkdirAll(filepath.Dir(fpath), 0750))
+	require.NoError(t, os.WriteFile(fpath, []byte(body), 0600))
+	_, err = w.Add(path)
+	require.NoError(t, err)
+	_, err = w.Commit("update "+path, &git.CommitOptions{
+		Author: &object.Signature{Name: "remote", Email: "remote@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+}
+
+// pushRemoteChange commits a file to the remote through another working copy.
+func pushRemoteChange(t *testing.T, remote string, path string, body string) {
+	t.Helper()
+	repo, err := git.PlainClone(filepath.Join(t.TempDir(), "other"), false, &git.CloneOptions{URL: remote})
+	require.NoError(t, err)
+	commitFile(t, repo, path, body)
+	require.NoError(t, repo.Push(&git.PushOptions{}))
+}
+
+func newTestBidirectionalGitStorage(t *testing.T, remote string) *rootStorageGit {
+	t.Helper()
+	s := newGitStorage(RootStorageMeta{}, RootStorageConfig{
+		Prefix: "git",
+		Git: &StorageGitConfig{
+			Remote:        remote,
+			Branch:        "master",
+			Root:          "dashboards",
+			Bidirectional: true,
+		},
+	}, filepath.Join(t.TempDir(), "cache"))
+	require.Empty(t, s.Meta().Notice)
+	require.True(t, s.Meta().Ready)
+	return s
+}
+
+func readFile(t *testing.T, s *rootStorageGit, path string) string {
+	t.Helper()
+	f, _, err := s.Store().Get(context.Background(), path, nil)
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	return string(f.Contents)
+}
+
+func TestBidirectionalGitStorage(t *testing.T) {
+	usr := &user.SignedInUser{Name: "Alice", Login: "alice", Email: "alice@example.com"}
+
+	t.Run("saves are pushed as commits of the user", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+
+		res, err := s.Write(context.Background(), &WriteValueRequest{
+			User:    usr,
+			Path:    "/b.json",
+			Body:    []byte(`{"title": "B"}`),
+			Message: "add B",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+		require.Equal(t, "master", res.Branch)
+
+		remoteRepo, err := git.PlainOpen(remote)
+		require.NoError(t, err)
+		head, err := remoteRepo.Head()
+		require.NoError(t, err)
+		require.Equal(t, res.Hash, head.Hash().String())
+		commit, err := remoteRepo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		require.Equal(t, "add B", commit.Message)
+		require.Equal(t, "Alice", commit.Author.Name)
+		require.Equal(t, "alice@example.com", commit.Author.Email)
+		file, err := commit.File("dashboards/b.json")
+		require.NoError(t, err)
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		require.Equal(t, `{"title": "B"}`, contents)
+	})
+
+	t.Run("remote changes are applied on sync", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+
+		pushRemoteChange(t, remote, "dashboards/a.json", `{"title": "A2"}`)
+		require.NoError(t, s.Sync())
+		require.Equal(t, `{"title": "A2"}`, readFile(t, s, "/a.json"))
+	})
+
+	t.Run("saving a file changed in the remote is a conflict", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+
+		pushRemoteChange(t, remote, "dashboards/a.json", `{"title": "remote"}`)
+		res, err := s.Write(context.Background(), &WriteValueRequest{
+			User: usr,
+			Path: "/a.json",
+			Body: []byte(`{"title": "local"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 409, res.Code)
+
+		// Remote version is applied, so the user can reload it and save again.
+		require.Equal(t, `{"title": "remote"}`, readFile(t, s, "/a.json"))
+		res, err = s.Write(context.Background(), &WriteValueRequest{
+			User: usr,
+			Path: "/a.json",
+			Body: []byte(`{"title": "local"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+	})
+
+	t.Run("saving a file changed since it was loaded is a conflict", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+		loaded := contentHash([]byte(readFile(t, s, "/a.json")))
+
+		// The change is applied before the save, by a sync or the save of another user.
+		pushRemoteChange(t, remote, "dashboards/a.json", `{"title": "remote"}`)
+		require.NoError(t, s.Sync())
+
+		res, err := s.Write(context.Background(), &WriteValueRequest{
+			User:         usr,
+			Path:         "/a.json",
+			Body:         []byte(`{"title": "local"}`),
+			PreviousHash: loaded,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 409, res.Code)
+
+		res, err = s.Write(context.Background(), &WriteValueRequest{
+			User:         usr,
+			Path:         "/a.json",
+			Body:         []byte(`{"title": "local"}`),
+			PreviousHash: contentHash([]byte(readFile(t, s, "/a.json"))),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+	})
+
+	t.Run("saving another file applies remote changes", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+
+		pushRemoteChange(t, remote, "dashboards/a.json", `{"title": "remote"}`)
+		res, err := s.Write(context.Background(), &WriteValueRequest{
+			User: usr,
+			Path: "/b.json",
+			Body: []byte(`{"title": "B"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+		require.Equal(t, `{"title": "remote"}`, readFile(t, s, "/a.json"))
+	})
+
+	t.Run("uncommitted local changes conflicting with remote are reported", func(t *testing.T) {
+		remote := setupGitRemote(t)
+		s := newTestBidirectionalGitStorage(t, remote)
+
+		require.NoError(t, os.WriteFile(filepath.Join(s.root, "a.json"), []byte(`{"title": "uncommitted"}`), 0600))
+		pushRemoteChange(t, remote, "dashboards/a.json", `{"title": "remote"}`)
+
+		err := s.Sync()
+		var conflict *gitConflictError
+		require.ErrorAs(t, err, &conflict)
+		require.Equal(t, []string{"dashboards/a.json"}, conflict.paths)
+		require.Len(t, s.Meta().Notice, 1)
+		require.Equal(t, `{"title": "uncommitted"}`, readFile(t, s, "/a.json"))
+
+		// Resolved once the local change is reverted.
+		require.NoError(t, os.WriteFile(filepath.Join(s.root, "a.json"), []byte(`{"title": "A"}`), 0600))
+		require.NoError(t, s.Sync())
+		require.Empty(t, s.Meta().Notice)
+		require.Equal(t, `{"title": "remote"}`, readFile(t, s, "/a.json"))
+	})
+}
+
+func TestGitStorageSaveWithReadETag(t *testing.T) {
+	remote := setupGitRemote(t)
+	s := newStandardStorageService(nil, []storageRuntime{newTestBidirectionalGitStorage(t, remote)},
+		func(orgId int64) []storageRuntime { return nil }, allowAllAuthService, cfg, nil)
+	usr := &user.SignedInUser{Name: "Alice", Login: "alice", Email: "alice@example.com"}
+
+	request := func(method string, action string, body string) *contextmodel.ReqContext {
+		req := httptest.NewRequest(method, "/api/storage/"+action+"/git/a.json", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = web.SetURLParams(req, map[string]string{"*": "git/a.json"})
+		return &contextmodel.ReqContext{
+			Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(method, httptest.NewRecorder())},
+			SignedInUser: usr,
+		}
+	}
+	read := func() string {
+		c := request(http.MethodGet, "read", "")
+		require.Equal(t, http.StatusOK, s.read(c).Status())
+		etag, err := strconv.Unquote(c.Resp.Header().Get("ETag"))
+		require.NoError(t, err)
+		return etag
+	}
+	write := func(title string, previousHash string) (*WriteValueResponse, string) {
+		body, err := json.Marshal(map[string]any{
+			"kind":         EntityTypeDashboard,
+			"body":         map[string]string{"title": title},
+			"previousHash": previousHash,
+		})
+		require.NoError(t, err)
+		c := request(http.MethodPost, "write", string(body))
+		rsp := s.doWrite(c)
+		require.Equal(t, http.StatusOK, rsp.Status())
+		res := &WriteValueResponse{}
+		require.NoError(t, json.Unmarshal(rsp.Body(), res))
+		return res, c.Resp.Header().Get("ETag")
+	}
+
+	res, etag := write("first", read())
+	require.Equal(t, http.StatusOK, res.Code, res.Message)
+
+	// The ETag of the save is the one of the saved file, so it can be saved again.
+	previousHash, err := strconv.Unquote(etag)
+	require.NoError(t, err)
+	require.Equal(t, read(), previousHash)
+	res, _ = write("second", previousHash)
+	require.Equal(t, http.StatusOK, res.Code, res.Message)
+
+	// The file was changed since the first save.
+	res, etag = write("third", previousHash)
+	require.Equal(t, http.StatusConflict, res.Code)
+	require.Empty(t, etag)
+}
@@ -26,6 +26,9 @@ type WriteValueRequest struct {
 	Message    string             `json:"message,omitempty"`
 	Title      string             `json:"title,omitempty"`    // For PRs
 	Workflow   WriteValueWorkflow `json:"workflow,omitempty"` // save | pr | push
+	// PreviousHash is the hash of the file that was loaded, from the ETag of the read response.
+	// Saves to git storage are rejected with a conflict if the file has changed since.
+	PreviousHash string `json:"previousHash,omitempty"`
 }
 
 type WriteValueResponse struct {
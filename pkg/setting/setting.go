@@ -250,6 +250,10 @@ type Cfg struct {
 
 	PluginsCDNURLTemplate    string
 	PluginLogBackendRequests bool
+	PluginProcessCgroupPath  string
+	PluginRepositoryMirror   string
+	PluginHotReload          bool
+	PluginHotReloadInterval  time.Duration
 
 	// Panels
 	DisableSanitizeHtml bool
@@ -268,6 +272,8 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	// DashboardRecentlyDeletedRetention is how long deleted dashboards and folders can be restored.
+	DashboardRecentlyDeletedRetention time.Duration
 
 	// Auth
 	LoginCookieName              string
@@ -486,6 +492,15 @@ type Cfg struct {
 	// LiveHAEngineAddress is a connection address for Live HA engine.
 	LiveHAEngineAddress  string
 	LiveHAEnginePassword string
+	// LivePipelineStorage is a type of storage for Live pipeline channel
+	// rules and write configs, "file" or "database".
+	LivePipelineStorage string
+	// LiveManagedStreamHistoryWindow is a period of time managed stream frames
+	// are kept for new subscribers. Zero value disables history.
+	LiveManagedStreamHistoryWindow time.Duration
+	// LiveManagedStreamHistoryMaxFrames is a maximum number of frames kept in
+	// history of a managed stream channel.
+	LiveManagedStreamHistoryMaxFrames int
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
@@ -524,6 +539,8 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	QueryCaching QueryCachingSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -1092,6 +1109,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.DashboardRecentlyDeletedRetention, err = gtime.ParseDuration(valueAsString(dashboards, "recently_deleted_retention", "30d"))
+	if err != nil {
+		return err
+	}
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1205,7 +1226,8 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.readSqlDataSourceSettings()
 
 	cfg.Storage = readStorageSettings(iniFile)
-	cfg.Search = readSearchSettings(iniFile)
+	cfg.Search = readSearchSettings(iniFile, cfg.DataPath)
+	cfg.QueryCaching = readQueryCachingSettings(iniFile)
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -2023,6 +2045,20 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
 	cfg.LiveHAEnginePassword = section.Key("ha_engine_password").MustString("")
+	cfg.LivePipelineStorage = section.Key("pipeline_storage").MustString("file")
+	switch cfg.LivePipelineStorage {
+	case "file", "database":
+	default:
+		return fmt.Errorf("unsupported live pipeline storage type: %s", cfg.LivePipelineStorage)
+	}
+	cfg.LiveManagedStreamHistoryWindow = section.Key("managed_stream_history_window").MustDuration(0)
+	if cfg.LiveManagedStreamHistoryWindow < 0 {
+		return fmt.Errorf("unexpected value %s for [live] managed_stream_history_window", cfg.LiveManagedStreamHistoryWindow)
+	}
+	cfg.LiveManagedStreamHistoryMaxFrames = section.Key("managed_stream_history_max_frames").MustInt(1000)
+	if cfg.LiveManagedStreamHistoryMaxFrames < 1 {
+		return fmt.Errorf("unexpected value %d for [live] managed_stream_history_max_frames", cfg.LiveManagedStreamHistoryMaxFrames)
+	}
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
@@ -2,6 +2,7 @@ package setting
 
 import (
 	"strings"
+	"time"
 
 	"gopkg.in/ini.v1"
 )
@@ -63,6 +64,13 @@ func (cfg *Cfg) readPluginSettings(iniFile *ini.File) error {
 	// Plugins CDN settings
 	cfg.PluginsCDNURLTemplate = strings.TrimRight(pluginsSection.Key("cdn_base_url").MustString(""), "/")
 	cfg.PluginLogBackendRequests = pluginsSection.Key("log_backend_requests").MustBool(false)
+	cfg.PluginProcessCgroupPath = pluginsSection.Key("process_cgroup_path").MustString("")
+	cfg.PluginRepositoryMirror = pluginsSection.Key("repository_mirror").MustString("")
+	cfg.PluginHotReload = pluginsSection.Key("hot_reload").MustBool(false)
+	cfg.PluginHotReloadInterval = pluginsSection.Key("hot_reload_interval").MustDuration(10 * time.Second)
+	if cfg.PluginHotReloadInterval < time.Second {
+		cfg.PluginHotReloadInterval = time.Second
+	}
 
 	// Installation token for managed plugins
 	cfg.PluginInstallToken = pluginsSection.Key("install_token").MustString("")
@@ -0,0 +1,29 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type QueryCachingSettings struct {
+	Enabled bool
+	// TTL is the default time to live of cached query results. Data sources can
+	// override it with the queryCachingTTL setting of their JSON data.
+	TTL time.Duration
+	// ResourcesTTL is the time to live of cached resource responses. Zero disables resource caching.
+	ResourcesTTL time.Duration
+	// MaxValueSize is the maximum size in bytes of a response that is written to the cache.
+	MaxValueSize int
+}
+
+func readQueryCachingSettings(iniFile *ini.File) QueryCachingSettings {
+	s := QueryCachingSettings{}
+
+	cachingSection := iniFile.Section("caching")
+	s.Enabled = cachingSection.Key("enabled").MustBool(true)
+	s.TTL = cachingSection.Key("ttl").MustDuration(5 * time.Minute)
+	s.ResourcesTTL = cachingSection.Key("resources_ttl").MustDuration(5 * time.Minute)
+	s.MaxValueSize = cachingSection.Key("max_value_size").MustInt(1024 * 1024)
+	return s
+}
@@ -1,6 +1,7 @@
 package setting
 
 import (
+	"path/filepath"
 	"time"
 
 	"gopkg.in/ini.v1"
@@ -10,14 +11,20 @@ type SearchSettings struct {
 	FullReindexInterval       time.Duration
 	IndexUpdateInterval       time.Duration
 	DashboardLoadingBatchSize int
+	// IndexPath is a directory where search indexes are persisted between restarts.
+	// Indexes are kept in memory only when empty.
+	IndexPath string
 }
 
-func readSearchSettings(iniFile *ini.File) SearchSettings {
+func readSearchSettings(iniFile *ini.File, dataPath string) SearchSettings {
 	s := SearchSettings{}
 
 	searchSection := iniFile.Section("search")
 	s.DashboardLoadingBatchSize = searchSection.Key("dashboard_loading_batch_size").MustInt(200)
 	s.FullReindexInterval = searchSection.Key("full_reindex_interval").MustDuration(5 * time.Minute)
 	s.IndexUpdateInterval = searchSection.Key("index_update_interval").MustDuration(10 * time.Second)
+	if searchSection.Key("persist_index").MustBool(false) {
+		s.IndexPath = filepath.Join(dataPath, "search")
+	}
 	return s
 }
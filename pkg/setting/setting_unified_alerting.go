@@ -131,9 +131,18 @@ type UnifiedAlertingStateHistorySettings struct {
 	// if one of them is set.
 	LokiBasicAuthPassword string
 	LokiBasicAuthUsername string
-	MultiPrimary          string
-	MultiSecondaries      []string
-	ExternalLabels        map[string]string
+	PrometheusQueryURL    string
+	PrometheusWriteURL    string
+	PrometheusTenantID    string
+	// PrometheusBasicAuthUsername and PrometheusBasicAuthPassword are used for basic auth
+	// if one of them is set.
+	PrometheusBasicAuthUsername string
+	PrometheusBasicAuthPassword string
+	// PrometheusMetricName is the name of the series that state transitions are written to.
+	PrometheusMetricName string
+	MultiPrimary         string
+	MultiSecondaries     []string
+	ExternalLabels       map[string]string
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -383,17 +392,23 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	stateHistory := iniFile.Section("unified_alerting.state_history")
 	stateHistoryLabels := iniFile.Section("unified_alerting.state_history.external_labels")
 	uaCfgStateHistory := UnifiedAlertingStateHistorySettings{
-		Enabled:               stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
-		Backend:               stateHistory.Key("backend").MustString("annotations"),
-		LokiRemoteURL:         stateHistory.Key("loki_remote_url").MustString(""),
-		LokiReadURL:           stateHistory.Key("loki_remote_read_url").MustString(""),
-		LokiWriteURL:          stateHistory.Key("loki_remote_write_url").MustString(""),
-		LokiTenantID:          stateHistory.Key("loki_tenant_id").MustString(""),
-		LokiBasicAuthUsername: stateHistory.Key("loki_basic_auth_username").MustString(""),
-		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
-		MultiPrimary:          stateHistory.Key("primary").MustString(""),
-		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
-		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		Enabled:                     stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
+		Backend:                     stateHistory.Key("backend").MustString("annotations"),
+		LokiRemoteURL:               stateHistory.Key("loki_remote_url").MustString(""),
+		LokiReadURL:                 stateHistory.Key("loki_remote_read_url").MustString(""),
+		LokiWriteURL:                stateHistory.Key("loki_remote_write_url").MustString(""),
+		LokiTenantID:                stateHistory.Key("loki_tenant_id").MustString(""),
+		LokiBasicAuthUsername:       stateHistory.Key("loki_basic_auth_username").MustString(""),
+		LokiBasicAuthPassword:       stateHistory.Key("loki_basic_auth_password").MustString(""),
+		PrometheusQueryURL:          stateHistory.Key("prometheus_query_url").MustString(""),
+		PrometheusWriteURL:          stateHistory.Key("prometheus_remote_write_url").MustString(""),
+		PrometheusTenantID:          stateHistory.Key("prometheus_tenant_id").MustString(""),
+		PrometheusBasicAuthUsername: stateHistory.Key("prometheus_basic_auth_username").MustString(""),
+		PrometheusBasicAuthPassword: stateHistory.Key("prometheus_basic_auth_password").MustString(""),
+		PrometheusMetricName:        stateHistory.Key("prometheus_metric_name").MustString("GRAFANA_ALERTS"),
+		MultiPrimary:                stateHistory.Key("primary").MustString(""),
+		MultiSecondaries:            splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
+		ExternalLabels:              stateHistoryLabels.KeysHash(),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -32,8 +33,9 @@ import (
 var logger = log.New("tsdb.graphite")
 
 type Service struct {
-	im     instancemgmt.InstanceManager
-	tracer tracing.Tracer
+	im              instancemgmt.InstanceManager
+	tracer          tracing.Tracer
+	resourceHandler backend.CallResourceHandler
 }
 
 const (
@@ -42,10 +44,12 @@ const (
 )
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
-	return &Service{
+	s := &Service{
 		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 		tracer: tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
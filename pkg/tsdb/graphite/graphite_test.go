@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -298,3 +300,64 @@ func (f fakeInstanceManager) Get(_ context.Context, _ backend.PluginContext) (in
 func (f fakeInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
 	return nil
 }
+
+type testInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (m testInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m testInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+func newTestService(serverURL string) *Service {
+	s := &Service{
+		im: testInstanceManager{dsInfo: datasourceInfo{HTTPClient: http.DefaultClient, URL: serverURL}},
+	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("Reports working datasource", func(t *testing.T) {
+		var target string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/graphite/render", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			target = r.PostForm.Get("target")
+			_, _ = w.Write([]byte(`[{"target": "constantLine(100)", "datapoints": [[100, 1], [100, 2]]}]`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL+"/graphite").CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status, res.Message)
+		require.Equal(t, "constantLine(100)", target)
+	})
+
+	t.Run("Reports failing request", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "401")
+	})
+
+	t.Run("Reports invalid response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html></html>`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+	})
+}
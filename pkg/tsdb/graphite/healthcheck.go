@@ -0,0 +1,54 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth renders a constant series for the last five minutes, which only
+// succeeds when Graphite is reachable and can evaluate render requests.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return healthCheckError("error getting datasource info", err), nil
+	}
+
+	formData := url.Values{
+		"target":        []string{"constantLine(100)"},
+		"from":          []string{"-5min"},
+		"until":         []string{"now"},
+		"format":        []string{"json"},
+		"maxDataPoints": []string{"300"},
+	}
+	graphiteReq, err := s.createRequest(ctx, logger, dsInfo, formData)
+	if err != nil {
+		return healthCheckError("error creating request", err), nil
+	}
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		logger.Warn("Graphite health check failed", "error", err)
+		return healthCheckError("error performing request", err), nil
+	}
+	if _, err := s.parseResponse(logger, res); err != nil {
+		logger.Warn("Graphite health check failed", "error", err)
+		return healthCheckError("error reading response", err), nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
+
+func healthCheckError(message string, err error) *backend.CheckHealthResult {
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: fmt.Sprintf("%s: %s", message, err.Error()),
+	}
+}
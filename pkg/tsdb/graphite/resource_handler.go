@@ -0,0 +1,112 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// newResourceMux exposes the Graphite metadata APIs used by the query editor. Paths
+// mirror the Graphite API and only the listed query parameters are forwarded.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleResourceReq("metrics/find", "query", "from", "until"))
+	mux.HandleFunc("/tags", s.handleResourceReq("tags", "filter", "from", "until"))
+	mux.HandleFunc("/tags/", s.handleTagValues("filter", "from", "until"))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleResourceReq("tags/autoComplete/tags", "expr", "tagPrefix", "limit", "from", "until"))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleResourceReq("tags/autoComplete/values", "expr", "tag", "valuePrefix", "limit", "from", "until"))
+	mux.HandleFunc("/functions", s.handleResourceReq("functions"))
+	return mux
+}
+
+// handleTagValues returns the values of the tag in the path, such as /tags/name.
+func (s *Service) handleTagValues(params ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		tag := strings.TrimPrefix(req.URL.Path, "/tags/")
+		if tag == "" || tag == "." || tag == ".." || strings.Contains(tag, "/") {
+			writeResponse(rw, http.StatusNotFound, []byte("not found"))
+			return
+		}
+		s.handleResourceReq("tags/"+tag, params...)(rw, req)
+	}
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) handleResourceReq(graphitePath string, params ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		logger := logger.FromContext(ctx)
+
+		if req.Method != http.MethodGet {
+			writeResponse(rw, http.StatusMethodNotAllowed, []byte("method not allowed"))
+			return
+		}
+
+		dsInfo, err := s.getDSInfo(ctx, httpadapter.PluginConfigFromContext(ctx))
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, []byte(fmt.Sprintf("error getting datasource info: %s", err)))
+			return
+		}
+
+		u, err := url.Parse(dsInfo.URL)
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, []byte(fmt.Sprintf("invalid datasource url: %s", err)))
+			return
+		}
+		u.Path = path.Join(u.Path, graphitePath)
+		query := url.Values{}
+		for _, p := range params {
+			// expressions of the autocomplete APIs are repeated
+			if v, ok := req.URL.Query()[p]; ok {
+				query[p] = v
+			}
+		}
+		u.RawQuery = query.Encode()
+
+		graphiteReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, []byte(fmt.Sprintf("failed to create request: %s", err)))
+			return
+		}
+
+		res, err := dsInfo.HTTPClient.Do(graphiteReq)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, []byte(fmt.Sprintf("error performing request: %s", err)))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "error", err)
+			}
+		}()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, []byte(fmt.Sprintf("error reading response: %s", err)))
+			return
+		}
+		if res.StatusCode/100 != 2 {
+			logger.Info("Resource request failed", "path", graphitePath, "status", res.Status, "body", string(body))
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		writeResponse(rw, res.StatusCode, body)
+	}
+}
+
+func writeResponse(rw http.ResponseWriter, code int, body []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
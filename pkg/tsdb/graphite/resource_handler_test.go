@@ -0,0 +1,105 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
+
+func callResource(t *testing.T, s *Service, method string, resourceURL string) *backend.CallResourceResponse {
+	t.Helper()
+	sender := &fakeSender{}
+	resourcePath, _, _ := strings.Cut(resourceURL, "?")
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		Method: method,
+		Path:   resourcePath,
+		URL:    resourceURL,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.res)
+	return sender.res
+}
+
+func TestResourceHandler(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/metrics/find":
+			_, _ = w.Write([]byte(`[{"text": "servers", "expandable": 1}]`))
+		case "/tags/autoComplete/values":
+			_, _ = w.Write([]byte(`["prod", "dev"]`))
+		case "/tags/env":
+			_, _ = w.Write([]byte(`{"tag": "env", "values": [{"count": 1, "value": "prod"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	s := newTestService(srv.URL)
+
+	t.Run("Forwards metrics find requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "metrics/find?query=servers.*&from=-1h&until=now&other=x")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `[{"text": "servers", "expandable": 1}]`, string(res.Body))
+		require.Len(t, requests, 1)
+		require.Equal(t, "from=-1h&query=servers.%2A&until=now", requests[0].URL.RawQuery)
+	})
+
+	t.Run("Forwards tag values requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "tags/autoComplete/values?expr=name%3Dcpu&tag=env&valuePrefix=p")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `["prod", "dev"]`, string(res.Body))
+		require.Len(t, requests, 1)
+		require.Equal(t, "name=cpu", requests[0].URL.Query().Get("expr"))
+		require.Equal(t, "env", requests[0].URL.Query().Get("tag"))
+		require.Equal(t, "p", requests[0].URL.Query().Get("valuePrefix"))
+	})
+
+	t.Run("Forwards all expressions of autocomplete requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "tags/autoComplete/values?expr=name%3Dcpu&expr=host%3Da&tag=env")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Len(t, requests, 1)
+		require.Equal(t, []string{"name=cpu", "host=a"}, requests[0].URL.Query()["expr"])
+	})
+
+	t.Run("Forwards values of a tag requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "tags/env?filter=pr&other=x")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `{"tag": "env", "values": [{"count": 1, "value": "prod"}]}`, string(res.Body))
+		require.Len(t, requests, 1)
+		require.Equal(t, "/tags/env", requests[0].URL.Path)
+		require.Equal(t, "filter=pr", requests[0].URL.RawQuery)
+	})
+
+	t.Run("Returns Graphite error status", func(t *testing.T) {
+		res := callResource(t, s, http.MethodGet, "functions")
+		require.Equal(t, http.StatusNotFound, res.Status)
+	})
+
+	t.Run("Rejects unknown paths and methods", func(t *testing.T) {
+		requests = nil
+		require.Equal(t, http.StatusNotFound, callResource(t, s, http.MethodGet, "render").Status)
+		require.Equal(t, http.StatusNotFound, callResource(t, s, http.MethodGet, "tags/env/values").Status)
+		require.Equal(t, http.StatusMethodNotAllowed, callResource(t, s, http.MethodPost, "metrics/find").Status)
+		require.Empty(t, requests)
+	})
+}
@@ -0,0 +1,71 @@
+package opentsdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// queryAnnotations returns annotations stored for the target metric, or global annotations
+// when the query has isGlobal set, in the time range of the query.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model annotationModel) backend.DataResponse {
+	tsdbQuery := OpenTsdbQuery{
+		Start: query.TimeRange.From.UnixMilli(),
+		End:   query.TimeRange.To.UnixMilli(),
+		Queries: []map[string]any{
+			{"aggregator": "sum", "metric": model.Target},
+		},
+		GlobalAnnotations: true,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	responseData, err := s.readResponse(logger, res)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	return backend.DataResponse{
+		Frames: data.Frames{annotationsToFrame(query.RefID, responseData, model.IsGlobal)},
+	}
+}
+
+func annotationsToFrame(name string, responseData []OpenTsdbResponse, global bool) *data.Frame {
+	times := []time.Time{}
+	timeEnds := []*time.Time{}
+	texts := []string{}
+
+	// As in the frontend, only annotations of the first series are used.
+	if len(responseData) > 0 {
+		annotations := responseData[0].Annotations
+		if global {
+			annotations = responseData[0].GlobalAnnotations
+		}
+		for _, a := range annotations {
+			times = append(times, time.Unix(int64(a.StartTime), 0).UTC())
+			var end *time.Time
+			if a.EndTime > 0 {
+				t := time.Unix(int64(a.EndTime), 0).UTC()
+				end = &t
+			}
+			timeEnds = append(timeEnds, end)
+			texts = append(texts, a.Description)
+		}
+	}
+
+	return data.NewFrame(name,
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+}
@@ -0,0 +1,65 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth asks OpenTSDB to suggest a metric name. Suggestions are served by every
+// OpenTSDB version Grafana supports and only need read access to the UID table.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("error getting datasource info: %s", err),
+		}, nil
+	}
+
+	if err := s.checkSuggest(ctx, dsInfo); err != nil {
+		logger.Warn("OpenTSDB health check failed", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
+
+// checkSuggest returns an error unless OpenTSDB answers a metric suggest request with a list of names.
+func (s *Service) checkSuggest(ctx context.Context, dsInfo *datasourceInfo) error {
+	query := url.Values{"type": []string{"metrics"}, "q": []string{"cpu"}, "max": []string{"1"}}
+	res, err := s.getAPI(ctx, dsInfo, "api/suggest", query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("OpenTSDB suggest request failed, status: %s", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading OpenTSDB response: %w", err)
+	}
+	var names []string
+	if err := json.Unmarshal(body, &names); err != nil {
+		return fmt.Errorf("unexpected response to OpenTSDB suggest request, check the URL is the one of an OpenTSDB server: %w", err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -26,13 +27,16 @@ import (
 var logger = log.New("tsdb.opentsdb")
 
 type Service struct {
-	im instancemgmt.InstanceManager
+	im              instancemgmt.InstanceManager
+	resourceHandler backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
-	return &Service{
+	s := &Service{
 		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
@@ -68,14 +72,34 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotation queries are sent to OpenTSDB one by one, the rest is combined into a single request
+	result := backend.NewQueryDataResponse()
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		var model annotationModel
+		if err := json.Unmarshal(query.JSON, &model); err == nil && model.FromAnnotations {
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query, model)
+			continue
+		}
+		metricQueries = append(metricQueries, query)
+	}
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	q := metricQueries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range metricQueries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -85,11 +109,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -107,11 +126,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, myRefID)
+	metricsResult, err := s.parseResponse(logger, res, myRefID)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, r := range metricsResult.Responses {
+		result.Responses[refID] = r
+	}
 	return result, nil
 }
 
@@ -141,25 +163,8 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID string) (*backend.QueryDataResponse, error) {
 	resp := backend.NewQueryDataResponse()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Warn("Failed to close response body", "err", err)
-		}
-	}()
-
-	if res.StatusCode/100 != 2 {
-		logger.Info("Request failed", "status", res.Status, "body", string(body))
-		return nil, fmt.Errorf("request failed, status: %s", res.Status)
-	}
-
-	var responseData []OpenTsdbResponse
-	err = json.Unmarshal(body, &responseData)
+	responseData, err := s.readResponse(logger, res)
 	if err != nil {
-		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
 		return nil, err
 	}
 
@@ -189,6 +194,32 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID s
 	return resp, nil
 }
 
+// readResponse checks the status and decodes the body of an api/query response.
+func (s *Service) readResponse(logger log.Logger, res *http.Response) ([]OpenTsdbResponse, error) {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var responseData []OpenTsdbResponse
+	err = json.Unmarshal(body, &responseData)
+	if err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+	return responseData, nil
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 	metric := make(map[string]any)
 
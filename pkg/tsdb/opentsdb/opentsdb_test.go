@@ -2,14 +2,18 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -277,3 +281,113 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
 }
+
+type testInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (m testInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m testInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+func newTestService(serverURL string) *Service {
+	s := &Service{
+		im: testInstanceManager{dsInfo: &datasourceInfo{HTTPClient: http.DefaultClient, URL: serverURL}},
+	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func TestQueryDataAnnotations(t *testing.T) {
+	var requests []OpenTsdbQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q OpenTsdbQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+		requests = append(requests, q)
+		_, _ = w.Write([]byte(`[{
+			"metric": "deploys",
+			"tags": {},
+			"dps": {"1405544146": 1},
+			"annotations": [{"description": "deploy", "startTime": 1405544146, "endTime": 1405544206}],
+			"globalAnnotations": [{"description": "outage", "startTime": 1405544100}]
+		}]`))
+	}))
+	t.Cleanup(srv.Close)
+	service := newTestService(srv.URL)
+
+	timeRange := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+	resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`)},
+			{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`)},
+			{RefID: "C", TimeRange: timeRange, JSON: []byte(`{"metric": "deploys", "aggregator": "sum"}`)},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	require.True(t, requests[0].GlobalAnnotations)
+	require.Equal(t, int64(1405544000000), requests[0].Start)
+	require.Equal(t, "deploys", requests[0].Queries[0]["metric"])
+	require.False(t, requests[2].GlobalAnnotations)
+
+	end := time.Unix(1405544206, 0).UTC()
+	expected := data.NewFrame("A",
+		data.NewField("time", nil, []time.Time{time.Unix(1405544146, 0).UTC()}),
+		data.NewField("timeEnd", nil, []*time.Time{&end}),
+		data.NewField("text", nil, []string{"deploy"}),
+	)
+	if diff := cmp.Diff(data.Frames{expected}, resp.Responses["A"].Frames, data.FrameTestCompareOptions()...); diff != "" {
+		t.Errorf("Result mismatch (-want +got):\n%s", diff)
+	}
+
+	require.NoError(t, resp.Responses["B"].Error)
+	require.Equal(t, "outage", resp.Responses["B"].Frames[0].Fields[2].At(0))
+	require.Nil(t, resp.Responses["B"].Frames[0].Fields[1].At(0))
+
+	require.Len(t, resp.Responses["C"].Frames, 1)
+	require.Equal(t, "deploys", resp.Responses["C"].Frames[0].Name)
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("Reports working datasource", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/suggest", r.URL.Path)
+			require.Equal(t, "metrics", r.URL.Query().Get("type"))
+			_, _ = w.Write([]byte(`["cpu.user"]`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status, res.Message)
+	})
+
+	t.Run("Reports failing request", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "502")
+	})
+
+	t.Run("Reports server not answering like OpenTSDB", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html>login</html>`))
+		}))
+		t.Cleanup(srv.Close)
+
+		res, err := newTestService(srv.URL).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "unexpected response to OpenTSDB suggest request")
+	})
+}
@@ -0,0 +1,147 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// suggestTypes are the kinds of names the OpenTSDB /api/suggest endpoint can complete.
+var suggestTypes = map[string]bool{"metrics": true, "tagk": true, "tagv": true}
+
+// newResourceMux exposes the read-only OpenTSDB endpoints used by the query editor and
+// template variables. Other endpoints, in particular the ones writing data or annotations,
+// are not reachable through the datasource.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", getOnly(s.handleSuggest))
+	mux.HandleFunc("/api/search/lookup", getOnly(s.handleLookup))
+	mux.HandleFunc("/api/aggregators", getOnly(s.handleList("api/aggregators")))
+	mux.HandleFunc("/api/config/filters", getOnly(s.handleList("api/config/filters")))
+	return mux
+}
+
+func getOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeResponse(rw, http.StatusMethodNotAllowed, []byte("method not allowed"))
+			return
+		}
+		h(rw, req)
+	}
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// handleSuggest completes metric names, tag keys or tag values starting with q.
+func (s *Service) handleSuggest(rw http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	if !suggestTypes[params.Get("type")] {
+		writeResponse(rw, http.StatusBadRequest, []byte("type must be one of metrics, tagk or tagv"))
+		return
+	}
+
+	query := url.Values{"type": []string{params.Get("type")}}
+	if q := params.Get("q"); q != "" {
+		query.Set("q", q)
+	}
+	if limit := params.Get("max"); limit != "" {
+		query.Set("max", limit)
+	}
+	s.forwardAPIRequest(rw, req, "api/suggest", query)
+}
+
+// handleLookup returns the time series matching m, a metric with optional tag filters such as
+// cpu{host=*}, from which the query editor reads the tag keys and values of a metric.
+func (s *Service) handleLookup(rw http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	if params.Get("m") == "" {
+		writeResponse(rw, http.StatusBadRequest, []byte("m is required"))
+		return
+	}
+
+	query := url.Values{"m": []string{params.Get("m")}}
+	if limit := params.Get("limit"); limit != "" {
+		query.Set("limit", limit)
+	}
+	s.forwardAPIRequest(rw, req, "api/search/lookup", query)
+}
+
+// handleList returns the aggregators or filter types supported by the OpenTSDB server.
+func (s *Service) handleList(apiPath string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		s.forwardAPIRequest(rw, req, apiPath, nil)
+	}
+}
+
+// forwardAPIRequest sends a GET request to the OpenTSDB HTTP API and writes its response,
+// which is JSON for both successful and failed requests.
+func (s *Service) forwardAPIRequest(rw http.ResponseWriter, req *http.Request, apiPath string, query url.Values) {
+	ctx := req.Context()
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, httpadapter.PluginConfigFromContext(ctx))
+	if err != nil {
+		writeResponse(rw, http.StatusInternalServerError, []byte(fmt.Sprintf("error getting datasource info: %s", err)))
+		return
+	}
+
+	res, err := s.getAPI(ctx, dsInfo, apiPath, query)
+	if err != nil {
+		writeResponse(rw, http.StatusBadGateway, []byte(err.Error()))
+		return
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		writeResponse(rw, http.StatusBadGateway, []byte(fmt.Sprintf("error reading OpenTSDB response: %s", err)))
+		return
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("OpenTSDB API request failed", "path", apiPath, "status", res.Status, "body", string(body))
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponse(rw, res.StatusCode, body)
+}
+
+// getAPI sends a GET request to an endpoint of the OpenTSDB HTTP API. The caller closes the
+// body of the response.
+func (s *Service) getAPI(ctx context.Context, dsInfo *datasourceInfo, apiPath string, query url.Values) (*http.Response, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid datasource url: %w", err)
+	}
+	u.Path = path.Join(u.Path, apiPath)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenTSDB is not reachable: %w", err)
+	}
+	return res, nil
+}
+
+func writeResponse(rw http.ResponseWriter, code int, body []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,84 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
+
+func callResource(t *testing.T, s *Service, method string, resourceURL string) *backend.CallResourceResponse {
+	t.Helper()
+	sender := &fakeSender{}
+	resourcePath, _, _ := strings.Cut(resourceURL, "?")
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		Method: method,
+		Path:   resourcePath,
+		URL:    resourceURL,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.res)
+	return sender.res
+}
+
+func TestResourceHandler(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/api/suggest":
+			_, _ = w.Write([]byte(`["cpu.user", "cpu.system"]`))
+		case "/api/search/lookup":
+			_, _ = w.Write([]byte(`{"results": [{"metric": "cpu.user", "tags": {"host": "a"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	s := newTestService(srv.URL)
+
+	t.Run("Forwards suggest requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "api/suggest?type=metrics&q=cpu&max=10&other=x")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `["cpu.user", "cpu.system"]`, string(res.Body))
+		require.Len(t, requests, 1)
+		require.Equal(t, "max=10&q=cpu&type=metrics", requests[0].URL.RawQuery)
+	})
+
+	t.Run("Forwards lookup requests", func(t *testing.T) {
+		requests = nil
+		res := callResource(t, s, http.MethodGet, "api/search/lookup?m=cpu.user%7Bhost%3D%2A%7D&limit=1000")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Len(t, requests, 1)
+		require.Equal(t, "cpu.user{host=*}", requests[0].URL.Query().Get("m"))
+		require.Equal(t, "1000", requests[0].URL.Query().Get("limit"))
+	})
+
+	t.Run("Rejects invalid suggest and lookup requests", func(t *testing.T) {
+		requests = nil
+		require.Equal(t, http.StatusBadRequest, callResource(t, s, http.MethodGet, "api/suggest?type=annotations&q=cpu").Status)
+		require.Equal(t, http.StatusBadRequest, callResource(t, s, http.MethodGet, "api/search/lookup?limit=10").Status)
+		require.Empty(t, requests)
+	})
+
+	t.Run("Rejects unknown paths and methods", func(t *testing.T) {
+		requests = nil
+		require.Equal(t, http.StatusNotFound, callResource(t, s, http.MethodGet, "api/query").Status)
+		require.Equal(t, http.StatusMethodNotAllowed, callResource(t, s, http.MethodPost, "api/suggest").Status)
+		require.Empty(t, requests)
+	})
+}
@@ -1,13 +1,31 @@
 package opentsdb
 
 type OpenTsdbQuery struct {
-	Start   int64            `json:"start"`
-	End     int64            `json:"end"`
-	Queries []map[string]any `json:"queries"`
+	Start             int64            `json:"start"`
+	End               int64            `json:"end"`
+	Queries           []map[string]any `json:"queries"`
+	GlobalAnnotations bool             `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string             `json:"metric"`
-	Tags       map[string]string  `json:"tags"`
-	DataPoints map[string]float64 `json:"dps"`
+	Metric            string               `json:"metric"`
+	Tags              map[string]string    `json:"tags"`
+	DataPoints        map[string]float64   `json:"dps"`
+	Annotations       []OpenTsdbAnnotation `json:"annotations"`
+	GlobalAnnotations []OpenTsdbAnnotation `json:"globalAnnotations"`
+}
+
+type OpenTsdbAnnotation struct {
+	TSUID       string  `json:"tsuid"`
+	Description string  `json:"description"`
+	Notes       string  `json:"notes"`
+	StartTime   float64 `json:"startTime"`
+	EndTime     float64 `json:"endTime"`
+}
+
+// annotationModel holds the fields set by the annotations editor.
+type annotationModel struct {
+	FromAnnotations bool   `json:"fromAnnotations"`
+	Target          string `json:"target"`
+	IsGlobal        bool   `json:"isGlobal"`
 }
@@ -14,6 +14,8 @@ import {
   downsamplingTypes,
   ExpressionQuery,
   ExpressionQueryType,
+  forecastMethods,
+  forecastOutputs,
   reducerModes,
   ReducerMode,
   reducerTypes,
@@ -168,6 +170,7 @@ function ExpressionPreview({ refId, model, evalData, isAlertCondition }: Express
   function renderPreview() {
     switch (model.type) {
       case ExpressionQueryType.math:
+      case ExpressionQueryType.sql:
         return <MathExpressionViewer model={model} />;
 
       case ExpressionQueryType.reduce:
@@ -182,6 +185,9 @@ function ExpressionPreview({ refId, model, evalData, isAlertCondition }: Express
       case ExpressionQueryType.threshold:
         return <ThresholdExpressionViewer model={model} />;
 
+      case ExpressionQueryType.forecast:
+        return <ForecastExpressionViewer model={model} />;
+
       default:
         return <>Expression not supported: {model.type}</>;
     }
@@ -306,7 +312,7 @@ function ReduceConditionViewer({ model }: { model: ExpressionQuery }) {
   return (
     <div className={styles.container}>
       <div className={styles.label}>Function</div>
-      <div className={styles.value}>{reducerType?.label}</div>
+      <div className={styles.value}>{reducerType?.label ?? reducer}</div>
 
       <div className={styles.label}>Input</div>
       <div className={styles.value}>{expression}</div>
@@ -367,6 +373,30 @@ const getResampleExpressionViewerStyles = (theme: GrafanaTheme2) => ({
   ...getCommonQueryStyles(theme),
 });
 
+function ForecastExpressionViewer({ model }: { model: ExpressionQuery }) {
+  const styles = useStyles2(getResampleExpressionViewerStyles);
+
+  const { expression, method, season, output } = model;
+  const methodType = forecastMethods.find((fm) => fm.value === method);
+  const outputType = forecastOutputs.find((fo) => fo.value === (output ?? 'score'));
+
+  return (
+    <div className={styles.container}>
+      <div className={styles.label}>Input</div>
+      <div className={styles.value}>{expression}</div>
+
+      <div className={styles.label}>Method</div>
+      <div className={styles.value}>{methodType?.label}</div>
+
+      <div className={styles.label}>Season</div>
+      <div className={styles.value}>{season}</div>
+
+      <div className={styles.label}>Output</div>
+      <div className={styles.value}>{outputType?.label}</div>
+    </div>
+  );
+}
+
 function ThresholdExpressionViewer({ model }: { model: ExpressionQuery }) {
   const styles = useStyles2(getExpressionViewerStyles);
 
@@ -4,9 +4,11 @@ import { DataSourceApi, QueryEditorProps, SelectableValue } from '@grafana/data'
 import { InlineField, Select } from '@grafana/ui';
 
 import { ClassicConditions } from './components/ClassicConditions';
+import { Forecast } from './components/Forecast';
 import { Math } from './components/Math';
 import { Reduce } from './components/Reduce';
 import { Resample } from './components/Resample';
+import { SqlExpr } from './components/SqlExpr';
 import { Threshold } from './components/Threshold';
 import { ExpressionQuery, ExpressionQueryType, expressionTypes } from './types';
 import { getDefaults } from './utils/expressionTypes';
@@ -27,6 +29,8 @@ function useExpressionsCache() {
       case ExpressionQueryType.reduce:
       case ExpressionQueryType.resample:
       case ExpressionQueryType.threshold:
+      case ExpressionQueryType.sql:
+      case ExpressionQueryType.forecast:
         return expressionCache.current[queryType];
       case ExpressionQueryType.classic:
         return undefined;
@@ -39,13 +43,19 @@ function useExpressionsCache() {
         expressionCache.current.math = value;
         break;
 
-      // We want to use the same value for Reduce, Resample and Threshold
+      case ExpressionQueryType.sql:
+        expressionCache.current.sql = value;
+        break;
+
+      // We want to use the same value for Reduce, Resample, Threshold and Forecast
       case ExpressionQueryType.reduce:
       case ExpressionQueryType.resample:
       case ExpressionQueryType.resample:
+      case ExpressionQueryType.forecast:
         expressionCache.current.reduce = value;
         expressionCache.current.resample = value;
         expressionCache.current.threshold = value;
+        expressionCache.current.forecast = value;
         break;
     }
   }, []);
@@ -89,6 +99,12 @@ export function ExpressionQueryEditor(props: Props) {
 
       case ExpressionQueryType.threshold:
         return <Threshold onChange={onChange} query={query} labelWidth={labelWidth} refIds={refIds} />;
+
+      case ExpressionQueryType.sql:
+        return <SqlExpr onChange={onChange} query={query} labelWidth={labelWidth} refIds={refIds} />;
+
+      case ExpressionQueryType.forecast:
+        return <Forecast onChange={onChange} query={query} labelWidth={labelWidth} refIds={refIds} />;
     }
   };
 
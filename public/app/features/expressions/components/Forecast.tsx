@@ -0,0 +1,85 @@
+import React, { ChangeEvent } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+
+import { ExpressionQuery, ForecastMethod, forecastMethods, forecastOutputs } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const Forecast = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
+  const method = forecastMethods.find((o) => o.value === query.method);
+  const output = forecastOutputs.find((o) => o.value === (query.output ?? 'score'));
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectMethod = (value: SelectableValue<ForecastMethod>) => {
+    onChange({ ...query, method: value.value });
+  };
+
+  const onSelectOutput = (value: SelectableValue<string>) => {
+    onChange({ ...query, output: value.value });
+  };
+
+  const onSeasonChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, season: event.target.value });
+  };
+
+  // Empty numbers are left out of the query, so the backend uses its defaults
+  const onNumberChange = (key: 'periods' | 'deviations' | 'alpha' | 'beta' | 'gamma') => {
+    return (event: ChangeEvent<HTMLInputElement>) => {
+      const value = event.target.valueAsNumber;
+      onChange({ ...query, [key]: isNaN(value) ? undefined : value });
+    };
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Method" labelWidth={labelWidth}>
+          <Select options={forecastMethods} value={method} onChange={onSelectMethod} width={20} />
+        </InlineField>
+        <InlineField label="Season" tooltip="The duration of a season, for example 1d for a daily pattern">
+          <Input onChange={onSeasonChange} value={query.season} width={10} />
+        </InlineField>
+        <InlineField label="Output">
+          <Select options={forecastOutputs} value={output} onChange={onSelectOutput} width={20} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Deviations" labelWidth={labelWidth} tooltip="The width of the bands, defaults to 3">
+          <Input type="number" onChange={onNumberChange('deviations')} value={query.deviations ?? ''} width={10} />
+        </InlineField>
+        {query.method === ForecastMethod.HoltWinters ? (
+          <>
+            <InlineField label="Alpha" tooltip="Smoothing factor of the level, defaults to 0.5">
+              <Input type="number" onChange={onNumberChange('alpha')} value={query.alpha ?? ''} width={10} />
+            </InlineField>
+            <InlineField label="Beta" tooltip="Smoothing factor of the trend, defaults to 0.1">
+              <Input type="number" onChange={onNumberChange('beta')} value={query.beta ?? ''} width={10} />
+            </InlineField>
+            <InlineField label="Gamma" tooltip="Smoothing factor of the seasonality, defaults to 0.3">
+              <Input type="number" onChange={onNumberChange('gamma')} value={query.gamma ?? ''} width={10} />
+            </InlineField>
+          </>
+        ) : (
+          <InlineField label="Periods" tooltip="The number of previous seasons used, defaults to 3">
+            <Input type="number" onChange={onNumberChange('periods')} value={query.periods ?? ''} width={10} />
+          </InlineField>
+        )}
+      </InlineFieldRow>
+    </>
+  );
+};
@@ -87,6 +87,26 @@ export const Math = ({ labelWidth, onChange, query, onRunQuery }: Props) => {
                       name="floor"
                       description="rounds the number down to the nearest integer value. It's able to operate on series or escalar values."
                     />
+                    <DocumentedFunction
+                      name="clamp_min, clamp_max"
+                      description="returns the greater or the lesser of each value and the second argument, e.g. clamp_min($A, 0). It's able to operate on series or scalar values."
+                    />
+                    <DocumentedFunction
+                      name="rate, delta"
+                      description="returns the per-second rate or the difference between each point of a series and the previous point."
+                    />
+                    <DocumentedFunction
+                      name="cumulative_sum"
+                      description="returns the running total of a series."
+                    />
+                    <DocumentedFunction
+                      name="moving_avg"
+                      description='returns the average of a series over a window ending at each point, e.g. moving_avg($A, "5m").'
+                    />
+                    <DocumentedFunction
+                      name="shift"
+                      description='moves each point of a series forward in time by a duration, e.g. shift($A, "1d").'
+                    />
                   </div>
                 </div>
               }
@@ -13,7 +13,10 @@ interface Props {
 }
 
 export const Reduce = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
-  const reducer = reducerTypes.find((o) => o.value === query.reducer);
+  // Other percentiles than the listed ones are entered as a custom value, such as percentile(90)
+  const reducer =
+    reducerTypes.find((o) => o.value === query.reducer) ??
+    (query.reducer ? { value: query.reducer, label: query.reducer } : undefined);
 
   const onRefIdChange = (value: SelectableValue<string>) => {
     onChange({ ...query, expression: value.value });
@@ -75,7 +78,7 @@ export const Reduce = ({ labelWidth = 'auto', onChange, refIds, query }: Props)
       </InlineFieldRow>
       <InlineFieldRow>
         <InlineField label="Function" labelWidth={labelWidth}>
-          <Select options={reducerTypes} value={reducer} onChange={onSelectReducer} width={20} />
+          <Select options={reducerTypes} value={reducer} onChange={onSelectReducer} width={20} allowCustomValue />
         </InlineField>
         <InlineField label="Mode" labelWidth={labelWidth}>
           <Select onChange={onModeChanged} options={reducerModes} value={mode} width={25} />
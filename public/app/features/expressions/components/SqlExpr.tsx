@@ -0,0 +1,41 @@
+import React from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { CodeEditor, InlineField } from '@grafana/ui';
+
+import { ExpressionQuery } from '../types';
+
+interface Props {
+  labelWidth: number | 'auto';
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const SqlExpr = ({ labelWidth, onChange, refIds, query }: Props) => {
+  const tables = refIds.map((refId) => refId.value).join(', ');
+
+  const onExpressionChange = (expression: string) => {
+    onChange({ ...query, expression });
+  };
+
+  return (
+    <InlineField
+      label="SQL"
+      labelWidth={labelWidth}
+      grow
+      tooltip={`A SELECT statement over the results of other queries or expressions. Each query is a table named after its refId: ${tables}`}
+    >
+      <CodeEditor
+        language="sql"
+        width="100%"
+        height={200}
+        showLineNumbers={true}
+        showMiniMap={false}
+        value={query.expression ?? ''}
+        onBlur={onExpressionChange}
+        onSave={onExpressionChange}
+      />
+    </InlineField>
+  );
+};
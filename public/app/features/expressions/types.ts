@@ -1,4 +1,5 @@
 import { DataQuery, ReducerID, SelectableValue } from '@grafana/data';
+import { config } from '@grafana/runtime';
 
 import { EvalFunction } from '../alerting/state/alertDef';
 
@@ -13,6 +14,8 @@ export enum ExpressionQueryType {
   resample = 'resample',
   classic = 'classic_conditions',
   threshold = 'threshold',
+  sql = 'sql',
+  forecast = 'forecast',
 }
 
 export const getExpressionLabel = (type: ExpressionQueryType) => {
@@ -27,6 +30,10 @@ export const getExpressionLabel = (type: ExpressionQueryType) => {
       return 'Classic condition';
     case ExpressionQueryType.threshold:
       return 'Threshold';
+    case ExpressionQueryType.sql:
+      return 'SQL';
+    case ExpressionQueryType.forecast:
+      return 'Forecast';
   }
 };
 
@@ -59,7 +66,28 @@ export const expressionTypes: Array<SelectableValue<ExpressionQueryType>> = [
     description:
       'Takes one or more time series returned from a query or an expression and checks if any of the series match the threshold condition.',
   },
-];
+  {
+    value: ExpressionQueryType.sql,
+    label: 'SQL',
+    description: 'Transforms and joins the results of queries or expressions with a SQL SELECT statement.',
+  },
+  {
+    value: ExpressionQueryType.forecast,
+    label: 'Forecast',
+    description:
+      'Takes one or more time series returned from a query or an expression and computes their expected values from their seasonal history, and bands around them for outlier detection.',
+  },
+].filter((expressionType) => {
+  // SQL and forecast expressions are rejected by the backend unless their feature toggle is enabled
+  switch (expressionType.value) {
+    case ExpressionQueryType.sql:
+      return Boolean(config.featureToggles?.sqlExpressions);
+    case ExpressionQueryType.forecast:
+      return Boolean(config.featureToggles?.forecastExpressions);
+    default:
+      return true;
+  }
+});
 
 export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.min, label: 'Min', description: 'Get the minimum value' },
@@ -68,6 +96,14 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'median', label: 'Median', description: 'Get the middle value' },
+  { value: 'percentile(95)', label: '95th percentile', description: 'Get the 95th percentile of the values' },
+  { value: 'percentile(99)', label: '99th percentile', description: 'Get the 99th percentile of the values' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the standard deviation of the values' },
+  { value: ReducerID.delta, label: 'Delta', description: 'Get the difference between the last and the first value' },
+  { value: 'rate', label: 'Rate', description: 'Get the per-second rate of change of the values' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of values that are not null' },
 ];
 
 export enum ReducerMode {
@@ -108,6 +144,32 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
 ];
 
+export enum ForecastMethod {
+  Median = 'median',
+  HoltWinters = 'holt_winters',
+}
+
+export const forecastMethods: Array<SelectableValue<ForecastMethod>> = [
+  {
+    value: ForecastMethod.Median,
+    label: 'Median',
+    description: 'Median of the values at the same time of the previous seasons',
+  },
+  {
+    value: ForecastMethod.HoltWinters,
+    label: 'Holt-Winters',
+    description: 'Additive Holt-Winters smoothing of the previous values, the series must be evenly spaced',
+  },
+];
+
+export const forecastOutputs: Array<SelectableValue<string>> = [
+  { value: 'score', label: 'Score', description: 'Number of deviations between each value and its baseline' },
+  { value: 'baseline', label: 'Baseline', description: 'Expected value of each point' },
+  { value: 'upper', label: 'Upper band', description: 'Upper band around the baseline' },
+  { value: 'lower', label: 'Lower band', description: 'Lower band around the baseline' },
+  { value: 'all', label: 'All', description: 'Score, baseline and bands, with a forecast label' },
+];
+
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
   { value: EvalFunction.IsAbove, label: 'Is above' },
   { value: EvalFunction.IsBelow, label: 'Is below' },
@@ -128,6 +190,14 @@ export interface ExpressionQuery extends DataQuery {
   upsampler?: string;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
+  method?: ForecastMethod;
+  season?: string;
+  periods?: number;
+  deviations?: number;
+  alpha?: number;
+  beta?: number;
+  gamma?: number;
+  output?: string;
 }
 
 export interface ExpressionQuerySettings {
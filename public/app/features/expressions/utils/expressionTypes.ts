@@ -1,7 +1,7 @@
 import { ReducerID } from '@grafana/data';
 
 import { EvalFunction } from '../../alerting/state/alertDef';
-import { ClassicCondition, ExpressionQuery, ExpressionQueryType } from '../types';
+import { ClassicCondition, ExpressionQuery, ExpressionQueryType, ForecastMethod } from '../types';
 
 export const getDefaults = (query: ExpressionQuery) => {
   switch (query.type) {
@@ -28,6 +28,23 @@ export const getDefaults = (query: ExpressionQuery) => {
       query.expression = undefined;
       break;
 
+    case ExpressionQueryType.sql:
+      query.expression = undefined;
+      query.reducer = undefined;
+      break;
+
+    case ExpressionQueryType.forecast:
+      if (!query.method) {
+        query.method = ForecastMethod.Median;
+      }
+
+      if (!query.season) {
+        query.season = '1d';
+      }
+
+      query.reducer = undefined;
+      break;
+
     case ExpressionQueryType.classic:
       if (!query.conditions) {
         query.conditions = [defaultCondition];
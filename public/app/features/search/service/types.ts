@@ -16,6 +16,7 @@ export interface SearchQuery {
   tags?: string[];
   kind?: string[];
   panel_type?: string;
+  query_text?: string;
   uid?: string[];
   facet?: FacetField[];
   explain?: boolean;
@@ -1,3 +1,5 @@
+import { lastValueFrom } from 'rxjs';
+
 import { DataFrame, dataFrameFromJSON, DataFrameJSON, getDisplayProcessor } from '@grafana/data';
 import { config, getBackendSrv } from '@grafana/runtime';
 import { backendSrv } from 'app/core/services/backend_srv';
@@ -23,11 +25,22 @@ export interface GrafanaStorage {
 }
 
 class SimpleStorage implements GrafanaStorage {
+  /** ETags of the files read or saved, sent with the next save so it fails if the file has changed since */
+  private hashes = new Map<string, string>();
+
   constructor() {}
 
   async get<T = any>(path: string): Promise<T> {
     const storagePath = `api/storage/read/${path}`.replace('//', '/');
-    return getBackendSrv().get<T>(storagePath);
+    const rsp = await lastValueFrom(getBackendSrv().fetch<T>({ url: storagePath }));
+    this.setHash(path, rsp.headers.get('ETag'));
+    return rsp.data;
+  }
+
+  private setHash(path: string, etag: string | null) {
+    if (etag) {
+      this.hashes.set(path, etag.replace(/^"|"$/g, ''));
+    }
   }
 
   async list(path: string): Promise<DataFrame | undefined> {
@@ -115,7 +128,16 @@ class SimpleStorage implements GrafanaStorage {
   }
 
   async write(path: string, options: WriteValueRequest): Promise<WriteValueResponse> {
-    return backendSrv.post<WriteValueResponse>(`/api/storage/write/${path}`, options);
+    const rsp = await lastValueFrom(
+      backendSrv.fetch<WriteValueResponse>({
+        url: `/api/storage/write/${path}`,
+        method: 'POST',
+        data: { previousHash: this.hashes.get(path), ...options },
+      })
+    );
+    // Only set when the file was saved, a pull request keeps the ETag of the loaded file
+    this.setHash(path, rsp.headers.get('ETag'));
+    return rsp.data;
   }
 
   async getConfig() {
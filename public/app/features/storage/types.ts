@@ -56,6 +56,8 @@ export interface WriteValueRequest {
   message?: string;
   title?: string;
   workflow: WorkflowID;
+  /** ETag of the loaded file, saving fails if the file has changed since */
+  previousHash?: string;
 }
 
 export interface WriteValueResponse {
@@ -1,5 +1,5 @@
 import { isArray } from 'lodash';
-import { of } from 'rxjs';
+import { of, throwError } from 'rxjs';
 import { createFetchResponse } from 'test/helpers/createFetchResponse';
 
 import { AbstractLabelMatcher, AbstractLabelOperator, getFrameDisplayName, dateTime } from '@grafana/data';
@@ -32,6 +32,7 @@ describe('graphiteDatasource', () => {
 
     const instanceSettings = {
       url: '/api/datasources/proxy/1',
+      uid: 'graphite-uid',
       name: 'graphiteProd',
       jsonData: {
         rollupIndicatorEnabled: true,
@@ -294,6 +295,8 @@ describe('graphiteDatasource', () => {
         return of(createFetchResponse(INVALID_JSON));
       });
       const funcDefs = await ctx.ds.getFuncDefs();
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/graphite-uid/resources/functions');
+      expect(fetchMock.mock.calls[0][0].responseType).toBe('text');
       expect(funcDefs).toEqual({
         testFunction: {
           category: 'Transform',
@@ -315,6 +318,17 @@ describe('graphiteDatasource', () => {
     });
   });
 
+  describe('when fetching the values of a tag', () => {
+    it('should use the backend resource of the tag', async () => {
+      fetchMock.mockImplementation(() => {
+        return of(createFetchResponse({ tag: 'server', values: [{ value: 'backend_01', count: 1 }] }));
+      });
+      const values = await ctx.ds.getTagValues({ key: 'server' });
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/graphite-uid/resources/tags/server');
+      expect(values).toEqual([{ text: 'backend_01', id: undefined }]);
+    });
+  });
+
   describe('building graphite params', () => {
     it('should return empty array if no targets', () => {
       const results = ctx.ds.buildGraphiteParams({
@@ -426,7 +440,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
     });
@@ -436,7 +450,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
@@ -446,7 +460,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/tags');
       expect(requestOptions.params?.expr).toEqual(['server=backend_01']);
       expect(results).not.toBe(null);
     });
@@ -456,7 +470,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
@@ -467,7 +481,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual(['server=~backend*']);
       expect(results).not.toBe(null);
@@ -478,7 +492,7 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual([]);
       expect(results).not.toBe(null);
@@ -489,13 +503,13 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/values');
       expect(requestOptions.params?.tag).toBe('server');
       expect(requestOptions.params?.expr).toEqual(['server=~backend*']);
       expect(results).not.toBe(null);
     });
 
-    it('/metrics/find should be POST', () => {
+    it('/metrics/find should use the backend resource', () => {
       ctx.templateSrv.init([
         {
           type: 'query',
@@ -506,11 +520,39 @@ describe('graphiteDatasource', () => {
       ctx.ds.metricFindQuery('[[foo]]').then((data: any) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.method).toEqual('POST');
-      expect(requestOptions.headers).toHaveProperty('Content-Type', 'application/x-www-form-urlencoded');
-      expect(requestOptions.data).toMatch(`query=bar`);
-      expect(requestOptions).toHaveProperty('params');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
+      expect(requestOptions.method).toEqual('GET');
+      expect(requestOptions.params).toEqual({ query: 'bar' });
+    });
+
+    it('/metrics/find should forward the time range', () => {
+      ctx.ds
+        .metricFindQuery('apps.*', {
+          range: { from: 'now-1h', to: 'now' },
+        })
+        .then((data: any) => {
+          results = data;
+        });
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
+      expect(requestOptions.params).toEqual({ query: 'apps.*', from: '-1h', until: 'now' });
+    });
+
+    it('should map /metrics/find results', async () => {
+      fetchMock.mockImplementation((options) => {
+        requestOptions = options;
+        return of(
+          createFetchResponse([
+            { text: 'backend_01', expandable: 1 },
+            { text: 'cpu', expandable: 0 },
+          ])
+        );
+      });
+
+      const data = await ctx.ds.metricFindQuery('apps.*');
+      expect(data).toEqual([
+        { text: 'backend_01', expandable: true },
+        { text: 'cpu', expandable: false },
+      ]);
     });
 
     it('should interpolate $__searchFilter with searchFilter', () => {
@@ -518,9 +560,8 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.params).toEqual({});
-      expect(requestOptions.data).toEqual('query=app.backend*');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
+      expect(requestOptions.params).toEqual({ query: 'app.backend*' });
       expect(results).not.toBe(null);
     });
 
@@ -529,9 +570,8 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.params).toEqual({});
-      expect(requestOptions.data).toEqual('query=app.*');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
+      expect(requestOptions.params).toEqual({ query: 'app.*' });
       expect(results).not.toBe(null);
     });
 
@@ -547,10 +587,10 @@ describe('graphiteDatasource', () => {
 
     it('should fetch from /metrics/find endpoint when queryType is default or query is string', async () => {
       const stringQuery = 'query';
-      ctx.ds.metricFindQuery(stringQuery).then((data) => {
+      await ctx.ds.metricFindQuery(stringQuery).then((data) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
       expect(results).not.toBe(null);
 
       const objectQuery = {
@@ -560,7 +600,7 @@ describe('graphiteDatasource', () => {
         datasource: ctx.ds,
       };
       const data = await ctx.ds.metricFindQuery(objectQuery);
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
       expect(data).toBeTruthy();
     });
 
@@ -657,6 +697,35 @@ describe('graphiteDatasource', () => {
     });
   });
 
+  describe('testDatasource', () => {
+    it('should use the backend health check', async () => {
+      let requestOptions: BackendSrvRequest | undefined;
+      fetchMock.mockImplementation((options) => {
+        requestOptions = options;
+        return of(createFetchResponse({ status: 'OK', message: 'Data source is working' }));
+      });
+
+      const result = await ctx.ds.testDatasource();
+
+      expect(requestOptions?.url).toBe('/api/datasources/uid/graphite-uid/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+
+    it('should fail with the message of the backend health check', async () => {
+      fetchMock.mockImplementation(() => {
+        return throwError(() => ({
+          status: 400,
+          data: { status: 'ERROR', message: 'error performing request: connection refused' },
+        }));
+      });
+
+      await expect(ctx.ds.testDatasource()).rejects.toMatchObject({
+        status: 'error',
+        message: 'error performing request: connection refused',
+      });
+    });
+  });
+
   describe('exporting to abstract query', () => {
     async function assertQueryExport(target: string, labelMatchers: AbstractLabelMatcher[]): Promise<void> {
       let abstractQueries = await ctx.ds.exportToAbstractQueries([
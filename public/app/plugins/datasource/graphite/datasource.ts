@@ -1,5 +1,5 @@
 import { each, indexOf, isArray, isString, map as _map } from 'lodash';
-import { lastValueFrom, merge, Observable, of, OperatorFunction, pipe, throwError } from 'rxjs';
+import { lastValueFrom, merge, Observable, of, throwError } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
 import {
@@ -9,7 +9,6 @@ import {
   DataFrame,
   DataQueryRequest,
   DataQueryResponse,
-  DataSourceApi,
   DataSourceWithQueryExportSupport,
   dateMath,
   dateTime,
@@ -21,7 +20,7 @@ import {
   toDataFrame,
   getSearchFilterScopedVar,
 } from '@grafana/data';
-import { getBackendSrv } from '@grafana/runtime';
+import { DataSourceWithBackend, getBackendSrv } from '@grafana/runtime';
 import { isVersionGtOrEq, SemVersion } from 'app/core/utils/version';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import { getRollupNotice, getRuntimeConsolidationNotice } from 'app/plugins/datasource/graphite/meta';
@@ -65,7 +64,7 @@ function convertGlobToRegEx(text: string): string {
 }
 
 export class GraphiteDatasource
-  extends DataSourceApi<GraphiteQuery, GraphiteOptions, GraphiteQueryImportConfiguration>
+  extends DataSourceWithBackend<GraphiteQuery, GraphiteOptions, GraphiteQueryImportConfiguration>
   implements DataSourceWithQueryExportSupport<GraphiteQuery>
 {
   basicAuth: string;
@@ -618,35 +617,25 @@ export class GraphiteDatasource
     requestId: string,
     range?: { from: any; until: any }
   ): Promise<MetricFindValue[]> {
-    const httpOptions: any = {
-      method: 'POST',
-      url: '/metrics/find',
-      params: {},
-      data: `query=${query}`,
-      headers: {
-        'Content-Type': 'application/x-www-form-urlencoded',
-      },
-      // for cancellations
-      requestId: requestId,
-    };
+    const params: Record<string, string> = { query };
 
     if (range) {
-      httpOptions.params.from = range.from;
-      httpOptions.params.until = range.until;
+      params.from = range.from;
+      params.until = range.until;
     }
 
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data, (metric) => {
-            return {
-              text: metric.text,
-              expandable: metric.expandable ? true : false,
-            };
-          });
-        })
-      )
-    );
+    return this.getResource('metrics/find', params, { requestId })
+      .then((results: any) => {
+        return _map(results, (metric) => {
+          return {
+            text: metric.text,
+            expandable: metric.expandable ? true : false,
+          };
+        });
+      })
+      .catch((err) => {
+        throw reduceError(err);
+      });
   }
 
   /**
@@ -691,115 +680,101 @@ export class GraphiteDatasource
 
   getTags(optionalOptions: any) {
     const options = optionalOptions || {};
-
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags',
-      // for cancellations
-      requestId: options.requestId,
-    };
+    const params: Record<string, string> = {};
 
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data, (tag) => {
-            return {
-              text: tag.tag,
-              id: tag.id,
-            };
-          });
-        })
-      )
-    );
+    return this.getResource('tags', params, { requestId: options.requestId })
+      .then((results: any) => {
+        return _map(results, (tag) => {
+          return {
+            text: tag.tag,
+            id: tag.id,
+          };
+        });
+      })
+      .catch((err) => {
+        throw reduceError(err);
+      });
   }
 
   getTagValues(options: any = {}) {
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/' + this.templateSrv.replace(options.key),
-      // for cancellations
-      requestId: options.requestId,
-    };
+    const params: Record<string, string> = {};
 
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          if (results.data && results.data.values) {
-            return _map(results.data.values, (value) => {
-              return {
-                text: value.value,
-                id: value.id,
-              };
-            });
-          } else {
-            return [];
-          }
-        })
-      )
-    );
+    const tag = encodeURIComponent(this.templateSrv.replace(options.key));
+    return this.getResource('tags/' + tag, params, { requestId: options.requestId })
+      .then((results: any) => {
+        if (results && results.values) {
+          return _map(results.values, (value) => {
+            return {
+              text: value.value,
+              id: value.id,
+            };
+          });
+        } else {
+          return [];
+        }
+      })
+      .catch((err) => {
+        throw reduceError(err);
+      });
   }
 
   getTagsAutoComplete(expressions: any[], tagPrefix: any, optionalOptions?: any) {
     const options = optionalOptions || {};
 
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/autoComplete/tags',
-      params: {
-        expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
-      },
-      // for cancellations
-      requestId: options.requestId,
+    const params: Record<string, any> = {
+      expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
     };
 
     if (tagPrefix) {
-      httpOptions.params.tagPrefix = tagPrefix;
+      params.tagPrefix = tagPrefix;
     }
     if (options.limit) {
-      httpOptions.params.limit = options.limit;
+      params.limit = options.limit;
     }
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return this.getResource('tags/autoComplete/tags', params, { requestId: options.requestId })
+      .then(toTags)
+      .catch((err) => {
+        throw reduceError(err);
+      });
   }
 
   getTagValuesAutoComplete(expressions: any[], tag: any, valuePrefix: any, optionalOptions: any) {
     const options = optionalOptions || {};
 
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/autoComplete/values',
-      params: {
-        expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
-        tag: this.templateSrv.replace((tag || '').trim()),
-      },
-      // for cancellations
-      requestId: options.requestId,
+    const params: Record<string, any> = {
+      expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
+      tag: this.templateSrv.replace((tag || '').trim()),
     };
 
     if (valuePrefix) {
-      httpOptions.params.valuePrefix = valuePrefix;
+      params.valuePrefix = valuePrefix;
     }
     if (options.limit) {
-      httpOptions.params.limit = options.limit;
+      params.limit = options.limit;
     }
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return this.getResource('tags/autoComplete/values', params, { requestId: options.requestId })
+      .then(toTags)
+      .catch((err) => {
+        throw reduceError(err);
+      });
   }
 
   getVersion(optionalOptions: any) {
@@ -850,56 +825,24 @@ export class GraphiteDatasource
       return this.funcDefsPromise;
     }
 
-    const httpOptions = {
-      method: 'GET',
-      url: '/functions',
-      // add responseType because if this is not defined,
-      // backend_srv defaults to json
-      responseType: 'text',
-    };
-
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
-          // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
-          // it was merged to master in July 2020 but it has never been released (the last Graphite
-          // release was 1.1.7 - March 2020). The bug was introduced in Graphite 1.1.7, in versions
-          // 1.1.0 - 1.1.6 /functions endpoint returns a valid JSON
-          const fixedData = JSON.parse(results.data.replace(/"default": ?Infinity/g, '"default": 1e9999'));
-          this.funcDefs = gfunc.parseFuncDefs(fixedData);
-          return this.funcDefs;
-        }),
-        catchError((error) => {
-          console.error('Fetching graphite functions error', error);
-          this.funcDefs = gfunc.getFuncDefs(this.graphiteVersion);
-          return of(this.funcDefs);
-        })
-      )
-    );
-  }
-
-  testDatasource() {
-    const query: DataQueryRequest<GraphiteQuery> = {
-      app: 'graphite',
-      interval: '10ms',
-      intervalMs: 10,
-      requestId: 'reqId',
-      scopedVars: {},
-      startTime: 0,
-      timezone: 'browser',
-      panelId: 3,
-      rangeRaw: { from: 'now-1h', to: 'now' },
-      range: {
-        from: dateTime('now-1h'),
-        to: dateTime('now'),
-        raw: { from: 'now-1h', to: 'now' },
-      },
-      targets: [{ refId: 'A', target: 'constantLine(100)' }],
-      maxDataPoints: 300,
-    };
-
-    return lastValueFrom(this.query(query)).then(() => ({ status: 'success', message: 'Data source is working' }));
+    // add responseType because if this is not defined,
+    // backend_srv defaults to json
+    return this.getResource<string>('functions', undefined, { responseType: 'text' })
+      .then((results) => {
+        // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
+        // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
+        // it was merged to master in July 2020 but it has never been released (the last Graphite
+        // release was 1.1.7 - March 2020). The bug was introduced in Graphite 1.1.7, in versions
+        // 1.1.0 - 1.1.6 /functions endpoint returns a valid JSON
+        const fixedData = JSON.parse(results.replace(/"default": ?Infinity/g, '"default": 1e9999'));
+        this.funcDefs = gfunc.parseFuncDefs(fixedData);
+        return this.funcDefs;
+      })
+      .catch((error) => {
+        console.error('Fetching graphite functions error', error);
+        this.funcDefs = gfunc.getFuncDefs(this.graphiteVersion);
+        return this.funcDefs;
+      });
   }
 
   doGraphiteRequest(options: {
@@ -1005,16 +948,12 @@ function supportsFunctionIndex(version: string): boolean {
   return isVersionGtOrEq(version, '1.1');
 }
 
-function mapToTags(): OperatorFunction<any, Array<{ text: string }>> {
-  return pipe(
-    map((results) => {
-      if (results.data) {
-        return _map(results.data, (value) => {
-          return { text: value };
-        });
-      } else {
-        return [];
-      }
-    })
-  );
+function toTags(results: any): Array<{ text: string }> {
+  if (results) {
+    return _map(results, (value) => {
+      return { text: value };
+    });
+  } else {
+    return [];
+  }
 }
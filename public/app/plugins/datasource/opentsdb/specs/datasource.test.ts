@@ -1,4 +1,4 @@
-import { of } from 'rxjs';
+import { lastValueFrom, of } from 'rxjs';
 
 import { DataQueryRequest, dateTime } from '@grafana/data';
 import { backendSrv } from 'app/core/services/backend_srv'; // will use the version in __mocks__
@@ -29,7 +29,7 @@ describe('opentsdb', () => {
     const fetchMock = jest.spyOn(backendSrv, 'fetch');
     fetchMock.mockImplementation(() => of(createFetchResponse(data)));
 
-    const instanceSettings = { url: '', jsonData: { tsdbVersion: 1 } };
+    const instanceSettings = { url: '', uid: 'opentsdb-uid', jsonData: { tsdbVersion: 1 } };
     const replace = jest.fn((value) => value);
     const templateSrv = {
       replace,
@@ -47,7 +47,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('metrics(pew)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('metrics');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('pew');
       expect(results).not.toBe(null);
@@ -59,7 +59,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_names(cpu)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu');
       expect(results).not.toBe(null);
     });
@@ -70,7 +70,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*}');
       expect(results).not.toBe(null);
     });
@@ -81,7 +81,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env}');
       expect(results).not.toBe(null);
     });
@@ -92,7 +92,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env, region=$region)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env,region=$region}');
       expect(results).not.toBe(null);
     });
@@ -103,7 +103,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagk(foo)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagk');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('foo');
       expect(results).not.toBe(null);
@@ -115,13 +115,68 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagv(bar)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagv');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('bar');
       expect(results).not.toBe(null);
     });
   });
 
+  describe('When performing metricFindQuery with results', () => {
+    it('tag_names(cpu) should return the tag keys of the lookup results', async () => {
+      const { ds } = getTestcontext({
+        data: {
+          results: [
+            { metric: 'cpu', tags: { host: 'a', env: 'prod' } },
+            { metric: 'cpu', tags: { host: 'b' } },
+          ],
+        },
+      });
+
+      const results = await ds.metricFindQuery('tag_names(cpu)');
+
+      expect(results).toEqual([{ text: 'host' }, { text: 'env' }]);
+    });
+  });
+
+  describe('When testing the datasource', () => {
+    it('should use the backend health check', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { status: 'OK', message: 'Data source is working' } });
+
+      const result = await ds.testDatasource();
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+  });
+
+  describe('When querying annotations', () => {
+    it('should send the annotation queries to the backend', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { results: { Anno: { frames: [] } } } });
+
+      await lastValueFrom(
+        ds.query({
+          requestId: 'Q1',
+          range: {
+            from: dateTime('2022-10-19T08:55:18.430Z'),
+            to: dateTime('2022-10-19T14:55:18.431Z'),
+            raw: { from: 'now-6h', to: 'now' },
+          },
+          targets: [
+            { refId: 'Anno', fromAnnotations: true, target: 'deploys', isGlobal: true },
+            { refId: 'Empty', fromAnnotations: true },
+          ],
+        } as unknown as DataQueryRequest<OpenTsdbQuery>)
+      );
+
+      expect(fetchMock).toHaveBeenCalledTimes(1);
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/ds/query?ds_type=opentsdb&requestId=Q1');
+      expect(fetchMock.mock.calls[0][0].data.queries).toMatchObject([
+        { refId: 'Anno', fromAnnotations: true, target: 'deploys', isGlobal: true },
+      ]);
+    });
+  });
+
   describe('When interpolating variables', () => {
     it('should return an empty array if no queries are provided', () => {
       const { ds } = getTestcontext();
@@ -56,6 +56,9 @@ export type GrafanaNotifierType =
   | 'pushover'
   | 'LINE'
   | 'kafka'
+  | 'http'
+  | 'mqtt'
+  | 'amqp'
   | 'wecom';
 
 export type CloudNotifierType =